@@ -5,7 +5,6 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
-	"strings"
 
 	"github.com/BurntSushi/toml"
 	"github.com/Masterminds/semver/v3"
@@ -47,6 +46,11 @@ type BuildContext struct {
 	// $CNB_STACK_ID environment variable.
 	Stack string
 
+	// Target is the os, architecture, and distribution the lifecycle selected
+	// for the build. Buildpacks can use these values to select dependencies
+	// that are compatible with the build and run images.
+	Target Target
+
 	// WorkingDir is the location of the application source code as provided by
 	// the lifecycle.
 	WorkingDir string
@@ -96,9 +100,9 @@ func Build(f BuildFunc, options ...Option) {
 	}
 
 	var (
-		layersPath   = config.args[1]
-		platformPath = config.args[2]
-		planPath     = config.args[3]
+		layersPath   = config.arg(config.layersPath, 1)
+		platformPath = config.arg(config.platformPath, 2)
+		planPath     = config.arg(config.planPath, 3)
 	)
 
 	pwd, err := os.Getwd()
@@ -114,10 +118,7 @@ func Build(f BuildFunc, options ...Option) {
 		return
 	}
 
-	cnbPath, ok := os.LookupEnv("CNB_BUILDPACK_DIR")
-	if !ok {
-		cnbPath = filepath.Clean(strings.TrimSuffix(config.args[0], filepath.Join("bin", "build")))
-	}
+	cnbPath := config.buildpackPath("build")
 
 	var buildpackInfo struct {
 		APIVersion string        `toml:"api"`
@@ -143,6 +144,7 @@ func Build(f BuildFunc, options ...Option) {
 			Path: platformPath,
 		},
 		Stack:      os.Getenv("CNB_STACK_ID"),
+		Target:     targetFromEnvironment(),
 		WorkingDir: pwd,
 		Plan:       plan,
 		Layers: Layers{
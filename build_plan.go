@@ -38,3 +38,50 @@ type BuildPlanRequirement struct {
 	// constraint for a requirement.
 	Metadata interface{} `toml:"metadata"`
 }
+
+// WithVersion returns a copy of the BuildPlanRequirement whose metadata
+// includes the "version" and "version-source" keys. An empty source is
+// omitted. Metadata that is not already a map[string]interface{} is replaced.
+func (r BuildPlanRequirement) WithVersion(version, source string) BuildPlanRequirement {
+	metadata := r.metadata()
+	metadata["version"] = version
+	if source != "" {
+		metadata["version-source"] = source
+	}
+
+	r.Metadata = metadata
+	return r
+}
+
+// ForLaunch returns a copy of the BuildPlanRequirement whose metadata marks the
+// requirement as needed at launch time.
+func (r BuildPlanRequirement) ForLaunch() BuildPlanRequirement {
+	metadata := r.metadata()
+	metadata["launch"] = true
+
+	r.Metadata = metadata
+	return r
+}
+
+// ForBuild returns a copy of the BuildPlanRequirement whose metadata marks the
+// requirement as needed at build time.
+func (r BuildPlanRequirement) ForBuild() BuildPlanRequirement {
+	metadata := r.metadata()
+	metadata["build"] = true
+
+	r.Metadata = metadata
+	return r
+}
+
+// metadata returns a copy of the requirement metadata so that requirements
+// produced by the builder methods do not share a map.
+func (r BuildPlanRequirement) metadata() map[string]interface{} {
+	metadata := map[string]interface{}{}
+	if existing, ok := r.Metadata.(map[string]interface{}); ok {
+		for key, value := range existing {
+			metadata[key] = value
+		}
+	}
+
+	return metadata
+}
@@ -0,0 +1,53 @@
+package packit_test
+
+import (
+	"testing"
+
+	"github.com/paketo-buildpacks/packit"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBuildPlan(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("BuildPlanRequirement", func() {
+		it("builds the requirement metadata", func() {
+			requirement := packit.BuildPlanRequirement{Name: "some-dependency"}.
+				WithVersion("1.2.3", "some-source").
+				ForLaunch().
+				ForBuild()
+
+			Expect(requirement).To(Equal(packit.BuildPlanRequirement{
+				Name: "some-dependency",
+				Metadata: map[string]interface{}{
+					"version":        "1.2.3",
+					"version-source": "some-source",
+					"launch":         true,
+					"build":          true,
+				},
+			}))
+		})
+
+		it("omits an empty version source", func() {
+			requirement := packit.BuildPlanRequirement{Name: "some-dependency"}.WithVersion("1.2.3", "")
+
+			Expect(requirement.Metadata).To(Equal(map[string]interface{}{
+				"version": "1.2.3",
+			}))
+		})
+
+		it("keeps existing metadata without modifying it", func() {
+			metadata := map[string]interface{}{"some-key": "some-value"}
+
+			requirement := packit.BuildPlanRequirement{Name: "some-dependency", Metadata: metadata}.ForLaunch()
+
+			Expect(requirement.Metadata).To(Equal(map[string]interface{}{
+				"some-key": "some-value",
+				"launch":   true,
+			}))
+			Expect(metadata).To(Equal(map[string]interface{}{"some-key": "some-value"}))
+		})
+	})
+}
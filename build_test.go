@@ -135,6 +135,122 @@ api = "0.5"
 			},
 		}))
 	})
+
+	context("when the target environment variables are set", func() {
+		it.Before(func() {
+			Expect(os.Setenv("CNB_TARGET_OS", "linux")).To(Succeed())
+			Expect(os.Setenv("CNB_TARGET_ARCH", "arm64")).To(Succeed())
+			Expect(os.Setenv("CNB_TARGET_ARCH_VARIANT", "v8")).To(Succeed())
+			Expect(os.Setenv("CNB_TARGET_DISTRO_NAME", "ubuntu")).To(Succeed())
+			Expect(os.Setenv("CNB_TARGET_DISTRO_VERSION", "22.04")).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("CNB_TARGET_OS")).To(Succeed())
+			Expect(os.Unsetenv("CNB_TARGET_ARCH")).To(Succeed())
+			Expect(os.Unsetenv("CNB_TARGET_ARCH_VARIANT")).To(Succeed())
+			Expect(os.Unsetenv("CNB_TARGET_DISTRO_NAME")).To(Succeed())
+			Expect(os.Unsetenv("CNB_TARGET_DISTRO_VERSION")).To(Succeed())
+		})
+
+		it("provides the target in the build context", func() {
+			var context packit.BuildContext
+
+			packit.Build(func(ctx packit.BuildContext) (packit.BuildResult, error) {
+				context = ctx
+
+				return packit.BuildResult{}, nil
+			}, packit.WithArgs([]string{binaryPath, layersDir, platformDir, planPath}))
+
+			Expect(context.Target).To(Equal(packit.Target{
+				OS:          "linux",
+				Arch:        "arm64",
+				ArchVariant: "v8",
+				Distro: packit.TargetDistro{
+					Name:    "ubuntu",
+					Version: "22.04",
+				},
+			}))
+		})
+	})
+
+	context("when the paths are given as options", func() {
+		it("provides the build context to the given BuildFunc and persists the results to those paths", func() {
+			var context packit.BuildContext
+
+			packit.Build(func(ctx packit.BuildContext) (packit.BuildResult, error) {
+				context = ctx
+
+				return packit.BuildResult{
+					Launch: packit.LaunchMetadata{
+						Processes: []packit.Process{
+							{
+								Type:    "some-type",
+								Command: "some-command",
+							},
+						},
+					},
+				}, nil
+			},
+				packit.WithCNBPath(cnbDir),
+				packit.WithLayersPath(layersDir),
+				packit.WithPlatformPath(platformDir),
+				packit.WithPlanPath(planPath),
+				packit.WithExitHandler(exitHandler),
+			)
+
+			Expect(exitHandler.ErrorCall.CallCount).To(Equal(0))
+
+			Expect(context.CNBPath).To(Equal(cnbDir))
+			Expect(context.Layers.Path).To(Equal(layersDir))
+			Expect(context.Platform.Path).To(Equal(platformDir))
+			Expect(context.Plan.Entries).To(HaveLen(1))
+			Expect(context.BuildpackInfo.ID).To(Equal("some-id"))
+
+			contents, err := os.ReadFile(filepath.Join(layersDir, "launch.toml"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(string(contents)).To(MatchTOML(`
+				[[processes]]
+					type = "some-type"
+					command = "some-command"
+					direct = false
+			`))
+		})
+
+		context("when a TOML writer is given", func() {
+			it("writes the results using that writer", func() {
+				tomlWriter := &fakes.TOMLWriter{}
+
+				packit.Build(func(ctx packit.BuildContext) (packit.BuildResult, error) {
+					return packit.BuildResult{
+						Build: packit.BuildMetadata{
+							Unmet: []packit.UnmetEntry{
+								{Name: "some-entry"},
+							},
+						},
+					}, nil
+				},
+					packit.WithCNBPath(cnbDir),
+					packit.WithLayersPath(layersDir),
+					packit.WithPlatformPath(platformDir),
+					packit.WithPlanPath(planPath),
+					packit.WithTOMLWriter(tomlWriter),
+				)
+
+				Expect(tomlWriter.WriteCall.CallCount).To(Equal(1))
+				Expect(tomlWriter.WriteCall.Receives.Path).To(Equal(filepath.Join(layersDir, "build.toml")))
+				Expect(tomlWriter.WriteCall.Receives.Value).To(Equal(packit.BuildMetadata{
+					Unmet: []packit.UnmetEntry{
+						{Name: "some-entry"},
+					},
+				}))
+
+				Expect(filepath.Join(layersDir, "build.toml")).NotTo(BeAnExistingFile())
+			})
+		})
+	})
+
 	context("when there are updates to the build plan", func() {
 		context("when the api version is less than 0.5", func() {
 
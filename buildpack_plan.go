@@ -23,3 +23,72 @@ type BuildpackPlanEntry struct {
 	// constraint for a requirement.
 	Metadata map[string]interface{} `toml:"metadata"`
 }
+
+// Find returns the first entry in the BuildpackPlan with the given name. The
+// boolean return value reports whether such an entry was found.
+func (p BuildpackPlan) Find(name string) (BuildpackPlanEntry, bool) {
+	for _, entry := range p.Entries {
+		if entry.Name == name {
+			return entry, true
+		}
+	}
+
+	return BuildpackPlanEntry{}, false
+}
+
+// SetMetadata returns a copy of the BuildpackPlan where the metadata of every
+// entry with the given name has been replaced by the given metadata. Entries
+// with other names are left intact.
+func (p BuildpackPlan) SetMetadata(name string, metadata map[string]interface{}) BuildpackPlan {
+	return p.update(name, func(map[string]interface{}) map[string]interface{} {
+		return copyMetadata(metadata)
+	})
+}
+
+// MergeMetadata returns a copy of the BuildpackPlan where the given metadata
+// has been merged into the metadata of every entry with the given name. Keys
+// that are already present in an entry are overwritten. Entries with other
+// names are left intact. This can be used to record the resolved version of a
+// dependency for buildpacks further down the order, for example:
+//
+//	plan = plan.MergeMetadata("node", map[string]interface{}{
+//	  "version":        "16.3.0",
+//	  "version-source": "buildpack.yml",
+//	})
+func (p BuildpackPlan) MergeMetadata(name string, metadata map[string]interface{}) BuildpackPlan {
+	return p.update(name, func(existing map[string]interface{}) map[string]interface{} {
+		merged := copyMetadata(existing)
+		for key, value := range metadata {
+			merged[key] = value
+		}
+
+		return merged
+	})
+}
+
+func (p BuildpackPlan) update(name string, fn func(map[string]interface{}) map[string]interface{}) BuildpackPlan {
+	if p.Entries == nil {
+		return p
+	}
+
+	entries := make([]BuildpackPlanEntry, len(p.Entries))
+	for i, entry := range p.Entries {
+		if entry.Name == name {
+			entry.Metadata = fn(entry.Metadata)
+		}
+
+		entries[i] = entry
+	}
+
+	p.Entries = entries
+	return p
+}
+
+func copyMetadata(metadata map[string]interface{}) map[string]interface{} {
+	copied := map[string]interface{}{}
+	for key, value := range metadata {
+		copied[key] = value
+	}
+
+	return copied
+}
@@ -0,0 +1,139 @@
+package packit_test
+
+import (
+	"testing"
+
+	"github.com/paketo-buildpacks/packit"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testBuildpackPlan(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		plan packit.BuildpackPlan
+	)
+
+	it.Before(func() {
+		plan = packit.BuildpackPlan{
+			Entries: []packit.BuildpackPlanEntry{
+				{
+					Name: "some-entry",
+					Metadata: map[string]interface{}{
+						"version": "1.2.3",
+						"launch":  true,
+					},
+				},
+				{
+					Name: "other-entry",
+					Metadata: map[string]interface{}{
+						"version": "4.5.6",
+					},
+				},
+			},
+		}
+	})
+
+	context("Find", func() {
+		it("returns the entry with the given name", func() {
+			entry, ok := plan.Find("other-entry")
+			Expect(ok).To(BeTrue())
+			Expect(entry).To(Equal(packit.BuildpackPlanEntry{
+				Name: "other-entry",
+				Metadata: map[string]interface{}{
+					"version": "4.5.6",
+				},
+			}))
+		})
+
+		context("when there is no entry with the given name", func() {
+			it("reports that the entry was not found", func() {
+				_, ok := plan.Find("missing-entry")
+				Expect(ok).To(BeFalse())
+			})
+		})
+	})
+
+	context("SetMetadata", func() {
+		it("replaces the metadata of the named entry and leaves the others intact", func() {
+			updated := plan.SetMetadata("some-entry", map[string]interface{}{
+				"version-source": "buildpack.yml",
+			})
+
+			Expect(updated.Entries).To(Equal([]packit.BuildpackPlanEntry{
+				{
+					Name: "some-entry",
+					Metadata: map[string]interface{}{
+						"version-source": "buildpack.yml",
+					},
+				},
+				{
+					Name: "other-entry",
+					Metadata: map[string]interface{}{
+						"version": "4.5.6",
+					},
+				},
+			}))
+
+			Expect(plan.Entries[0].Metadata).To(Equal(map[string]interface{}{
+				"version": "1.2.3",
+				"launch":  true,
+			}))
+		})
+	})
+
+	context("MergeMetadata", func() {
+		it("merges into the metadata of the named entry and leaves the others intact", func() {
+			updated := plan.MergeMetadata("some-entry", map[string]interface{}{
+				"version":        "1.2.4",
+				"version-source": "buildpack.yml",
+			})
+
+			Expect(updated.Entries).To(Equal([]packit.BuildpackPlanEntry{
+				{
+					Name: "some-entry",
+					Metadata: map[string]interface{}{
+						"version":        "1.2.4",
+						"version-source": "buildpack.yml",
+						"launch":         true,
+					},
+				},
+				{
+					Name: "other-entry",
+					Metadata: map[string]interface{}{
+						"version": "4.5.6",
+					},
+				},
+			}))
+
+			Expect(plan.Entries[0].Metadata).To(Equal(map[string]interface{}{
+				"version": "1.2.3",
+				"launch":  true,
+			}))
+		})
+
+		context("when the entry has no metadata", func() {
+			it.Before(func() {
+				plan.Entries[1].Metadata = nil
+			})
+
+			it("creates the metadata", func() {
+				updated := plan.MergeMetadata("other-entry", map[string]interface{}{
+					"version": "4.5.6",
+				})
+
+				Expect(updated.Entries[1].Metadata).To(Equal(map[string]interface{}{
+					"version": "4.5.6",
+				}))
+			})
+		})
+
+		context("when there is no entry with the given name", func() {
+			it("returns the plan unchanged", func() {
+				Expect(plan.MergeMetadata("missing-entry", map[string]interface{}{"version": "7.8.9"})).To(Equal(plan))
+			})
+		})
+	})
+}
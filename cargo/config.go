@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/BurntSushi/toml"
+	"github.com/Masterminds/semver/v3"
 )
 
 type Config struct {
@@ -23,11 +26,12 @@ type ConfigStack struct {
 }
 
 type ConfigBuildpack struct {
-	ID       string                   `toml:"id"                 json:"id,omitempty"`
-	Name     string                   `toml:"name"               json:"name,omitempty"`
-	Version  string                   `toml:"version"            json:"version,omitempty"`
-	Homepage string                   `toml:"homepage,omitempty" json:"homepage,omitempty"`
-	Licenses []ConfigBuildpackLicense `toml:"licenses,omitempty" json:"licenses,omitempty"`
+	ID          string                   `toml:"id"                     json:"id,omitempty"`
+	Name        string                   `toml:"name"                   json:"name,omitempty"`
+	Version     string                   `toml:"version"                json:"version,omitempty"`
+	Homepage    string                   `toml:"homepage,omitempty"     json:"homepage,omitempty"`
+	Licenses    []ConfigBuildpackLicense `toml:"licenses,omitempty"     json:"licenses,omitempty"`
+	SBOMFormats []string                 `toml:"sbom-formats,omitempty" json:"sbom-formats,omitempty"`
 
 	SHA256 string `toml:"-" json:"-"`
 }
@@ -41,23 +45,47 @@ type ConfigMetadata struct {
 	IncludeFiles          []string                             `toml:"include-files"              json:"include-files,omitempty"`
 	PrePackage            string                               `toml:"pre-package"                json:"pre-package,omitempty"`
 	DefaultVersions       map[string]string                    `toml:"default-versions"           json:"default-versions,omitempty"`
+	StackDefaultVersions  map[string]map[string]string         `toml:"stack-default-versions"     json:"stack-default-versions,omitempty"`
 	Dependencies          []ConfigMetadataDependency           `toml:"dependencies"               json:"dependencies,omitempty"`
 	DependencyConstraints []ConfigMetadataDependencyConstraint `toml:"dependency-constraints"     json:"dependency-constraints,omitempty"`
 	Unstructured          map[string]interface{}               `toml:"-"                          json:"-"`
 }
 
 type ConfigMetadataDependency struct {
+	Arch            string     `toml:"arch"             json:"arch,omitempty"`
+	ChecksumURI     string     `toml:"checksum_uri"     json:"checksum_uri,omitempty"`
+	Checksums       []string   `toml:"checksums"        json:"checksums,omitempty"`
 	CPE             string     `toml:"cpe"              json:"cpe,omitempty"`
 	DeprecationDate *time.Time `toml:"deprecation_date" json:"deprecation_date,omitempty"`
+	FileCount       int        `toml:"file-count"       json:"file-count,omitempty"`
+	Format          string     `toml:"format"           json:"format,omitempty"`
 	ID              string     `toml:"id"               json:"id,omitempty"`
 	Licenses        []string   `toml:"licenses"         json:"licenses,omitempty"`
 	Name            string     `toml:"name"             json:"name,omitempty"`
+	OS              string     `toml:"os"               json:"os,omitempty"`
+	PURL            string     `toml:"purl"             json:"purl,omitempty"`
 	SHA256          string     `toml:"sha256"           json:"sha256,omitempty"`
 	Source          string     `toml:"source"           json:"source,omitempty"`
 	SourceSHA256    string     `toml:"source_sha256"    json:"source_sha256,omitempty"`
 	Stacks          []string   `toml:"stacks"           json:"stacks,omitempty"`
+	StripComponents int        `toml:"strip-components" json:"strip-components,omitempty"`
+	TargetPath      string     `toml:"target-path"      json:"target-path,omitempty"`
 	URI             string     `toml:"uri"              json:"uri,omitempty"`
 	Version         string     `toml:"version"          json:"version,omitempty"`
+
+	// CPEs holds the cpe key when it is given as a list of strings rather than
+	// a single string. When set, it is encoded in place of CPE.
+	CPEs []string `toml:"-" json:"-"`
+
+	// LicenseTables holds the licenses key when it is given as [[licenses]]
+	// tables rather than a list of license type strings. When set, it is
+	// encoded in place of Licenses.
+	LicenseTables []ConfigMetadataDependencyLicense `toml:"-" json:"-"`
+}
+
+type ConfigMetadataDependencyLicense struct {
+	Type string `toml:"type" json:"type,omitempty"`
+	URI  string `toml:"uri"  json:"uri,omitempty"`
 }
 
 type ConfigMetadataDependencyConstraint struct {
@@ -93,6 +121,11 @@ func EncodeConfig(writer io.Writer, config Config) error {
 		return err
 	}
 
+	c, err = convertDependencyIntegers(config.Metadata.Dependencies, c)
+	if err != nil {
+		return err
+	}
+
 	return toml.NewEncoder(writer).Encode(c)
 }
 
@@ -138,6 +171,10 @@ func (m ConfigMetadata) MarshalJSON() ([]byte, error) {
 		metadata["default-versions"] = m.DefaultVersions
 	}
 
+	if len(m.StackDefaultVersions) > 0 {
+		metadata["stack-default-versions"] = m.StackDefaultVersions
+	}
+
 	return json.Marshal(metadata)
 }
 
@@ -188,6 +225,14 @@ func (m *ConfigMetadata) UnmarshalJSON(data []byte) error {
 		delete(metadata, "default-versions")
 	}
 
+	if stackDefaultVersions, ok := metadata["stack-default-versions"]; ok {
+		err = json.Unmarshal(stackDefaultVersions, &m.StackDefaultVersions)
+		if err != nil {
+			return err
+		}
+		delete(metadata, "stack-default-versions")
+	}
+
 	if len(metadata) > 0 {
 		m.Unstructured = map[string]interface{}{}
 		for key, value := range metadata {
@@ -198,6 +243,217 @@ func (m *ConfigMetadata) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// MarshalJSON encodes the dependency, writing the cpe key as a list when CPEs
+// is set and the licenses key as tables when LicenseTables is set.
+func (cd ConfigMetadataDependency) MarshalJSON() ([]byte, error) {
+	type dependency ConfigMetadataDependency
+
+	entry := struct {
+		dependency
+		CPE      interface{} `json:"cpe,omitempty"`
+		Licenses interface{} `json:"licenses,omitempty"`
+	}{dependency: dependency(cd)}
+
+	if len(cd.CPEs) > 0 {
+		entry.CPE = cd.CPEs
+	} else if cd.CPE != "" {
+		entry.CPE = cd.CPE
+	}
+
+	if len(cd.LicenseTables) > 0 {
+		entry.Licenses = cd.LicenseTables
+	} else if len(cd.Licenses) > 0 {
+		entry.Licenses = cd.Licenses
+	}
+
+	return json.Marshal(entry)
+}
+
+// UnmarshalJSON decodes the dependency, accepting the cpe key as either a
+// single string or a list of strings, and the licenses key as either a list of
+// license type strings or a list of tables.
+func (cd *ConfigMetadataDependency) UnmarshalJSON(data []byte) error {
+	type dependency ConfigMetadataDependency
+
+	var entry struct {
+		dependency
+		CPE      json.RawMessage `json:"cpe"`
+		Licenses json.RawMessage `json:"licenses"`
+	}
+	err := json.Unmarshal(data, &entry)
+	if err != nil {
+		return err
+	}
+
+	*cd = ConfigMetadataDependency(entry.dependency)
+
+	if len(entry.CPE) > 0 && string(entry.CPE) != "null" {
+		if entry.CPE[0] == '[' {
+			err = json.Unmarshal(entry.CPE, &cd.CPEs)
+		} else {
+			err = json.Unmarshal(entry.CPE, &cd.CPE)
+		}
+		if err != nil {
+			return fmt.Errorf("dependency %q has a malformed cpe: %w", cd.ID, err)
+		}
+	}
+
+	if len(entry.Licenses) > 0 && string(entry.Licenses) != "null" {
+		var licenses []json.RawMessage
+		err = json.Unmarshal(entry.Licenses, &licenses)
+		if err != nil {
+			return fmt.Errorf("dependency %q has malformed licenses: %w", cd.ID, err)
+		}
+
+		for _, l := range licenses {
+			if len(l) > 0 && l[0] == '{' {
+				var license ConfigMetadataDependencyLicense
+				err = json.Unmarshal(l, &license)
+				if err != nil {
+					return fmt.Errorf("dependency %q has a malformed license: %w", cd.ID, err)
+				}
+
+				cd.LicenseTables = append(cd.LicenseTables, license)
+				continue
+			}
+
+			var license string
+			err = json.Unmarshal(l, &license)
+			if err != nil {
+				return fmt.Errorf("dependency %q has a malformed license: %w", cd.ID, err)
+			}
+
+			cd.Licenses = append(cd.Licenses, license)
+		}
+	}
+
+	return nil
+}
+
+// ValidateDefaultVersions checks each entry of the metadata.default-versions
+// table against the declared dependencies. It returns an error for every
+// default that refers to an id with no dependencies, has an invalid version
+// constraint, or has a constraint that matches none of the versions for that
+// id. The errors are ordered by dependency id.
+func (c Config) ValidateDefaultVersions() []error {
+	var ids []string
+	for id := range c.Metadata.DefaultVersions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var errs []error
+	for _, id := range ids {
+		defaultVersion := c.Metadata.DefaultVersions[id]
+
+		constraint, err := semver.NewConstraint(defaultVersion)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("default-version for %q has an invalid constraint %q: %w", id, defaultVersion, err))
+			continue
+		}
+
+		var found, matched bool
+		for _, dependency := range c.Metadata.Dependencies {
+			if dependency.ID != id {
+				continue
+			}
+			found = true
+
+			version, err := semver.NewVersion(dependency.Version)
+			if err != nil {
+				continue
+			}
+
+			if constraint.Check(version) {
+				matched = true
+				break
+			}
+		}
+
+		switch {
+		case !found:
+			errs = append(errs, fmt.Errorf("default-version for %q references an id with no dependencies", id))
+		case !matched:
+			errs = append(errs, fmt.Errorf("default-version for %q matches no dependency: constraint %q", id, defaultVersion))
+		}
+	}
+
+	return errs
+}
+
+// ValidateStacks checks the stacks of each entry in metadata.dependencies
+// against the stacks declared by the buildpack. It returns an error for every
+// dependency stack that the buildpack does not declare. A declared stack of "*"
+// permits any dependency stack, and a dependency stack of "*" is always
+// permitted. The errors are ordered as the dependencies are declared.
+func (c Config) ValidateStacks() []error {
+	declared := map[string]bool{}
+	for _, stack := range c.Stacks {
+		declared[stack.ID] = true
+	}
+
+	if declared["*"] {
+		return nil
+	}
+
+	var errs []error
+	for _, dependency := range c.Metadata.Dependencies {
+		for _, stack := range dependency.Stacks {
+			if stack == "*" || declared[stack] {
+				continue
+			}
+
+			errs = append(errs, fmt.Errorf("dependency %q (%s) references undeclared stack %q", dependency.ID, dependency.Version, stack))
+		}
+	}
+
+	return errs
+}
+
+// SortDependencies orders the entries of metadata.dependencies by id, then by
+// semantic version, then by stacks, so that encoding the config produces a
+// stable ordering. Versions that are not valid semantic versions are compared
+// lexically.
+func (c *Config) SortDependencies() {
+	dependencies := c.Metadata.Dependencies
+	sort.SliceStable(dependencies, func(i, j int) bool {
+		if dependencies[i].ID != dependencies[j].ID {
+			return dependencies[i].ID < dependencies[j].ID
+		}
+
+		if dependencies[i].Version != dependencies[j].Version {
+			iVersion, iErr := semver.NewVersion(dependencies[i].Version)
+			jVersion, jErr := semver.NewVersion(dependencies[j].Version)
+			if iErr == nil && jErr == nil && !iVersion.Equal(jVersion) {
+				return iVersion.LessThan(jVersion)
+			}
+
+			return dependencies[i].Version < dependencies[j].Version
+		}
+
+		return strings.Join(dependencies[i].Stacks, ",") < strings.Join(dependencies[j].Stacks, ",")
+	})
+}
+
+// ValidateSBOMFormats checks each of the emitted SBOM media types against the
+// sbom-formats declared by the buildpack. It returns an error for every
+// emitted format that the buildpack does not declare.
+func (c Config) ValidateSBOMFormats(emitted ...string) []error {
+	declared := map[string]bool{}
+	for _, format := range c.Buildpack.SBOMFormats {
+		declared[format] = true
+	}
+
+	var errs []error
+	for _, format := range emitted {
+		if !declared[format] {
+			errs = append(errs, fmt.Errorf("sbom format %q is not declared in buildpack.sbom-formats", format))
+		}
+	}
+
+	return errs
+}
+
 func (cd ConfigMetadataDependency) HasStack(stack string) bool {
 	for _, s := range cd.Stacks {
 		if s == stack {
@@ -237,3 +493,37 @@ func convertPatches(constraints []ConfigMetadataDependencyConstraint, c map[stri
 	}
 	return c, nil
 }
+
+// convertDependencyIntegers converts the integer fields of each dependency,
+// which Unmarshal stores as float64 types, back into ints so that they are not
+// written to the final toml with an unnecessary decimal point.
+func convertDependencyIntegers(dependencies []ConfigMetadataDependency, c map[string]interface{}) (map[string]interface{}, error) {
+	if len(dependencies) == 0 {
+		return c, nil
+	}
+
+	metadata, ok := c["metadata"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("failure to assert type: unexpected data in metadata")
+	}
+
+	entries, ok := metadata["dependencies"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("failure to assert type: unexpected data in dependencies")
+	}
+
+	for _, entry := range entries {
+		dependency, ok := entry.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("failure to assert type: unexpected data in dependency")
+		}
+
+		for _, key := range []string{"strip-components", "file-count"} {
+			if value, ok := dependency[key].(float64); ok {
+				dependency[key] = int(value)
+			}
+		}
+	}
+
+	return c, nil
+}
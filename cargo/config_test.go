@@ -43,6 +43,7 @@ func testConfig(t *testing.T, context spec.G, it spec.S) {
 							URI:  "some-license-uri",
 						},
 					},
+					SBOMFormats: []string{"application/vnd.cyclonedx+json", "application/spdx+json"},
 				},
 				Stacks: []cargo.ConfigStack{
 					{
@@ -110,6 +111,7 @@ api = "0.2"
 	name = "some-buildpack-name"
 	version = "some-buildpack-version"
 	homepage = "some-homepage-link"
+	sbom-formats = ["application/vnd.cyclonedx+json", "application/spdx+json"]
 
 [[buildpack.licenses]]
   type = "some-license-type"
@@ -162,6 +164,131 @@ api = "0.2"
 `))
 		})
 
+		context("when dependencies declare an arch and os", func() {
+			it("round-trips the fields and omits them when unset", func() {
+				config := cargo.Config{
+					API: "0.2",
+					Buildpack: cargo.ConfigBuildpack{
+						ID: "some-buildpack-id",
+					},
+					Metadata: cargo.ConfigMetadata{
+						Dependencies: []cargo.ConfigMetadataDependency{
+							{
+								Arch:            "arm64",
+								FileCount:       3,
+								ID:              "some-dependency",
+								OS:              "linux",
+								SHA256:          "shasum",
+								Stacks:          []string{"some-stack"},
+								StripComponents: 1,
+								URI:             "http://some-url",
+								Version:         "1.2.3",
+							},
+							{
+								ID:      "other-dependency",
+								SHA256:  "other-shasum",
+								Stacks:  []string{"some-stack"},
+								URI:     "http://other-url",
+								Version: "4.5.6",
+							},
+						},
+					},
+				}
+
+				err := cargo.EncodeConfig(buffer, config)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(buffer.String()).To(MatchTOML(`
+api = "0.2"
+
+[buildpack]
+	id = "some-buildpack-id"
+
+[metadata]
+
+[[metadata.dependencies]]
+	arch = "arm64"
+	file-count = 3
+	id = "some-dependency"
+	os = "linux"
+	sha256 = "shasum"
+	stacks = ["some-stack"]
+	strip-components = 1
+	uri = "http://some-url"
+	version = "1.2.3"
+
+[[metadata.dependencies]]
+	id = "other-dependency"
+	sha256 = "other-shasum"
+	stacks = ["some-stack"]
+	uri = "http://other-url"
+	version = "4.5.6"
+`))
+
+				var decoded cargo.Config
+				err = cargo.DecodeConfig(bytes.NewBuffer(buffer.Bytes()), &decoded)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(decoded.Metadata.Dependencies).To(Equal(config.Metadata.Dependencies))
+			})
+		})
+
+		context("when dependencies declare a cpe list and license tables", func() {
+			it("round-trips the fields", func() {
+				config := cargo.Config{
+					API: "0.2",
+					Buildpack: cargo.ConfigBuildpack{
+						ID: "some-buildpack-id",
+					},
+					Metadata: cargo.ConfigMetadata{
+						Dependencies: []cargo.ConfigMetadataDependency{
+							{
+								CPEs:    []string{"some-cpe", "other-cpe"},
+								ID:      "some-dependency",
+								SHA256:  "shasum",
+								Stacks:  []string{"some-stack"},
+								URI:     "http://some-url",
+								Version: "1.2.3",
+								LicenseTables: []cargo.ConfigMetadataDependencyLicense{
+									{Type: "MIT", URI: "https://spdx.org/licenses/MIT.html"},
+									{Type: "Apache-2.0"},
+								},
+							},
+						},
+					},
+				}
+
+				err := cargo.EncodeConfig(buffer, config)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(buffer.String()).To(MatchTOML(`
+api = "0.2"
+
+[buildpack]
+	id = "some-buildpack-id"
+
+[metadata]
+
+[[metadata.dependencies]]
+	cpe = ["some-cpe", "other-cpe"]
+	id = "some-dependency"
+	sha256 = "shasum"
+	stacks = ["some-stack"]
+	uri = "http://some-url"
+	version = "1.2.3"
+
+	[[metadata.dependencies.licenses]]
+		type = "MIT"
+		uri = "https://spdx.org/licenses/MIT.html"
+
+	[[metadata.dependencies.licenses]]
+		type = "Apache-2.0"
+`))
+
+				var decoded cargo.Config
+				err = cargo.DecodeConfig(bytes.NewBuffer(buffer.Bytes()), &decoded)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(decoded.Metadata.Dependencies).To(Equal(config.Metadata.Dependencies))
+			})
+		})
+
 		context("failure cases", func() {
 			context("when the Config cannot be marshalled to json", func() {
 				it("returns an error", func() {
@@ -206,6 +333,7 @@ api = "0.2"
 	name = "some-buildpack-name"
 	version = "some-buildpack-version"
 	homepage = "some-homepage-link"
+	sbom-formats = ["application/vnd.cyclonedx+json", "application/spdx+json"]
 
 [[buildpack.licenses]]
 	type = "some-license-type"
@@ -271,6 +399,7 @@ api = "0.2"
 							URI:  "some-license-uri",
 						},
 					},
+					SBOMFormats: []string{"application/vnd.cyclonedx+json", "application/spdx+json"},
 				},
 				Stacks: []cargo.ConfigStack{
 					{
@@ -364,6 +493,19 @@ api = "0.2"
 				})
 			})
 
+			context("when there are stack-specific default versions", func() {
+				it("unmarshals them", func() {
+					var metadata cargo.ConfigMetadata
+					err := metadata.UnmarshalJSON([]byte(`{"stack-default-versions": {"some-stack": {"some-dependency": "1.2.*"}}}`))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(metadata).To(Equal(cargo.ConfigMetadata{
+						StackDefaultVersions: map[string]map[string]string{
+							"some-stack": {"some-dependency": "1.2.*"},
+						},
+					}))
+				})
+			})
+
 			context("failure cases", func() {
 				context("metadata field is not a object", func() {
 					it("it returns an error", func() {
@@ -397,6 +539,22 @@ api = "0.2"
 					})
 				})
 
+				context("metadata field dependencies has a cpe that is not a string or list of strings", func() {
+					it("it returns an error", func() {
+						var metadata cargo.ConfigMetadata
+						err := metadata.UnmarshalJSON([]byte(`{"dependencies": [{"id": "some-dependency", "cpe": [1]}]}`))
+						Expect(err).To(MatchError(ContainSubstring(`dependency "some-dependency" has a malformed cpe: json: cannot unmarshal`)))
+					})
+				})
+
+				context("metadata field dependencies has a license that is not a string or table", func() {
+					it("it returns an error", func() {
+						var metadata cargo.ConfigMetadata
+						err := metadata.UnmarshalJSON([]byte(`{"dependencies": [{"id": "some-dependency", "licenses": [1]}]}`))
+						Expect(err).To(MatchError(ContainSubstring(`dependency "some-dependency" has a malformed license: json: cannot unmarshal`)))
+					})
+				})
+
 				context("metadata field dependency-constraints is not an array of objects", func() {
 					it("it returns an error", func() {
 						var metadata cargo.ConfigMetadata
@@ -404,6 +562,205 @@ api = "0.2"
 						Expect(err).To(MatchError(ContainSubstring("json: cannot unmarshal")))
 					})
 				})
+
+				context("metadata field stack-default-versions is not a table of tables", func() {
+					it("it returns an error", func() {
+						var metadata cargo.ConfigMetadata
+						err := metadata.UnmarshalJSON([]byte(`{"stack-default-versions": {"some-stack": "1.2.*"}}`))
+						Expect(err).To(MatchError(ContainSubstring("json: cannot unmarshal")))
+					})
+				})
+			})
+		})
+	})
+
+	context("ValidateDefaultVersions", func() {
+		var config cargo.Config
+
+		it.Before(func() {
+			config = cargo.Config{
+				Metadata: cargo.ConfigMetadata{
+					DefaultVersions: map[string]string{
+						"node": "14.*",
+					},
+					Dependencies: []cargo.ConfigMetadataDependency{
+						{ID: "node", Version: "12.22.1"},
+						{ID: "node", Version: "14.17.0"},
+						{ID: "yarn", Version: "1.22.10"},
+					},
+				},
+			}
+		})
+
+		it("returns no errors when each default matches a dependency", func() {
+			Expect(config.ValidateDefaultVersions()).To(BeEmpty())
+		})
+
+		context("when a default references an id with no dependencies", func() {
+			it.Before(func() {
+				config.Metadata.DefaultVersions["npm"] = "6.*"
+			})
+
+			it("returns an error", func() {
+				errs := config.ValidateDefaultVersions()
+				Expect(errs).To(HaveLen(1))
+				Expect(errs[0]).To(MatchError(`default-version for "npm" references an id with no dependencies`))
+			})
+		})
+
+		context("when a default constraint matches no dependency version", func() {
+			it.Before(func() {
+				config.Metadata.DefaultVersions["node"] = "16.*"
+				config.Metadata.DefaultVersions["yarn"] = "2.*"
+			})
+
+			it("returns an error for each default", func() {
+				errs := config.ValidateDefaultVersions()
+				Expect(errs).To(HaveLen(2))
+				Expect(errs[0]).To(MatchError(`default-version for "node" matches no dependency: constraint "16.*"`))
+				Expect(errs[1]).To(MatchError(`default-version for "yarn" matches no dependency: constraint "2.*"`))
+			})
+		})
+
+		context("when a default constraint is not valid", func() {
+			it.Before(func() {
+				config.Metadata.DefaultVersions["node"] = "not-a-constraint"
+			})
+
+			it("returns an error", func() {
+				errs := config.ValidateDefaultVersions()
+				Expect(errs).To(HaveLen(1))
+				Expect(errs[0]).To(MatchError(ContainSubstring(`default-version for "node" has an invalid constraint "not-a-constraint"`)))
+			})
+		})
+	})
+
+	context("ValidateStacks", func() {
+		var config cargo.Config
+
+		it.Before(func() {
+			config = cargo.Config{
+				Stacks: []cargo.ConfigStack{
+					{ID: "some-stack"},
+					{ID: "other-stack"},
+				},
+				Metadata: cargo.ConfigMetadata{
+					Dependencies: []cargo.ConfigMetadataDependency{
+						{ID: "node", Version: "14.17.0", Stacks: []string{"some-stack", "other-stack"}},
+						{ID: "yarn", Version: "1.22.10", Stacks: []string{"*"}},
+					},
+				},
+			}
+		})
+
+		it("returns no errors when each dependency stack is declared", func() {
+			Expect(config.ValidateStacks()).To(BeEmpty())
+		})
+
+		context("when a dependency references an undeclared stack", func() {
+			it.Before(func() {
+				config.Metadata.Dependencies = append(config.Metadata.Dependencies,
+					cargo.ConfigMetadataDependency{ID: "node", Version: "16.4.0", Stacks: []string{"some-stack", "unknown-stack"}},
+					cargo.ConfigMetadataDependency{ID: "npm", Version: "7.19.1", Stacks: []string{"another-stack"}},
+				)
+			})
+
+			it("returns an error for each undeclared stack", func() {
+				errs := config.ValidateStacks()
+				Expect(errs).To(HaveLen(2))
+				Expect(errs[0]).To(MatchError(`dependency "node" (16.4.0) references undeclared stack "unknown-stack"`))
+				Expect(errs[1]).To(MatchError(`dependency "npm" (7.19.1) references undeclared stack "another-stack"`))
+			})
+
+			context("when the buildpack declares the any stack", func() {
+				it.Before(func() {
+					config.Stacks = []cargo.ConfigStack{{ID: "*"}}
+				})
+
+				it("returns no errors", func() {
+					Expect(config.ValidateStacks()).To(BeEmpty())
+				})
+			})
+		})
+	})
+
+	context("SortDependencies", func() {
+		it("orders the dependencies by id, version, and stacks", func() {
+			config := cargo.Config{
+				Metadata: cargo.ConfigMetadata{
+					Dependencies: []cargo.ConfigMetadataDependency{
+						{ID: "yarn", Version: "1.22.10", Stacks: []string{"some-stack"}},
+						{ID: "node", Version: "16.4.0", Stacks: []string{"some-stack"}},
+						{ID: "node", Version: "14.17.0", Stacks: []string{"other-stack"}},
+						{ID: "node", Version: "16.4.0", Stacks: []string{"other-stack"}},
+						{ID: "node", Version: "9.11.2", Stacks: []string{"some-stack"}},
+						{ID: "npm", Version: "7.19.1", Stacks: []string{"some-stack"}},
+					},
+				},
+			}
+
+			config.SortDependencies()
+			Expect(config.Metadata.Dependencies).To(Equal([]cargo.ConfigMetadataDependency{
+				{ID: "node", Version: "9.11.2", Stacks: []string{"some-stack"}},
+				{ID: "node", Version: "14.17.0", Stacks: []string{"other-stack"}},
+				{ID: "node", Version: "16.4.0", Stacks: []string{"other-stack"}},
+				{ID: "node", Version: "16.4.0", Stacks: []string{"some-stack"}},
+				{ID: "npm", Version: "7.19.1", Stacks: []string{"some-stack"}},
+				{ID: "yarn", Version: "1.22.10", Stacks: []string{"some-stack"}},
+			}))
+		})
+
+		context("when a version is not a semantic version", func() {
+			it("compares the versions lexically", func() {
+				config := cargo.Config{
+					Metadata: cargo.ConfigMetadata{
+						Dependencies: []cargo.ConfigMetadataDependency{
+							{ID: "some-dependency", Version: "latest"},
+							{ID: "some-dependency", Version: "1.2.3"},
+						},
+					},
+				}
+
+				config.SortDependencies()
+				Expect(config.Metadata.Dependencies).To(Equal([]cargo.ConfigMetadataDependency{
+					{ID: "some-dependency", Version: "1.2.3"},
+					{ID: "some-dependency", Version: "latest"},
+				}))
+			})
+		})
+	})
+
+	context("ValidateSBOMFormats", func() {
+		var config cargo.Config
+
+		it.Before(func() {
+			config = cargo.Config{
+				Buildpack: cargo.ConfigBuildpack{
+					SBOMFormats: []string{"application/vnd.cyclonedx+json", "application/spdx+json"},
+				},
+			}
+		})
+
+		it("returns no errors when each emitted format is declared", func() {
+			Expect(config.ValidateSBOMFormats("application/spdx+json", "application/vnd.cyclonedx+json")).To(BeEmpty())
+		})
+
+		context("when an emitted format is not declared", func() {
+			it("returns an error for each undeclared format", func() {
+				errs := config.ValidateSBOMFormats("application/spdx+json", "application/vnd.syft+json", "text/plain")
+				Expect(errs).To(HaveLen(2))
+				Expect(errs[0]).To(MatchError(`sbom format "application/vnd.syft+json" is not declared in buildpack.sbom-formats`))
+				Expect(errs[1]).To(MatchError(`sbom format "text/plain" is not declared in buildpack.sbom-formats`))
+			})
+		})
+
+		context("when the buildpack declares no formats", func() {
+			it.Before(func() {
+				config.Buildpack.SBOMFormats = nil
+			})
+
+			it("returns an error for every emitted format", func() {
+				Expect(config.ValidateSBOMFormats("application/spdx+json")).To(HaveLen(1))
 			})
 		})
 	})
@@ -0,0 +1,35 @@
+package cargo
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+)
+
+//go:generate faux --interface Downloader --output fakes/downloader.go
+
+// Downloader serves as the interface for types that can fetch dependencies
+// given a location uri, such as Transport or postal.Transport.
+type Downloader interface {
+	Drop(root, uri string) (io.ReadCloser, error)
+}
+
+// ChecksumDependency fetches the dependency at the given uri and returns its
+// SHA256 checksum in the "sha256:<hex>" form, so that it can be recorded in a
+// buildpack.toml dependency entry.
+func ChecksumDependency(uri string, downloader Downloader) (string, error) {
+	bundle, err := downloader.Drop("", uri)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch dependency: %w", err)
+	}
+	defer bundle.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, bundle)
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate dependency checksum: %w", err)
+	}
+
+	return fmt.Sprintf("sha256:%s", hex.EncodeToString(hash.Sum(nil))), nil
+}
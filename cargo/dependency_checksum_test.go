@@ -0,0 +1,63 @@
+package cargo_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/cargo"
+	"github.com/paketo-buildpacks/packit/cargo/fakes"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDependencyChecksum(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		downloader *fakes.Downloader
+	)
+
+	it.Before(func() {
+		downloader = &fakes.Downloader{}
+		downloader.DropCall.Returns.ReadCloser = io.NopCloser(strings.NewReader("some-contents"))
+	})
+
+	context("ChecksumDependency", func() {
+		it("returns the sha256 checksum of the fetched dependency", func() {
+			checksum, err := cargo.ChecksumDependency("https://example.com/some-dependency.tgz", downloader)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(checksum).To(Equal("sha256:6e32ea34db1b3755d7dec972eb72c705338f0dd8e0be881d966963438fb2e800"))
+
+			Expect(downloader.DropCall.Receives.Root).To(Equal(""))
+			Expect(downloader.DropCall.Receives.Uri).To(Equal("https://example.com/some-dependency.tgz"))
+		})
+
+		context("failure cases", func() {
+			context("when the dependency cannot be fetched", func() {
+				it.Before(func() {
+					downloader.DropCall.Returns.ReadCloser = nil
+					downloader.DropCall.Returns.Error = errors.New("failed to drop")
+				})
+
+				it("returns an error", func() {
+					_, err := cargo.ChecksumDependency("https://example.com/some-dependency.tgz", downloader)
+					Expect(err).To(MatchError("failed to fetch dependency: failed to drop"))
+				})
+			})
+
+			context("when the dependency cannot be read", func() {
+				it.Before(func() {
+					downloader.DropCall.Returns.ReadCloser = io.NopCloser(errorReader{})
+				})
+
+				it("returns an error", func() {
+					_, err := cargo.ChecksumDependency("https://example.com/some-dependency.tgz", downloader)
+					Expect(err).To(MatchError("failed to calculate dependency checksum: failed to read"))
+				})
+			})
+		})
+	})
+}
@@ -12,7 +12,11 @@ func TestUnitCargo(t *testing.T) {
 	suite := spec.New("cargo", spec.Report(report.Terminal{}))
 	suite("BuildpackParser", testBuildpackParser)
 	suite("Config", testConfig)
+	suite("DependencyChecksum", testDependencyChecksum)
 	suite("DirectoryDuplicator", testDirectoryDuplicator)
+	suite("MergeConfigs", testMergeConfigs)
+	suite("PackageConfig", testPackageConfig)
+	suite("ReproducibleTarball", testReproducibleTarball)
 	suite("Transport", testTransport)
 	suite("ValidatedReader", testValidatedReader)
 	suite.Run(t)
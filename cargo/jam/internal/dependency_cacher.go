@@ -76,3 +76,18 @@ func (dc DependencyCacher) Cache(root string, deps []cargo.ConfigMetadataDepende
 
 	return dependencies, nil
 }
+
+// VendorDependencies downloads each dependency declared in the given config
+// into the dependencies directory of outDir, verifying its checksum, and
+// returns a copy of the config whose dependency URIs refer to the vendored
+// files. The given config is left unmodified.
+func VendorDependencies(config cargo.Config, downloader Downloader, outDir string) (cargo.Config, error) {
+	dependencies, err := NewDependencyCacher(downloader, scribe.NewLogger(io.Discard)).Cache(outDir, config.Metadata.Dependencies)
+	if err != nil {
+		return cargo.Config{}, err
+	}
+
+	config.Metadata.Dependencies = dependencies
+
+	return config, nil
+}
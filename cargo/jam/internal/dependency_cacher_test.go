@@ -186,4 +186,75 @@ func testDependencyCacher(t *testing.T, context spec.G, it spec.S) {
 			})
 		})
 	})
+
+	context("VendorDependencies", func() {
+		var config cargo.Config
+
+		it.Before(func() {
+			config = cargo.Config{
+				API: "0.2",
+				Metadata: cargo.ConfigMetadata{
+					Dependencies: []cargo.ConfigMetadataDependency{
+						{
+							ID:      "dep-1",
+							Version: "1.2.3",
+							URI:     "http://dep1-uri",
+							SHA256:  "3c9de6683673f3e8039599d5200d533807c6c35fd9e35d6b6d77009122868f0f",
+						},
+						{
+							ID:      "dep-2",
+							Version: "4.5.6",
+							URI:     "http://dep2-uri",
+							SHA256:  "bfc72d62682f4a2edc3218d70b1f7052e4f336c179a8f19ef12ee721d4ea29b7",
+						},
+					},
+				},
+			}
+		})
+
+		it("vendors the dependencies and rewrites their uris", func() {
+			vendored, err := internal.VendorDependencies(config, downloader, tmpDir)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(vendored.API).To(Equal("0.2"))
+			Expect(vendored.Metadata.Dependencies).To(Equal([]cargo.ConfigMetadataDependency{
+				{
+					ID:      "dep-1",
+					Version: "1.2.3",
+					URI:     "file:///dependencies/3c9de6683673f3e8039599d5200d533807c6c35fd9e35d6b6d77009122868f0f",
+					SHA256:  "3c9de6683673f3e8039599d5200d533807c6c35fd9e35d6b6d77009122868f0f",
+				},
+				{
+					ID:      "dep-2",
+					Version: "4.5.6",
+					URI:     "file:///dependencies/bfc72d62682f4a2edc3218d70b1f7052e4f336c179a8f19ef12ee721d4ea29b7",
+					SHA256:  "bfc72d62682f4a2edc3218d70b1f7052e4f336c179a8f19ef12ee721d4ea29b7",
+				},
+			}))
+
+			Expect(config.Metadata.Dependencies[0].URI).To(Equal("http://dep1-uri"))
+			Expect(config.Metadata.Dependencies[1].URI).To(Equal("http://dep2-uri"))
+
+			contents, err := os.ReadFile(filepath.Join(tmpDir, "dependencies", "3c9de6683673f3e8039599d5200d533807c6c35fd9e35d6b6d77009122868f0f"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("dep1-contents"))
+
+			contents, err = os.ReadFile(filepath.Join(tmpDir, "dependencies", "bfc72d62682f4a2edc3218d70b1f7052e4f336c179a8f19ef12ee721d4ea29b7"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(Equal("dep2-contents"))
+		})
+
+		context("failure cases", func() {
+			context("when the checksum does not match", func() {
+				it.Before(func() {
+					config.Metadata.Dependencies[1].SHA256 = "invalid-sha"
+				})
+
+				it("returns an error", func() {
+					_, err := internal.VendorDependencies(config, downloader, tmpDir)
+					Expect(err).To(MatchError("failed to copy dependency: validation error: checksum does not match"))
+				})
+			})
+		})
+	})
 }
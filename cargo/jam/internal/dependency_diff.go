@@ -0,0 +1,55 @@
+package internal
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/paketo-buildpacks/packit/cargo"
+)
+
+// DiffDependencies compares the dependencies declared in two buildpack.toml
+// configs. Dependencies are matched by their id, version, and stacks. It
+// returns the dependencies from the new config that have no match in the old
+// config, the dependencies from the old config that have no match in the new
+// config, and the dependencies from the new config whose checksum or URI
+// differ from their match in the old config.
+func DiffDependencies(oldConfig, newConfig cargo.Config) ([]cargo.ConfigMetadataDependency, []cargo.ConfigMetadataDependency, []cargo.ConfigMetadataDependency) {
+	oldDependencies := map[string]cargo.ConfigMetadataDependency{}
+	for _, dependency := range oldConfig.Metadata.Dependencies {
+		oldDependencies[dependencyKey(dependency)] = dependency
+	}
+
+	newDependencies := map[string]cargo.ConfigMetadataDependency{}
+	for _, dependency := range newConfig.Metadata.Dependencies {
+		newDependencies[dependencyKey(dependency)] = dependency
+	}
+
+	var added, removed, changed []cargo.ConfigMetadataDependency
+	for _, dependency := range newConfig.Metadata.Dependencies {
+		previous, ok := oldDependencies[dependencyKey(dependency)]
+		if !ok {
+			added = append(added, dependency)
+			continue
+		}
+
+		if previous.SHA256 != dependency.SHA256 || previous.URI != dependency.URI {
+			changed = append(changed, dependency)
+		}
+	}
+
+	for _, dependency := range oldConfig.Metadata.Dependencies {
+		if _, ok := newDependencies[dependencyKey(dependency)]; !ok {
+			removed = append(removed, dependency)
+		}
+	}
+
+	return added, removed, changed
+}
+
+func dependencyKey(dependency cargo.ConfigMetadataDependency) string {
+	stacks := make([]string, len(dependency.Stacks))
+	copy(stacks, dependency.Stacks)
+	sort.Strings(stacks)
+
+	return strings.Join([]string{dependency.ID, dependency.Version, strings.Join(stacks, ",")}, "@")
+}
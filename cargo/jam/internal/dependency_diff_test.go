@@ -0,0 +1,134 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/cargo"
+	"github.com/paketo-buildpacks/packit/cargo/jam/internal"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDependencyDiff(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		oldConfig cargo.Config
+		newConfig cargo.Config
+	)
+
+	it.Before(func() {
+		oldConfig = cargo.Config{
+			Metadata: cargo.ConfigMetadata{
+				Dependencies: []cargo.ConfigMetadataDependency{
+					{ID: "node", Version: "14.17.0", Stacks: []string{"some-stack"}, URI: "http://node-14.17.0", SHA256: "node-14.17.0-sha"},
+					{ID: "node", Version: "16.4.0", Stacks: []string{"some-stack"}, URI: "http://node-16.4.0", SHA256: "node-16.4.0-sha"},
+					{ID: "yarn", Version: "1.22.10", Stacks: []string{"some-stack"}, URI: "http://yarn-1.22.10", SHA256: "yarn-1.22.10-sha"},
+				},
+			},
+		}
+
+		newConfig = cargo.Config{
+			Metadata: cargo.ConfigMetadata{
+				Dependencies: []cargo.ConfigMetadataDependency{
+					{ID: "node", Version: "14.17.0", Stacks: []string{"some-stack"}, URI: "http://node-14.17.0", SHA256: "node-14.17.0-sha"},
+					{ID: "node", Version: "16.4.0", Stacks: []string{"some-stack"}, URI: "http://node-16.4.0", SHA256: "node-16.4.0-sha"},
+					{ID: "yarn", Version: "1.22.10", Stacks: []string{"some-stack"}, URI: "http://yarn-1.22.10", SHA256: "yarn-1.22.10-sha"},
+				},
+			},
+		}
+	})
+
+	it("returns no differences when the dependencies are the same", func() {
+		added, removed, changed := internal.DiffDependencies(oldConfig, newConfig)
+		Expect(added).To(BeEmpty())
+		Expect(removed).To(BeEmpty())
+		Expect(changed).To(BeEmpty())
+	})
+
+	context("when a dependency is added", func() {
+		it.Before(func() {
+			newConfig.Metadata.Dependencies = append(newConfig.Metadata.Dependencies, cargo.ConfigMetadataDependency{
+				ID: "npm", Version: "7.19.1", Stacks: []string{"some-stack"}, URI: "http://npm-7.19.1", SHA256: "npm-7.19.1-sha",
+			})
+		})
+
+		it("reports the added dependency", func() {
+			added, removed, changed := internal.DiffDependencies(oldConfig, newConfig)
+			Expect(added).To(Equal([]cargo.ConfigMetadataDependency{
+				{ID: "npm", Version: "7.19.1", Stacks: []string{"some-stack"}, URI: "http://npm-7.19.1", SHA256: "npm-7.19.1-sha"},
+			}))
+			Expect(removed).To(BeEmpty())
+			Expect(changed).To(BeEmpty())
+		})
+	})
+
+	context("when a dependency is removed", func() {
+		it.Before(func() {
+			newConfig.Metadata.Dependencies = newConfig.Metadata.Dependencies[:2]
+		})
+
+		it("reports the removed dependency", func() {
+			added, removed, changed := internal.DiffDependencies(oldConfig, newConfig)
+			Expect(added).To(BeEmpty())
+			Expect(removed).To(Equal([]cargo.ConfigMetadataDependency{
+				{ID: "yarn", Version: "1.22.10", Stacks: []string{"some-stack"}, URI: "http://yarn-1.22.10", SHA256: "yarn-1.22.10-sha"},
+			}))
+			Expect(changed).To(BeEmpty())
+		})
+	})
+
+	context("when a dependency version is bumped", func() {
+		it.Before(func() {
+			newConfig.Metadata.Dependencies[1] = cargo.ConfigMetadataDependency{
+				ID: "node", Version: "16.5.0", Stacks: []string{"some-stack"}, URI: "http://node-16.5.0", SHA256: "node-16.5.0-sha",
+			}
+		})
+
+		it("reports the newConfig version as added and the oldConfig version as removed", func() {
+			added, removed, changed := internal.DiffDependencies(oldConfig, newConfig)
+			Expect(added).To(Equal([]cargo.ConfigMetadataDependency{
+				{ID: "node", Version: "16.5.0", Stacks: []string{"some-stack"}, URI: "http://node-16.5.0", SHA256: "node-16.5.0-sha"},
+			}))
+			Expect(removed).To(Equal([]cargo.ConfigMetadataDependency{
+				{ID: "node", Version: "16.4.0", Stacks: []string{"some-stack"}, URI: "http://node-16.4.0", SHA256: "node-16.4.0-sha"},
+			}))
+			Expect(changed).To(BeEmpty())
+		})
+	})
+
+	context("when the checksum and uri of an existing version change", func() {
+		it.Before(func() {
+			newConfig.Metadata.Dependencies[0].SHA256 = "rebuilt-node-14.17.0-sha"
+			newConfig.Metadata.Dependencies[2].URI = "http://mirror/yarn-1.22.10"
+		})
+
+		it("reports the changed dependencies", func() {
+			added, removed, changed := internal.DiffDependencies(oldConfig, newConfig)
+			Expect(added).To(BeEmpty())
+			Expect(removed).To(BeEmpty())
+			Expect(changed).To(Equal([]cargo.ConfigMetadataDependency{
+				{ID: "node", Version: "14.17.0", Stacks: []string{"some-stack"}, URI: "http://node-14.17.0", SHA256: "rebuilt-node-14.17.0-sha"},
+				{ID: "yarn", Version: "1.22.10", Stacks: []string{"some-stack"}, URI: "http://mirror/yarn-1.22.10", SHA256: "yarn-1.22.10-sha"},
+			}))
+		})
+	})
+
+	context("when the same version is built for several stacks", func() {
+		it.Before(func() {
+			oldConfig.Metadata.Dependencies = append(oldConfig.Metadata.Dependencies, cargo.ConfigMetadataDependency{
+				ID: "node", Version: "16.4.0", Stacks: []string{"other-stack"}, URI: "http://other-node-16.4.0", SHA256: "other-node-16.4.0-sha",
+			})
+		})
+
+		it("matches the dependencies by their stacks", func() {
+			added, removed, changed := internal.DiffDependencies(oldConfig, newConfig)
+			Expect(added).To(BeEmpty())
+			Expect(removed).To(Equal([]cargo.ConfigMetadataDependency{
+				{ID: "node", Version: "16.4.0", Stacks: []string{"other-stack"}, URI: "http://other-node-16.4.0", SHA256: "other-node-16.4.0-sha"},
+			}))
+			Expect(changed).To(BeEmpty())
+		})
+	})
+}
@@ -0,0 +1,55 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/paketo-buildpacks/packit/cargo"
+)
+
+// SummarizeDependencies renders the dependencies declared in the given config
+// as a markdown table listing the id, version, stacks, and deprecation date of
+// each dependency. Dependencies are ordered by id and then by version, newest
+// first. An empty string is returned when the config has no dependencies.
+func SummarizeDependencies(config cargo.Config) string {
+	if len(config.Metadata.Dependencies) == 0 {
+		return ""
+	}
+
+	dependencies := make([]cargo.ConfigMetadataDependency, len(config.Metadata.Dependencies))
+	copy(dependencies, config.Metadata.Dependencies)
+
+	sort.SliceStable(dependencies, func(i, j int) bool {
+		if dependencies[i].ID != dependencies[j].ID {
+			return dependencies[i].ID < dependencies[j].ID
+		}
+
+		iVersion, iErr := semver.NewVersion(dependencies[i].Version)
+		jVersion, jErr := semver.NewVersion(dependencies[j].Version)
+		if iErr != nil || jErr != nil {
+			return dependencies[i].Version > dependencies[j].Version
+		}
+
+		return iVersion.GreaterThan(jVersion)
+	})
+
+	var builder strings.Builder
+	builder.WriteString("| ID | Version | Stacks | Deprecation Date |\n")
+	builder.WriteString("|---|---|---|---|\n")
+	for _, dependency := range dependencies {
+		stacks := make([]string, len(dependency.Stacks))
+		copy(stacks, dependency.Stacks)
+		sort.Strings(stacks)
+
+		deprecationDate := "-"
+		if dependency.DeprecationDate != nil {
+			deprecationDate = dependency.DeprecationDate.Format("2006-01-02")
+		}
+
+		fmt.Fprintf(&builder, "| %s | %s | %s | %s |\n", dependency.ID, dependency.Version, strings.Join(stacks, ", "), deprecationDate)
+	}
+
+	return builder.String()
+}
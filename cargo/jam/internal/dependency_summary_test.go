@@ -0,0 +1,58 @@
+package internal_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/paketo-buildpacks/packit/cargo"
+	"github.com/paketo-buildpacks/packit/cargo/jam/internal"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDependencySummary(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("SummarizeDependencies", func() {
+		it("renders the dependencies as a markdown table", func() {
+			deprecationDate := time.Date(2022, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+			summary := internal.SummarizeDependencies(cargo.Config{
+				Metadata: cargo.ConfigMetadata{
+					Dependencies: []cargo.ConfigMetadataDependency{
+						{
+							ID:      "some-dependency",
+							Version: "1.2.3",
+							Stacks:  []string{"some-stack", "other-stack"},
+						},
+						{
+							ID:              "other-dependency",
+							Version:         "4.5.6",
+							Stacks:          []string{"some-stack"},
+							DeprecationDate: &deprecationDate,
+						},
+						{
+							ID:      "some-dependency",
+							Version: "1.10.0",
+							Stacks:  []string{"some-stack"},
+						},
+					},
+				},
+			})
+
+			Expect(summary).To(Equal(`| ID | Version | Stacks | Deprecation Date |
+|---|---|---|---|
+| other-dependency | 4.5.6 | some-stack | 2022-04-01 |
+| some-dependency | 1.10.0 | some-stack | - |
+| some-dependency | 1.2.3 | other-stack, some-stack | - |
+`))
+		})
+
+		context("when there are no dependencies", func() {
+			it("returns an empty string", func() {
+				Expect(internal.SummarizeDependencies(cargo.Config{})).To(BeEmpty())
+			})
+		})
+	})
+}
@@ -0,0 +1,43 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/paketo-buildpacks/packit/cargo"
+)
+
+// ValidateDependencyURIs attempts to fetch every dependency declared in the
+// given config using the given downloader. It returns an error for each
+// dependency whose URI could not be fetched, which for a cargo.Transport
+// includes HTTP URIs that respond with an error status, or whose SHA256
+// checksum is not a well-formed hex-encoded SHA256 digest. Dependencies that
+// declare Checksums instead of a SHA256 are not checked for a SHA256. An empty
+// result indicates that every dependency is reachable.
+func ValidateDependencyURIs(config cargo.Config, downloader Downloader) []error {
+	var errs []error
+	for _, dependency := range config.Metadata.Dependencies {
+		// Dependencies that declare checksums in the "<algorithm>:<hex>" format
+		// need not declare a sha256.
+		if dependency.SHA256 != "" || len(dependency.Checksums) == 0 {
+			sum, err := hex.DecodeString(dependency.SHA256)
+			if err != nil || len(sum) != sha256.Size {
+				errs = append(errs, fmt.Errorf("dependency %q (%s) has a malformed sha256 %q: expected %d hex characters", dependency.ID, dependency.Version, dependency.SHA256, sha256.Size*2))
+			}
+		}
+
+		bundle, err := downloader.Drop("", dependency.URI)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("dependency %q (%s) is unreachable at %q: %w", dependency.ID, dependency.Version, dependency.URI, err))
+			continue
+		}
+
+		err = bundle.Close()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to close dependency %q (%s): %w", dependency.ID, dependency.Version, err))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,149 @@
+package internal_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/cargo"
+	"github.com/paketo-buildpacks/packit/cargo/jam/internal"
+	"github.com/paketo-buildpacks/packit/cargo/jam/internal/fakes"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDependencyURIValidator(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		downloader *fakes.Downloader
+		config     cargo.Config
+	)
+
+	it.Before(func() {
+		downloader = &fakes.Downloader{}
+		downloader.DropCall.Stub = func(root, uri string) (io.ReadCloser, error) {
+			switch uri {
+			case "http://dep1-uri", "http://dep2-uri":
+				return io.NopCloser(strings.NewReader("contents")), nil
+
+			default:
+				return nil, errors.New("connection refused")
+			}
+		}
+
+		config = cargo.Config{
+			Metadata: cargo.ConfigMetadata{
+				Dependencies: []cargo.ConfigMetadataDependency{
+					{
+						ID:      "dep-1",
+						Version: "1.2.3",
+						URI:     "http://dep1-uri",
+						SHA256:  "3c9de6683673f3e8039599d5200d533807c6c35fd9e35d6b6d77009122868f0f",
+					},
+					{
+						ID:      "dep-2",
+						Version: "4.5.6",
+						URI:     "http://dep2-uri",
+						SHA256:  "bfc72d62682f4a2edc3218d70b1f7052e4f336c179a8f19ef12ee721d4ea29b7",
+					},
+				},
+			},
+		}
+	})
+
+	context("ValidateDependencyURIs", func() {
+		it("returns no errors when every dependency is reachable", func() {
+			errs := internal.ValidateDependencyURIs(config, downloader)
+			Expect(errs).To(BeEmpty())
+
+			Expect(downloader.DropCall.CallCount).To(Equal(2))
+			Expect(downloader.DropCall.Receives.Root).To(Equal(""))
+		})
+
+		context("when some dependencies are unreachable", func() {
+			it.Before(func() {
+				config.Metadata.Dependencies = append(config.Metadata.Dependencies,
+					cargo.ConfigMetadataDependency{
+						ID:      "dep-3",
+						Version: "7.8.9",
+						URI:     "http://dead-uri",
+						SHA256:  "bfc72d62682f4a2edc3218d70b1f7052e4f336c179a8f19ef12ee721d4ea29b7",
+					},
+					cargo.ConfigMetadataDependency{
+						ID:      "dep-4",
+						Version: "1.0.0",
+						URI:     "http://typo-uri",
+						SHA256:  "3c9de6683673f3e8039599d5200d533807c6c35fd9e35d6b6d77009122868f0f",
+					},
+				)
+			})
+
+			it("returns an error for each unreachable uri", func() {
+				errs := internal.ValidateDependencyURIs(config, downloader)
+				Expect(errs).To(HaveLen(2))
+				Expect(errs[0]).To(MatchError(`dependency "dep-3" (7.8.9) is unreachable at "http://dead-uri": connection refused`))
+				Expect(errs[1]).To(MatchError(`dependency "dep-4" (1.0.0) is unreachable at "http://typo-uri": connection refused`))
+			})
+		})
+
+		context("when a dependency uri responds with an error status", func() {
+			var server *httptest.Server
+
+			it.Before(func() {
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					switch req.URL.Path {
+					case "/some-dependency":
+						fmt.Fprint(w, "some-dependency-contents")
+					default:
+						http.NotFound(w, req)
+					}
+				}))
+
+				config.Metadata.Dependencies[0].URI = fmt.Sprintf("%s/some-dependency", server.URL)
+				config.Metadata.Dependencies[1].URI = fmt.Sprintf("%s/dead-dependency", server.URL)
+			})
+
+			it.After(func() {
+				server.Close()
+			})
+
+			it("returns an error for the dead uri", func() {
+				errs := internal.ValidateDependencyURIs(config, cargo.NewTransport())
+				Expect(errs).To(HaveLen(1))
+				Expect(errs[0]).To(MatchError(fmt.Sprintf(`dependency "dep-2" (4.5.6) is unreachable at %[1]q: failed to fetch %[1]q: unexpected response status 404 Not Found`, config.Metadata.Dependencies[1].URI)))
+			})
+		})
+
+		context("when a dependency declares checksums instead of a sha256", func() {
+			it.Before(func() {
+				config.Metadata.Dependencies[0].SHA256 = ""
+				config.Metadata.Dependencies[0].Checksums = []string{"sha512:some-checksum"}
+			})
+
+			it("does not report a malformed sha256", func() {
+				errs := internal.ValidateDependencyURIs(config, downloader)
+				Expect(errs).To(BeEmpty())
+			})
+		})
+
+		context("when a dependency checksum is malformed", func() {
+			it.Before(func() {
+				config.Metadata.Dependencies[0].SHA256 = "3c9de6683673f3e8"
+				config.Metadata.Dependencies[1].SHA256 = "not-hex"
+			})
+
+			it("returns an error for each malformed checksum", func() {
+				errs := internal.ValidateDependencyURIs(config, downloader)
+				Expect(errs).To(HaveLen(2))
+				Expect(errs[0]).To(MatchError(`dependency "dep-1" (1.2.3) has a malformed sha256 "3c9de6683673f3e8": expected 64 hex characters`))
+				Expect(errs[1]).To(MatchError(`dependency "dep-2" (4.5.6) has a malformed sha256 "not-hex": expected 64 hex characters`))
+			})
+		})
+	})
+}
@@ -2,15 +2,20 @@ package internal
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/docker/distribution/reference"
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/paketo-buildpacks/packit/chronos"
 )
 
 type Image struct {
@@ -19,23 +24,25 @@ type Image struct {
 	Version string
 }
 
-func FindLatestImage(uri string) (Image, error) {
-	named, err := reference.ParseNormalizedNamed(uri)
-	if err != nil {
-		return Image{}, fmt.Errorf("failed to parse image reference %q: %w", uri, err)
-	}
+const (
+	listTagsAttempts = 5
+	listTagsBackoff  = time.Second
+)
 
-	repo, err := name.NewRepository(reference.Path(named))
-	if err != nil {
-		return Image{}, fmt.Errorf("failed to parse image repository: %w", err)
-	}
+func FindLatestImage(uri string) (Image, error) {
+	return FindLatestImageWithClock(uri, chronos.DefaultClock)
+}
 
-	repo.Registry, err = name.NewRegistry(reference.Domain(named))
+// FindLatestImageWithClock behaves like FindLatestImage, using the given clock
+// to wait between attempts when listing the image tags fails with a transient
+// registry error.
+func FindLatestImageWithClock(uri string, clock chronos.Clock) (Image, error) {
+	named, repo, err := parseImageRepository(uri)
 	if err != nil {
-		return Image{}, fmt.Errorf("failed to parse image registry: %w", err)
+		return Image{}, err
 	}
 
-	tags, err := remote.List(repo, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	tags, err := listTags(repo, clock)
 	if err != nil {
 		return Image{}, fmt.Errorf("failed to list tags: %w", err)
 	}
@@ -62,6 +69,69 @@ func FindLatestImage(uri string) (Image, error) {
 	}, nil
 }
 
+// ListImageTags returns every tag of the repository of the given image uri
+// that is a valid semantic version, including prereleases, sorted from the
+// highest version to the lowest. Tags that are not semantic versions, such as
+// "latest", are omitted.
+func ListImageTags(uri string) ([]string, error) {
+	_, repo, err := parseImageRepository(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := listTags(repo, chronos.DefaultClock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	type versionedTag struct {
+		tag     string
+		version *semver.Version
+	}
+
+	var versionedTags []versionedTag
+	for _, tag := range tags {
+		version, err := semver.StrictNewVersion(tag)
+		if err != nil {
+			continue
+		}
+
+		versionedTags = append(versionedTags, versionedTag{tag: tag, version: version})
+	}
+
+	sort.SliceStable(versionedTags, func(i, j int) bool {
+		return versionedTags[i].version.GreaterThan(versionedTags[j].version)
+	})
+
+	sorted := []string{}
+	for _, versionedTag := range versionedTags {
+		sorted = append(sorted, versionedTag.tag)
+	}
+
+	return sorted, nil
+}
+
+// parseImageRepository parses the given image uri and returns the repository
+// it refers to.
+func parseImageRepository(uri string) (reference.Named, name.Repository, error) {
+	named, err := reference.ParseNormalizedNamed(uri)
+	if err != nil {
+		return nil, name.Repository{}, fmt.Errorf("failed to parse image reference %q: %w", uri, err)
+	}
+
+	repo, err := name.NewRepository(reference.Path(named))
+	if err != nil {
+		return nil, name.Repository{}, fmt.Errorf("failed to parse image repository: %w", err)
+	}
+
+	repo.Registry, err = name.NewRegistry(reference.Domain(named))
+	if err != nil {
+		return nil, name.Repository{}, fmt.Errorf("failed to parse image registry: %w", err)
+	}
+
+	return named, repo, nil
+}
+
 func FindLatestBuildImage(runURI, buildURI string) (Image, error) {
 	runNamed, err := reference.ParseNormalizedNamed(runURI)
 	if err != nil {
@@ -90,7 +160,7 @@ func FindLatestBuildImage(runURI, buildURI string) (Image, error) {
 		return Image{}, fmt.Errorf("failed to parse build image registry: %w", err)
 	}
 
-	tags, err := remote.List(repo, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	tags, err := listTags(repo, chronos.DefaultClock)
 	if err != nil {
 		return Image{}, fmt.Errorf("failed to list tags: %w", err)
 	}
@@ -118,6 +188,29 @@ func FindLatestBuildImage(runURI, buildURI string) (Image, error) {
 	}, nil
 }
 
+// listTags lists the tags of the given repository, retrying with backoff when
+// the registry responds with 429 Too Many Requests or a 5xx status. Any other
+// error, including authentication failures, is returned immediately.
+func listTags(repo name.Repository, clock chronos.Clock) ([]string, error) {
+	var tags []string
+	err := clock.Retry(listTagsAttempts, listTagsBackoff, isTransientRegistryError, func() error {
+		var err error
+		tags, err = remote.List(repo, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+		return err
+	})
+
+	return tags, err
+}
+
+func isTransientRegistryError(err error) bool {
+	var transportErr *transport.Error
+	if !errors.As(err, &transportErr) {
+		return false
+	}
+
+	return transportErr.StatusCode == http.StatusTooManyRequests || transportErr.StatusCode >= http.StatusInternalServerError
+}
+
 func GetBuildpackageID(uri string) (string, error) {
 	ref, err := name.ParseReference(uri)
 	if err != nil {
@@ -8,8 +8,10 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/paketo-buildpacks/packit/cargo/jam/internal"
+	"github.com/paketo-buildpacks/packit/chronos"
 	"github.com/sclevine/spec"
 
 	. "github.com/onsi/gomega"
@@ -24,7 +26,17 @@ func testImage(t *testing.T, context spec.G, it spec.S) {
 	)
 
 	context("FindLatestImage", func() {
+		var (
+			flakyRequests   int
+			limitedRequests int
+			errorRequests   int
+		)
+
 		it.Before(func() {
+			flakyRequests = 0
+			limitedRequests = 0
+			errorRequests = 0
+
 			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 				if req.Header.Get("Authorization") != "Basic c29tZS11c2VybmFtZTpzb21lLXBhc3N3b3Jk" {
 					w.Header().Set("WWW-Authenticate", `Basic realm="localhost"`)
@@ -49,7 +61,22 @@ func testImage(t *testing.T, context spec.G, it spec.S) {
 							]
 					}`)
 
+				case "/v2/some-org/flaky-repo/tags/list":
+					flakyRequests++
+					if flakyRequests < 3 {
+						w.WriteHeader(http.StatusServiceUnavailable)
+						return
+					}
+
+					w.WriteHeader(http.StatusOK)
+					fmt.Fprintln(w, `{"tags": ["0.0.10", "0.20.1"]}`)
+
+				case "/v2/some-org/limited-repo/tags/list":
+					limitedRequests++
+					w.WriteHeader(http.StatusTooManyRequests)
+
 				case "/v2/some-org/error-repo/tags/list":
+					errorRequests++
 					w.WriteHeader(http.StatusTeapot)
 
 				default:
@@ -81,6 +108,64 @@ func testImage(t *testing.T, context spec.G, it spec.S) {
 			Expect(os.RemoveAll(dockerConfig)).To(Succeed())
 		})
 
+		context("when the registry responds with a transient error", func() {
+			var (
+				clock  chronos.Clock
+				sleeps []time.Duration
+			)
+
+			it.Before(func() {
+				sleeps = nil
+				clock = chronos.NewClock(time.Now).WithSleep(func(duration time.Duration) {
+					sleeps = append(sleeps, duration)
+				})
+			})
+
+			it("retries listing the tags with backoff", func() {
+				image, err := internal.FindLatestImageWithClock(fmt.Sprintf("%s/some-org/flaky-repo:latest", strings.TrimPrefix(server.URL, "http://")), clock)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(image.Version).To(Equal("0.20.1"))
+
+				Expect(flakyRequests).To(Equal(3))
+				Expect(sleeps).To(Equal([]time.Duration{time.Second, 2 * time.Second}))
+			})
+
+			context("when the registry keeps rate limiting", func() {
+				it("gives up after the final attempt", func() {
+					_, err := internal.FindLatestImageWithClock(fmt.Sprintf("%s/some-org/limited-repo:latest", strings.TrimPrefix(server.URL, "http://")), clock)
+					Expect(err).To(MatchError(ContainSubstring("failed to list tags:")))
+					Expect(err).To(MatchError(ContainSubstring("status code 429")))
+
+					Expect(limitedRequests).To(Equal(5))
+					Expect(sleeps).To(Equal([]time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second}))
+				})
+			})
+
+			context("when the error is not transient", func() {
+				it("fails without retrying", func() {
+					_, err := internal.FindLatestImageWithClock(fmt.Sprintf("%s/some-org/error-repo:latest", strings.TrimPrefix(server.URL, "http://")), clock)
+					Expect(err).To(MatchError(ContainSubstring("status code 418")))
+
+					Expect(errorRequests).To(Equal(1))
+					Expect(sleeps).To(BeEmpty())
+				})
+			})
+
+			context("when authentication fails", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(dockerConfig, "config.json"), []byte(`{}`), 0600)).To(Succeed())
+				})
+
+				it("fails without retrying", func() {
+					_, err := internal.FindLatestImageWithClock(fmt.Sprintf("%s/some-org/flaky-repo:latest", strings.TrimPrefix(server.URL, "http://")), clock)
+					Expect(err).To(MatchError(ContainSubstring("failed to list tags:")))
+
+					Expect(flakyRequests).To(Equal(0))
+					Expect(sleeps).To(BeEmpty())
+				})
+			})
+		})
+
 		it("returns the latest non-prerelease semver tag for the given image uri", func() {
 			image, err := internal.FindLatestImage(fmt.Sprintf("%s/some-org/some-repo:latest", strings.TrimPrefix(server.URL, "http://")))
 			Expect(err).NotTo(HaveOccurred())
@@ -116,6 +201,119 @@ func testImage(t *testing.T, context spec.G, it spec.S) {
 		})
 	}, spec.Sequential())
 
+	context("ListImageTags", func() {
+		var pageRequests int
+
+		it.Before(func() {
+			pageRequests = 0
+
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if req.Header.Get("Authorization") != "Basic c29tZS11c2VybmFtZTpzb21lLXBhc3N3b3Jk" {
+					w.Header().Set("WWW-Authenticate", `Basic realm="localhost"`)
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+
+				switch req.URL.Path {
+				case "/v2/":
+					w.WriteHeader(http.StatusOK)
+
+				case "/v2/some-org/some-repo/tags/list":
+					pageRequests++
+
+					switch req.URL.Query().Get("last") {
+					case "":
+						w.Header().Set("Link", `</v2/some-org/some-repo/tags/list?n=3&last=latest>; rel="next"`)
+						w.WriteHeader(http.StatusOK)
+						fmt.Fprintln(w, `{"tags": ["0.0.10", "0.20.1", "latest"]}`)
+
+					case "latest":
+						w.Header().Set("Link", `</v2/some-org/some-repo/tags/list?n=3&last=1.0.0>; rel="next"`)
+						w.WriteHeader(http.StatusOK)
+						fmt.Fprintln(w, `{"tags": ["0.20.13-rc1", "0.20.12", "1.0.0"]}`)
+
+					default:
+						w.WriteHeader(http.StatusOK)
+						fmt.Fprintln(w, `{"tags": ["v2.0.0", "0.3.0"]}`)
+					}
+
+				case "/v2/some-org/empty-repo/tags/list":
+					w.WriteHeader(http.StatusOK)
+					fmt.Fprintln(w, `{"tags": ["latest"]}`)
+
+				case "/v2/some-org/error-repo/tags/list":
+					w.WriteHeader(http.StatusTeapot)
+
+				default:
+					t.Fatal(fmt.Sprintf("unknown path: %s", req.URL.Path))
+				}
+			}))
+
+			var err error
+			dockerConfig, err = os.MkdirTemp("", "docker-config")
+			Expect(err).NotTo(HaveOccurred())
+
+			contents := fmt.Sprintf(`{
+				"auths": {
+					%q: {
+						"username": "some-username",
+						"password": "some-password"
+					}
+				}
+			}`, strings.TrimPrefix(server.URL, "http://"))
+
+			err = os.WriteFile(filepath.Join(dockerConfig, "config.json"), []byte(contents), 0600)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(os.Setenv("DOCKER_CONFIG", dockerConfig)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("DOCKER_CONFIG")).To(Succeed())
+			Expect(os.RemoveAll(dockerConfig)).To(Succeed())
+		})
+
+		it("returns every semver tag across all pages sorted from highest to lowest", func() {
+			tags, err := internal.ListImageTags(fmt.Sprintf("%s/some-org/some-repo:latest", strings.TrimPrefix(server.URL, "http://")))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tags).To(Equal([]string{
+				"1.0.0",
+				"0.20.13-rc1",
+				"0.20.12",
+				"0.20.1",
+				"0.3.0",
+				"0.0.10",
+			}))
+
+			Expect(pageRequests).To(Equal(3))
+		})
+
+		context("when none of the tags are semver", func() {
+			it("returns an empty list", func() {
+				tags, err := internal.ListImageTags(fmt.Sprintf("%s/some-org/empty-repo:latest", strings.TrimPrefix(server.URL, "http://")))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(tags).To(BeEmpty())
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the uri cannot be parsed", func() {
+				it("returns an error", func() {
+					_, err := internal.ListImageTags("not a valid uri")
+					Expect(err).To(MatchError("failed to parse image reference \"not a valid uri\": invalid reference format"))
+				})
+			})
+
+			context("when the tags cannot be listed", func() {
+				it("returns an error", func() {
+					_, err := internal.ListImageTags(fmt.Sprintf("%s/some-org/error-repo:latest", strings.TrimPrefix(server.URL, "http://")))
+					Expect(err).To(MatchError(ContainSubstring("failed to list tags:")))
+					Expect(err).To(MatchError(ContainSubstring("status code 418")))
+				})
+			})
+		})
+	}, spec.Sequential())
+
 	context("FindLatestBuildImage", func() {
 		it.Before(func() {
 			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
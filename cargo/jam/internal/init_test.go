@@ -24,6 +24,9 @@ func TestUnitCargo(t *testing.T) {
 	suite("BuildpackInspector", testBuildpackInspector)
 	suite("DependencyCacher", testDependencyCacher)
 	suite("Dependency", testDependency)
+	suite("DependencyDiff", testDependencyDiff)
+	suite("DependencySummary", testDependencySummary)
+	suite("DependencyURIValidator", testDependencyURIValidator)
 	suite("FileBundler", testFileBundler)
 	suite("Formatter", testFormatter)
 	suite("Image", testImage)
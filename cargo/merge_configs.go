@@ -0,0 +1,184 @@
+package cargo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MergeConfigs combines a base config with an overlay config, such as when a
+// buildpack is composed from another. The rules are applied as follows:
+//
+// Dependencies in the overlay replace the dependencies in the base that share
+// their id, version, and stacks, keeping the position of the base dependency.
+// Any other overlay dependencies are appended after those of the base.
+//
+// The entries of metadata.default-versions, metadata.stack-default-versions,
+// and the unstructured metadata are combined, with the overlay entry winning
+// when both configs declare the same key.
+//
+// For every other field, the overlay value takes precedence whenever it is
+// set, and the base value is kept otherwise. Lists, such as stacks or
+// include-files, are replaced as a whole rather than combined.
+//
+// An error is returned if either config declares the same dependency more than
+// once, because it would be ambiguous which of them the overlay replaces.
+func MergeConfigs(base, overlay Config) (Config, error) {
+	merged := base
+
+	if overlay.API != "" {
+		merged.API = overlay.API
+	}
+
+	merged.Buildpack = mergeConfigBuildpacks(base.Buildpack, overlay.Buildpack)
+
+	if len(overlay.Stacks) > 0 {
+		merged.Stacks = overlay.Stacks
+	}
+
+	if len(overlay.Order) > 0 {
+		merged.Order = overlay.Order
+	}
+
+	dependencies, err := mergeConfigDependencies(base.Metadata.Dependencies, overlay.Metadata.Dependencies)
+	if err != nil {
+		return Config{}, err
+	}
+	merged.Metadata.Dependencies = dependencies
+
+	if len(overlay.Metadata.IncludeFiles) > 0 {
+		merged.Metadata.IncludeFiles = overlay.Metadata.IncludeFiles
+	}
+
+	if overlay.Metadata.PrePackage != "" {
+		merged.Metadata.PrePackage = overlay.Metadata.PrePackage
+	}
+
+	if len(overlay.Metadata.DependencyConstraints) > 0 {
+		merged.Metadata.DependencyConstraints = overlay.Metadata.DependencyConstraints
+	}
+
+	merged.Metadata.DefaultVersions = mergeStringMaps(base.Metadata.DefaultVersions, overlay.Metadata.DefaultVersions)
+
+	if len(base.Metadata.StackDefaultVersions) > 0 || len(overlay.Metadata.StackDefaultVersions) > 0 {
+		merged.Metadata.StackDefaultVersions = map[string]map[string]string{}
+		for stack, defaults := range base.Metadata.StackDefaultVersions {
+			merged.Metadata.StackDefaultVersions[stack] = mergeStringMaps(defaults, nil)
+		}
+
+		for stack, defaults := range overlay.Metadata.StackDefaultVersions {
+			merged.Metadata.StackDefaultVersions[stack] = mergeStringMaps(merged.Metadata.StackDefaultVersions[stack], defaults)
+		}
+	}
+
+	if len(base.Metadata.Unstructured) > 0 || len(overlay.Metadata.Unstructured) > 0 {
+		merged.Metadata.Unstructured = map[string]interface{}{}
+		for key, value := range base.Metadata.Unstructured {
+			merged.Metadata.Unstructured[key] = value
+		}
+
+		for key, value := range overlay.Metadata.Unstructured {
+			merged.Metadata.Unstructured[key] = value
+		}
+	}
+
+	return merged, nil
+}
+
+func mergeConfigBuildpacks(base, overlay ConfigBuildpack) ConfigBuildpack {
+	merged := base
+
+	if overlay.ID != "" {
+		merged.ID = overlay.ID
+	}
+
+	if overlay.Name != "" {
+		merged.Name = overlay.Name
+	}
+
+	if overlay.Version != "" {
+		merged.Version = overlay.Version
+	}
+
+	if overlay.Homepage != "" {
+		merged.Homepage = overlay.Homepage
+	}
+
+	if len(overlay.Licenses) > 0 {
+		merged.Licenses = overlay.Licenses
+	}
+
+	if len(overlay.SBOMFormats) > 0 {
+		merged.SBOMFormats = overlay.SBOMFormats
+	}
+
+	if overlay.SHA256 != "" {
+		merged.SHA256 = overlay.SHA256
+	}
+
+	return merged
+}
+
+func mergeConfigDependencies(base, overlay []ConfigMetadataDependency) ([]ConfigMetadataDependency, error) {
+	positions := map[string]int{}
+	for i, dependency := range base {
+		key := dependencyKey(dependency)
+		if _, ok := positions[key]; ok {
+			return nil, fmt.Errorf("failed to merge configs: base declares dependency %q (%s) for stacks %q more than once", dependency.ID, dependency.Version, dependency.Stacks)
+		}
+
+		positions[key] = i
+	}
+
+	merged := append([]ConfigMetadataDependency{}, base...)
+
+	seen := map[string]bool{}
+	for _, dependency := range overlay {
+		key := dependencyKey(dependency)
+		if seen[key] {
+			return nil, fmt.Errorf("failed to merge configs: overlay declares dependency %q (%s) for stacks %q more than once", dependency.ID, dependency.Version, dependency.Stacks)
+		}
+		seen[key] = true
+
+		if i, ok := positions[key]; ok {
+			merged[i] = dependency
+			continue
+		}
+
+		merged = append(merged, dependency)
+	}
+
+	if len(merged) == 0 {
+		return nil, nil
+	}
+
+	return merged, nil
+}
+
+// dependencyKey identifies a dependency by its id, version, and stacks,
+// regardless of the order in which its stacks are listed.
+func dependencyKey(dependency ConfigMetadataDependency) string {
+	stacks := append([]string{}, dependency.Stacks...)
+	sort.Strings(stacks)
+
+	return strings.Join([]string{dependency.ID, dependency.Version, strings.Join(stacks, ",")}, "|")
+}
+
+// mergeStringMaps returns a new map containing the entries of both maps, with
+// the entries of the overlay winning, or nil if both maps are empty.
+func mergeStringMaps(base, overlay map[string]string) map[string]string {
+	if len(base) == 0 && len(overlay) == 0 {
+		return nil
+	}
+
+	merged := map[string]string{}
+	for key, value := range base {
+		merged[key] = value
+	}
+
+	for key, value := range overlay {
+		merged[key] = value
+	}
+
+	return merged
+}
@@ -0,0 +1,235 @@
+package cargo_test
+
+import (
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/cargo"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testMergeConfigs(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		base    cargo.Config
+		overlay cargo.Config
+	)
+
+	it.Before(func() {
+		base = cargo.Config{
+			API: "0.2",
+			Buildpack: cargo.ConfigBuildpack{
+				ID:       "some-buildpack-id",
+				Name:     "some-buildpack-name",
+				Version:  "1.0.0",
+				Homepage: "some-homepage",
+			},
+			Stacks: []cargo.ConfigStack{
+				{ID: "some-stack"},
+			},
+			Metadata: cargo.ConfigMetadata{
+				IncludeFiles: []string{"bin/build", "bin/detect"},
+				PrePackage:   "./scripts/build.sh",
+				Dependencies: []cargo.ConfigMetadataDependency{
+					{
+						ID:      "some-dependency",
+						Version: "1.2.3",
+						Stacks:  []string{"some-stack", "other-stack"},
+						URI:     "http://base/some-dependency-1.2.3",
+						SHA256:  "base-sha",
+					},
+					{
+						ID:      "other-dependency",
+						Version: "4.5.6",
+						Stacks:  []string{"some-stack"},
+						URI:     "http://base/other-dependency-4.5.6",
+						SHA256:  "other-base-sha",
+					},
+				},
+				DefaultVersions: map[string]string{
+					"some-dependency":  "1.2.*",
+					"other-dependency": "4.5.*",
+				},
+				StackDefaultVersions: map[string]map[string]string{
+					"some-stack": {"some-dependency": "1.*"},
+				},
+				Unstructured: map[string]interface{}{
+					"some-key":  "base-value",
+					"other-key": "base-value",
+				},
+			},
+		}
+
+		overlay = cargo.Config{}
+	})
+
+	it("returns the base when the overlay is empty", func() {
+		merged, err := cargo.MergeConfigs(base, overlay)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(merged).To(Equal(base))
+	})
+
+	context("when the overlay declares a dependency with the same id, version, and stacks", func() {
+		it.Before(func() {
+			overlay.Metadata.Dependencies = []cargo.ConfigMetadataDependency{
+				{
+					ID:      "some-dependency",
+					Version: "1.2.3",
+					Stacks:  []string{"other-stack", "some-stack"},
+					URI:     "http://overlay/some-dependency-1.2.3",
+					SHA256:  "overlay-sha",
+				},
+			}
+		})
+
+		it("replaces the base dependency in place", func() {
+			merged, err := cargo.MergeConfigs(base, overlay)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(merged.Metadata.Dependencies).To(Equal([]cargo.ConfigMetadataDependency{
+				{
+					ID:      "some-dependency",
+					Version: "1.2.3",
+					Stacks:  []string{"other-stack", "some-stack"},
+					URI:     "http://overlay/some-dependency-1.2.3",
+					SHA256:  "overlay-sha",
+				},
+				base.Metadata.Dependencies[1],
+			}))
+		})
+	})
+
+	context("when the overlay declares dependencies that do not conflict", func() {
+		it.Before(func() {
+			overlay.Metadata.Dependencies = []cargo.ConfigMetadataDependency{
+				{
+					ID:      "some-dependency",
+					Version: "1.2.4",
+					Stacks:  []string{"some-stack", "other-stack"},
+					URI:     "http://overlay/some-dependency-1.2.4",
+					SHA256:  "overlay-sha",
+				},
+				{
+					ID:      "other-dependency",
+					Version: "4.5.6",
+					Stacks:  []string{"other-stack"},
+					URI:     "http://overlay/other-dependency-4.5.6",
+					SHA256:  "other-overlay-sha",
+				},
+			}
+		})
+
+		it("appends them after the base dependencies", func() {
+			merged, err := cargo.MergeConfigs(base, overlay)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(merged.Metadata.Dependencies).To(Equal([]cargo.ConfigMetadataDependency{
+				base.Metadata.Dependencies[0],
+				base.Metadata.Dependencies[1],
+				overlay.Metadata.Dependencies[0],
+				overlay.Metadata.Dependencies[1],
+			}))
+		})
+	})
+
+	context("when the overlay declares default versions", func() {
+		it.Before(func() {
+			overlay.Metadata.DefaultVersions = map[string]string{
+				"some-dependency": "1.3.*",
+				"new-dependency":  "2.*",
+			}
+			overlay.Metadata.StackDefaultVersions = map[string]map[string]string{
+				"some-stack":  {"other-dependency": "4.*"},
+				"other-stack": {"some-dependency": "1.2.*"},
+			}
+		})
+
+		it("combines them with the overlay taking precedence", func() {
+			merged, err := cargo.MergeConfigs(base, overlay)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(merged.Metadata.DefaultVersions).To(Equal(map[string]string{
+				"some-dependency":  "1.3.*",
+				"other-dependency": "4.5.*",
+				"new-dependency":   "2.*",
+			}))
+			Expect(merged.Metadata.StackDefaultVersions).To(Equal(map[string]map[string]string{
+				"some-stack":  {"some-dependency": "1.*", "other-dependency": "4.*"},
+				"other-stack": {"some-dependency": "1.2.*"},
+			}))
+		})
+
+		it("does not modify the base", func() {
+			_, err := cargo.MergeConfigs(base, overlay)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(base.Metadata.DefaultVersions).To(Equal(map[string]string{
+				"some-dependency":  "1.2.*",
+				"other-dependency": "4.5.*",
+			}))
+			Expect(base.Metadata.StackDefaultVersions).To(Equal(map[string]map[string]string{
+				"some-stack": {"some-dependency": "1.*"},
+			}))
+		})
+	})
+
+	context("when the overlay sets other fields", func() {
+		it.Before(func() {
+			overlay.API = "0.7"
+			overlay.Buildpack = cargo.ConfigBuildpack{
+				Version:     "2.0.0",
+				SBOMFormats: []string{"application/vnd.cyclonedx+json"},
+			}
+			overlay.Stacks = []cargo.ConfigStack{{ID: "other-stack"}}
+			overlay.Metadata.IncludeFiles = []string{"bin/run"}
+			overlay.Metadata.Unstructured = map[string]interface{}{
+				"some-key": "overlay-value",
+			}
+		})
+
+		it("takes the overlay value and keeps the base value for unset fields", func() {
+			merged, err := cargo.MergeConfigs(base, overlay)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(merged.API).To(Equal("0.7"))
+			Expect(merged.Buildpack).To(Equal(cargo.ConfigBuildpack{
+				ID:          "some-buildpack-id",
+				Name:        "some-buildpack-name",
+				Version:     "2.0.0",
+				Homepage:    "some-homepage",
+				SBOMFormats: []string{"application/vnd.cyclonedx+json"},
+			}))
+			Expect(merged.Stacks).To(Equal([]cargo.ConfigStack{{ID: "other-stack"}}))
+			Expect(merged.Metadata.IncludeFiles).To(Equal([]string{"bin/run"}))
+			Expect(merged.Metadata.PrePackage).To(Equal("./scripts/build.sh"))
+			Expect(merged.Metadata.Unstructured).To(Equal(map[string]interface{}{
+				"some-key":  "overlay-value",
+				"other-key": "base-value",
+			}))
+		})
+	})
+
+	context("failure cases", func() {
+		context("when the overlay declares the same dependency twice", func() {
+			it.Before(func() {
+				overlay.Metadata.Dependencies = []cargo.ConfigMetadataDependency{
+					{ID: "some-dependency", Version: "1.2.3", Stacks: []string{"some-stack"}},
+					{ID: "some-dependency", Version: "1.2.3", Stacks: []string{"some-stack"}},
+				}
+			})
+
+			it("returns an error", func() {
+				_, err := cargo.MergeConfigs(base, overlay)
+				Expect(err).To(MatchError(`failed to merge configs: overlay declares dependency "some-dependency" (1.2.3) for stacks ["some-stack"] more than once`))
+			})
+		})
+
+		context("when the base declares the same dependency twice", func() {
+			it.Before(func() {
+				base.Metadata.Dependencies = append(base.Metadata.Dependencies, base.Metadata.Dependencies[0])
+			})
+
+			it("returns an error", func() {
+				_, err := cargo.MergeConfigs(base, overlay)
+				Expect(err).To(MatchError(`failed to merge configs: base declares dependency "some-dependency" (1.2.3) for stacks ["some-stack" "other-stack"] more than once`))
+			})
+		})
+	})
+}
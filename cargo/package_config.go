@@ -0,0 +1,54 @@
+package cargo
+
+import (
+	"io"
+
+	"github.com/BurntSushi/toml"
+)
+
+// PackageConfig is a representation of the package.toml file used to build a
+// buildpackage from a buildpack and its dependencies.
+type PackageConfig struct {
+	Buildpack    PackageConfigBuildpack    `toml:"buildpack"`
+	Dependencies []PackageConfigDependency `toml:"dependencies"`
+}
+
+// PackageConfigBuildpack describes the location of the buildpack being
+// packaged.
+type PackageConfigBuildpack struct {
+	URI string `toml:"uri"`
+}
+
+// PackageConfigDependency describes the location of a buildpack or
+// buildpackage image that is included in the buildpackage.
+type PackageConfigDependency struct {
+	URI string `toml:"uri"`
+}
+
+// UnmarshalTOML supports dependencies that refer to their location using
+// either the uri or the image field.
+func (d *PackageConfigDependency) UnmarshalTOML(v interface{}) error {
+	if m, ok := v.(map[string]interface{}); ok {
+		if image, ok := m["image"].(string); ok {
+			d.URI = image
+		}
+
+		if uri, ok := m["uri"].(string); ok {
+			d.URI = uri
+		}
+	}
+
+	return nil
+}
+
+// EncodePackageConfig writes the given PackageConfig to the writer as TOML.
+func EncodePackageConfig(writer io.Writer, config PackageConfig) error {
+	return toml.NewEncoder(writer).Encode(config)
+}
+
+// DecodePackageConfig reads TOML from the reader into the given
+// PackageConfig.
+func DecodePackageConfig(reader io.Reader, config *PackageConfig) error {
+	_, err := toml.DecodeReader(reader, config)
+	return err
+}
@@ -0,0 +1,110 @@
+package cargo_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/cargo"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+	. "github.com/paketo-buildpacks/packit/matchers"
+)
+
+func testPackageConfig(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		buffer *bytes.Buffer
+	)
+
+	it.Before(func() {
+		buffer = bytes.NewBuffer(nil)
+	})
+
+	context("EncodePackageConfig", func() {
+		it("encodes the package config to TOML", func() {
+			err := cargo.EncodePackageConfig(buffer, cargo.PackageConfig{
+				Buildpack: cargo.PackageConfigBuildpack{
+					URI: "build/buildpack.tgz",
+				},
+				Dependencies: []cargo.PackageConfigDependency{
+					{URI: "docker://some-registry/some-repository/some-buildpack-id:0.20.1"},
+					{URI: "docker://some-registry/some-repository/other-buildpack-id:0.1.0"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(buffer.String()).To(MatchTOML(`
+				[buildpack]
+				uri = "build/buildpack.tgz"
+
+				[[dependencies]]
+				uri = "docker://some-registry/some-repository/some-buildpack-id:0.20.1"
+
+				[[dependencies]]
+				uri = "docker://some-registry/some-repository/other-buildpack-id:0.1.0"
+			`))
+		})
+	})
+
+	context("DecodePackageConfig", func() {
+		it("decodes TOML to package config", func() {
+			var config cargo.PackageConfig
+			err := cargo.DecodePackageConfig(strings.NewReader(`
+				[buildpack]
+				uri = "build/buildpack.tgz"
+
+				[[dependencies]]
+				uri = "docker://some-registry/some-repository/last-buildpack-id:0.2.0"
+
+				[[dependencies]]
+				image = "some-registry/some-repository/some-buildpack-id:0.20.1"
+
+				[[dependencies]]
+				uri = "docker://some-registry/some-repository/other-buildpack-id:0.1.0"
+			`), &config)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(config).To(Equal(cargo.PackageConfig{
+				Buildpack: cargo.PackageConfigBuildpack{
+					URI: "build/buildpack.tgz",
+				},
+				Dependencies: []cargo.PackageConfigDependency{
+					{URI: "docker://some-registry/some-repository/last-buildpack-id:0.2.0"},
+					{URI: "some-registry/some-repository/some-buildpack-id:0.20.1"},
+					{URI: "docker://some-registry/some-repository/other-buildpack-id:0.1.0"},
+				},
+			}))
+		})
+
+		it("round-trips the package config", func() {
+			config := cargo.PackageConfig{
+				Buildpack: cargo.PackageConfigBuildpack{
+					URI: "build/buildpack.tgz",
+				},
+				Dependencies: []cargo.PackageConfigDependency{
+					{URI: "docker://some-registry/some-repository/last-buildpack-id:0.2.0"},
+					{URI: "docker://some-registry/some-repository/some-buildpack-id:0.20.1"},
+					{URI: "docker://some-registry/some-repository/other-buildpack-id:0.1.0"},
+				},
+			}
+
+			Expect(cargo.EncodePackageConfig(buffer, config)).To(Succeed())
+
+			var decoded cargo.PackageConfig
+			Expect(cargo.DecodePackageConfig(buffer, &decoded)).To(Succeed())
+			Expect(decoded).To(Equal(config))
+		})
+
+		context("failure cases", func() {
+			context("when a bad reader is passed in", func() {
+				it("returns an error", func() {
+					err := cargo.DecodePackageConfig(errorReader{}, &cargo.PackageConfig{})
+					Expect(err).To(MatchError(ContainSubstring("failed to read")))
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,147 @@
+package cargo
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/paketo-buildpacks/packit/fs"
+)
+
+// WriteReproducibleTarball writes the contents of the given directory to the
+// writer as a gzipped tarball whose bytes depend only on the names, contents,
+// and executable bits of the files in the directory. Entries are written in
+// lexical order with modification times set to the Unix epoch, ownership set
+// to root, and modes normalized to 0755 for directories and executable files,
+// 0644 for other files, and 0777 for symlinks. Packaging the same tree twice
+// produces identical bytes.
+func WriteReproducibleTarball(writer io.Writer, dir string) error {
+	gw := gzip.NewWriter(writer)
+	tw := tar.NewWriter(gw)
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		if rel == "." {
+			return nil
+		}
+
+		hdr := &tar.Header{
+			Name:    filepath.ToSlash(rel),
+			ModTime: time.Unix(0, 0),
+		}
+
+		switch {
+		case info.IsDir():
+			hdr.Typeflag = tar.TypeDir
+			hdr.Name += "/"
+			hdr.Mode = 0755
+
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+
+			hdr.Typeflag = tar.TypeSymlink
+			hdr.Linkname = link
+			hdr.Mode = 0777
+
+		case info.Mode().IsRegular():
+			hdr.Typeflag = tar.TypeReg
+			hdr.Size = info.Size()
+			hdr.Mode = 0644
+			if info.Mode()&0111 != 0 {
+				hdr.Mode = 0755
+			}
+
+		default:
+			return fmt.Errorf("unsupported file type for %q: %s", rel, info.Mode().Type())
+		}
+
+		err = tw.WriteHeader(hdr)
+		if err != nil {
+			return fmt.Errorf("failed to write header to tarball: %w", err)
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(tw, file)
+		if err != nil {
+			return fmt.Errorf("failed to write file to tarball: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create reproducible tarball: %w", err)
+	}
+
+	err = tw.Close()
+	if err != nil {
+		return fmt.Errorf("failed to create reproducible tarball: %w", err)
+	}
+
+	err = gw.Close()
+	if err != nil {
+		return fmt.Errorf("failed to create reproducible tarball: %w", err)
+	}
+
+	return nil
+}
+
+// ReproducibleDigest returns the hex-encoded SHA256 checksum of the tarball
+// that WriteReproducibleTarball produces for the given directory. Because the
+// tarball depends only on the names, contents, and executable bits of the
+// files in the directory, the digest changes only when one of those does,
+// which makes it a stable signal of whether a buildpack has changed.
+func ReproducibleDigest(dir string) (string, error) {
+	tmpDir, err := os.MkdirTemp("", "reproducible-digest")
+	if err != nil {
+		return "", fmt.Errorf("failed to compute reproducible digest: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	path := filepath.Join(tmpDir, "buildpack.tgz")
+	file, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute reproducible digest: %w", err)
+	}
+
+	err = WriteReproducibleTarball(file, dir)
+	if err != nil {
+		file.Close()
+		return "", fmt.Errorf("failed to compute reproducible digest: %w", err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to compute reproducible digest: %w", err)
+	}
+
+	digest, err := fs.NewChecksumCalculator().Sum(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to compute reproducible digest: %w", err)
+	}
+
+	return digest, nil
+}
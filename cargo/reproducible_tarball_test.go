@@ -0,0 +1,155 @@
+package cargo_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/paketo-buildpacks/packit/cargo"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testReproducibleTarball(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		dir string
+	)
+
+	it.Before(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "source")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.WriteFile(filepath.Join(dir, "some-file"), []byte("some content"), 0600)).To(Succeed())
+
+		Expect(os.MkdirAll(filepath.Join(dir, "some-dir"), 0700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "some-dir", "other-file"), []byte("other content"), 0700)).To(Succeed())
+		Expect(os.Symlink("other-file", filepath.Join(dir, "some-dir", "link"))).To(Succeed())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	context("WriteReproducibleTarball", func() {
+		it("writes a tarball with normalized headers", func() {
+			buffer := bytes.NewBuffer(nil)
+			Expect(cargo.WriteReproducibleTarball(buffer, dir)).To(Succeed())
+
+			gr, err := gzip.NewReader(buffer)
+			Expect(err).NotTo(HaveOccurred())
+
+			var headers []tar.Header
+			contents := map[string]string{}
+			tr := tar.NewReader(gr)
+			for {
+				hdr, err := tr.Next()
+				if err == io.EOF {
+					break
+				}
+				Expect(err).NotTo(HaveOccurred())
+
+				content, err := io.ReadAll(tr)
+				Expect(err).NotTo(HaveOccurred())
+				contents[hdr.Name] = string(content)
+
+				Expect(hdr.ModTime).To(Equal(time.Unix(0, 0)))
+				Expect(hdr.Uid).To(Equal(0))
+				Expect(hdr.Gid).To(Equal(0))
+				Expect(hdr.Uname).To(BeEmpty())
+				Expect(hdr.Gname).To(BeEmpty())
+
+				headers = append(headers, tar.Header{Name: hdr.Name, Mode: hdr.Mode, Typeflag: hdr.Typeflag, Linkname: hdr.Linkname})
+			}
+
+			Expect(headers).To(Equal([]tar.Header{
+				{Name: "some-dir/", Mode: 0755, Typeflag: tar.TypeDir},
+				{Name: "some-dir/link", Mode: 0777, Typeflag: tar.TypeSymlink, Linkname: "other-file"},
+				{Name: "some-dir/other-file", Mode: 0755, Typeflag: tar.TypeReg},
+				{Name: "some-file", Mode: 0644, Typeflag: tar.TypeReg},
+			}))
+
+			Expect(contents).To(HaveKeyWithValue("some-dir/other-file", "other content"))
+			Expect(contents).To(HaveKeyWithValue("some-file", "some content"))
+		})
+
+		it("produces identical bytes when packaging the same tree twice", func() {
+			first := bytes.NewBuffer(nil)
+			Expect(cargo.WriteReproducibleTarball(first, dir)).To(Succeed())
+
+			later := time.Now().Add(time.Hour)
+			Expect(os.Chtimes(filepath.Join(dir, "some-file"), later, later)).To(Succeed())
+			Expect(os.Chtimes(filepath.Join(dir, "some-dir"), later, later)).To(Succeed())
+
+			second := bytes.NewBuffer(nil)
+			Expect(cargo.WriteReproducibleTarball(second, dir)).To(Succeed())
+
+			Expect(second.Bytes()).To(Equal(first.Bytes()))
+		})
+
+		context("failure cases", func() {
+			context("when the directory does not exist", func() {
+				it("returns an error", func() {
+					err := cargo.WriteReproducibleTarball(bytes.NewBuffer(nil), filepath.Join(dir, "missing"))
+					Expect(err).To(MatchError(ContainSubstring("failed to create reproducible tarball:")))
+					Expect(err).To(MatchError(ContainSubstring("no such file or directory")))
+				})
+			})
+		})
+	})
+	context("ReproducibleDigest", func() {
+		it("returns the checksum of the reproducible tarball", func() {
+			buffer := bytes.NewBuffer(nil)
+			Expect(cargo.WriteReproducibleTarball(buffer, dir)).To(Succeed())
+
+			sum := sha256.Sum256(buffer.Bytes())
+
+			digest, err := cargo.ReproducibleDigest(dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(digest).To(Equal(hex.EncodeToString(sum[:])))
+		})
+
+		it("returns the same digest when computed twice for the same tree", func() {
+			first, err := cargo.ReproducibleDigest(dir)
+			Expect(err).NotTo(HaveOccurred())
+
+			later := time.Now().Add(time.Hour)
+			Expect(os.Chtimes(filepath.Join(dir, "some-file"), later, later)).To(Succeed())
+
+			second, err := cargo.ReproducibleDigest(dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second).To(Equal(first))
+		})
+
+		it("returns a different digest after the tree changes", func() {
+			first, err := cargo.ReproducibleDigest(dir)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(os.WriteFile(filepath.Join(dir, "some-file"), []byte("changed content"), 0600)).To(Succeed())
+
+			second, err := cargo.ReproducibleDigest(dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second).NotTo(Equal(first))
+		})
+
+		context("failure cases", func() {
+			context("when the directory does not exist", func() {
+				it("returns an error", func() {
+					_, err := cargo.ReproducibleDigest(filepath.Join(dir, "missing"))
+					Expect(err).To(MatchError(ContainSubstring("failed to compute reproducible digest: failed to create reproducible tarball:")))
+					Expect(err).To(MatchError(ContainSubstring("no such file or directory")))
+				})
+			})
+		})
+	})
+}
@@ -7,6 +7,10 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 )
 
 type Transport struct{}
@@ -15,7 +19,28 @@ func NewTransport() Transport {
 	return Transport{}
 }
 
+// Drop returns the contents of the dependency at the given uri. Dependencies
+// with a "file://" uri are read relative to the given root directory. A uri of
+// the form "oci://registry/repository:tag" refers to a single-layer image, and
+// the compressed contents of that layer are returned. Registry credentials are
+// read from the Docker config file, which can be located with DOCKER_CONFIG.
+// A uri of the form "s3://bucket/key" refers to an object in an S3 bucket,
+// which is fetched using the region set in the environment and the static
+// credentials in the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, and
+// AWS_SESSION_TOKEN environment variables. No other credential source is
+// consulted: shared config and credentials file profiles, the EC2 instance
+// metadata service, web identity tokens, and SSO are not supported. Any other
+// uri is fetched over HTTP, and a response with a status outside of the 2xx
+// range is returned as an error.
 func (t Transport) Drop(root, uri string) (io.ReadCloser, error) {
+	if strings.HasPrefix(uri, "oci://") {
+		return dropImageLayer(strings.TrimPrefix(uri, "oci://"))
+	}
+
+	if strings.HasPrefix(uri, "s3://") {
+		return dropS3Object(strings.TrimPrefix(uri, "s3://"))
+	}
+
 	if strings.HasPrefix(uri, "file://") {
 		file, err := os.Open(filepath.Join(root, strings.TrimPrefix(uri, "file://")))
 		if err != nil {
@@ -35,5 +60,38 @@ func (t Transport) Drop(root, uri string) (io.ReadCloser, error) {
 		return nil, fmt.Errorf("failed to make request: %s", err)
 	}
 
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		response.Body.Close()
+		return nil, fmt.Errorf("failed to fetch %q: unexpected response status %s", uri, response.Status)
+	}
+
 	return response.Body, nil
 }
+
+func dropImageLayer(uri string) (io.ReadCloser, error) {
+	ref, err := name.ParseReference(uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse image reference: %s", err)
+	}
+
+	image, err := remote.Image(ref, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch image: %s", err)
+	}
+
+	layers, err := image.Layers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image layers: %s", err)
+	}
+
+	if len(layers) != 1 {
+		return nil, fmt.Errorf("failed to read image layers: expected 1 layer but image has %d", len(layers))
+	}
+
+	layer, err := layers[0].Compressed()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image layer: %s", err)
+	}
+
+	return layer, nil
+}
@@ -1,14 +1,25 @@
 package cargo_test
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
 	"github.com/paketo-buildpacks/packit/cargo"
 	"github.com/sclevine/spec"
 
@@ -63,6 +74,15 @@ func testTransport(t *testing.T, context spec.G, it spec.S) {
 					})
 				})
 
+				context("when the server responds with an error status", func() {
+					it("returns an error", func() {
+						uri := fmt.Sprintf("%s/missing-bundle", server.URL)
+
+						_, err := transport.Drop("", uri)
+						Expect(err).To(MatchError(fmt.Sprintf("failed to fetch %q: unexpected response status 404 Not Found", uri)))
+					})
+				})
+
 				context("when the request fails", func() {
 					it.Before(func() {
 						server.Close()
@@ -123,5 +143,230 @@ func testTransport(t *testing.T, context spec.G, it spec.S) {
 				})
 			})
 		})
+
+		context("when the uri is for an image", func() {
+			var (
+				server       *httptest.Server
+				dockerConfig string
+				registryHost string
+				layerContent []byte
+			)
+
+			it.Before(func() {
+				registryHandler := registry.New()
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					if req.Header.Get("Authorization") != "Basic c29tZS11c2VybmFtZTpzb21lLXBhc3N3b3Jk" {
+						w.Header().Set("WWW-Authenticate", `Basic realm="localhost"`)
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+
+					registryHandler.ServeHTTP(w, req)
+				}))
+				registryHost = strings.TrimPrefix(server.URL, "http://")
+
+				buffer := bytes.NewBuffer(nil)
+				gw := gzip.NewWriter(buffer)
+				tw := tar.NewWriter(gw)
+
+				Expect(tw.WriteHeader(&tar.Header{Name: "some-file", Mode: 0644, Size: int64(len("some-content"))})).To(Succeed())
+				_, err := tw.Write([]byte("some-content"))
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(tw.Close()).To(Succeed())
+				Expect(gw.Close()).To(Succeed())
+				layerContent = buffer.Bytes()
+
+				push := func(tag string, layerCount int) {
+					image := empty.Image
+					for i := 0; i < layerCount; i++ {
+						layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+							return io.NopCloser(bytes.NewReader(layerContent)), nil
+						})
+						Expect(err).NotTo(HaveOccurred())
+
+						image, err = mutate.AppendLayers(image, layer)
+						Expect(err).NotTo(HaveOccurred())
+					}
+
+					ref, err := name.ParseReference(fmt.Sprintf("%s/some-org/some-image:%s", registryHost, tag))
+					Expect(err).NotTo(HaveOccurred())
+
+					err = remote.Write(ref, image, remote.WithAuth(&authn.Basic{Username: "some-username", Password: "some-password"}))
+					Expect(err).NotTo(HaveOccurred())
+				}
+
+				push("some-tag", 1)
+				push("multi-layer", 2)
+
+				dockerConfig, err = os.MkdirTemp("", "docker-config")
+				Expect(err).NotTo(HaveOccurred())
+
+				contents := fmt.Sprintf(`{
+					"auths": {
+						%q: {
+							"username": "some-username",
+							"password": "some-password"
+						}
+					}
+				}`, registryHost)
+
+				err = os.WriteFile(filepath.Join(dockerConfig, "config.json"), []byte(contents), 0600)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(os.Setenv("DOCKER_CONFIG", dockerConfig)).To(Succeed())
+			})
+
+			it.After(func() {
+				server.Close()
+				Expect(os.Unsetenv("DOCKER_CONFIG")).To(Succeed())
+				Expect(os.RemoveAll(dockerConfig)).To(Succeed())
+			})
+
+			it("returns the contents of the image layer", func() {
+				bundle, err := transport.Drop("", fmt.Sprintf("oci://%s/some-org/some-image:some-tag", registryHost))
+				Expect(err).NotTo(HaveOccurred())
+
+				contents, err := io.ReadAll(bundle)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(contents).To(Equal(layerContent))
+
+				Expect(bundle.Close()).To(Succeed())
+			})
+
+			context("failure cases", func() {
+				context("when the image reference cannot be parsed", func() {
+					it("returns an error", func() {
+						_, err := transport.Drop("", "oci://not a valid reference")
+						Expect(err).To(MatchError(ContainSubstring("failed to parse image reference")))
+					})
+				})
+
+				context("when the image does not exist", func() {
+					it("returns an error", func() {
+						_, err := transport.Drop("", fmt.Sprintf("oci://%s/some-org/no-such-image:some-tag", registryHost))
+						Expect(err).To(MatchError(ContainSubstring("failed to fetch image")))
+					})
+				})
+
+				context("when the registry credentials are missing", func() {
+					it.Before(func() {
+						Expect(os.WriteFile(filepath.Join(dockerConfig, "config.json"), []byte(`{}`), 0600)).To(Succeed())
+					})
+
+					it("returns an error", func() {
+						_, err := transport.Drop("", fmt.Sprintf("oci://%s/some-org/some-image:some-tag", registryHost))
+						Expect(err).To(MatchError(ContainSubstring("failed to fetch image")))
+						Expect(err).To(MatchError(ContainSubstring("401 Unauthorized")))
+					})
+				})
+
+				context("when the image has more than one layer", func() {
+					it("returns an error", func() {
+						_, err := transport.Drop("", fmt.Sprintf("oci://%s/some-org/some-image:multi-layer", registryHost))
+						Expect(err).To(MatchError("failed to read image layers: expected 1 layer but image has 2"))
+					})
+				})
+			})
+		}, spec.Sequential())
+
+		context("when the uri is for an S3 object", func() {
+			var (
+				server   *httptest.Server
+				requests []*http.Request
+			)
+
+			it.Before(func() {
+				requests = nil
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					requests = append(requests, req)
+
+					switch req.URL.Path {
+					case "/some-bucket/some-dir/some-object.tgz":
+						fmt.Fprint(w, "some-object-contents")
+					default:
+						http.NotFound(w, req)
+					}
+				}))
+
+				Expect(os.Setenv("AWS_ENDPOINT_URL", server.URL)).To(Succeed())
+				Expect(os.Setenv("AWS_REGION", "some-region")).To(Succeed())
+				Expect(os.Setenv("AWS_ACCESS_KEY_ID", "some-access-key-id")).To(Succeed())
+				Expect(os.Setenv("AWS_SECRET_ACCESS_KEY", "some-secret-access-key")).To(Succeed())
+				Expect(os.Setenv("AWS_SESSION_TOKEN", "some-session-token")).To(Succeed())
+			})
+
+			it.After(func() {
+				server.Close()
+
+				Expect(os.Unsetenv("AWS_ENDPOINT_URL")).To(Succeed())
+				Expect(os.Unsetenv("AWS_REGION")).To(Succeed())
+				Expect(os.Unsetenv("AWS_ACCESS_KEY_ID")).To(Succeed())
+				Expect(os.Unsetenv("AWS_SECRET_ACCESS_KEY")).To(Succeed())
+				Expect(os.Unsetenv("AWS_SESSION_TOKEN")).To(Succeed())
+			})
+
+			it("returns the contents of the object using a signed request", func() {
+				bundle, err := transport.Drop("", "s3://some-bucket/some-dir/some-object.tgz")
+				Expect(err).NotTo(HaveOccurred())
+
+				contents, err := io.ReadAll(bundle)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(contents)).To(Equal("some-object-contents"))
+
+				Expect(bundle.Close()).To(Succeed())
+
+				Expect(requests).To(HaveLen(1))
+				Expect(requests[0].Method).To(Equal("GET"))
+				Expect(requests[0].Header.Get("X-Amz-Security-Token")).To(Equal("some-session-token"))
+				Expect(requests[0].Header.Get("X-Amz-Date")).To(MatchRegexp(`^\d{8}T\d{6}Z$`))
+				Expect(requests[0].Header.Get("Authorization")).To(MatchRegexp(
+					`^AWS4-HMAC-SHA256 Credential=some-access-key-id/\d{8}/some-region/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date;x-amz-security-token, Signature=[0-9a-f]{64}$`,
+				))
+			})
+
+			context("when no credentials are set", func() {
+				it.Before(func() {
+					Expect(os.Unsetenv("AWS_ACCESS_KEY_ID")).To(Succeed())
+					Expect(os.Unsetenv("AWS_SECRET_ACCESS_KEY")).To(Succeed())
+				})
+
+				it("makes an anonymous request", func() {
+					bundle, err := transport.Drop("", "s3://some-bucket/some-dir/some-object.tgz")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(bundle.Close()).To(Succeed())
+
+					Expect(requests).To(HaveLen(1))
+					Expect(requests[0].Header.Get("Authorization")).To(BeEmpty())
+				})
+			})
+
+			context("failure cases", func() {
+				context("when the uri has no key", func() {
+					it("returns an error", func() {
+						_, err := transport.Drop("", "s3://some-bucket")
+						Expect(err).To(MatchError(`failed to parse s3 uri: expected s3://<bucket>/<key> but got "s3://some-bucket"`))
+					})
+				})
+
+				context("when the object does not exist", func() {
+					it("returns an error", func() {
+						_, err := transport.Drop("", "s3://some-bucket/missing-object")
+						Expect(err).To(MatchError(`failed to fetch s3 object "s3://some-bucket/missing-object": unexpected response status 404 Not Found`))
+					})
+				})
+
+				context("when the request fails", func() {
+					it.Before(func() {
+						server.Close()
+					})
+
+					it("returns an error", func() {
+						_, err := transport.Drop("", "s3://some-bucket/some-dir/some-object.tgz")
+						Expect(err).To(MatchError(ContainSubstring("failed to make request")))
+					})
+				})
+			})
+		})
 	})
 }
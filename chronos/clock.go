@@ -5,19 +5,52 @@ import "time"
 var DefaultClock = NewClock(time.Now)
 
 type Clock struct {
-	now func() time.Time
+	now   func() time.Time
+	sleep func(time.Duration)
 }
 
 func NewClock(now func() time.Time) Clock {
-	return Clock{now: now}
+	return Clock{
+		now:   now,
+		sleep: time.Sleep,
+	}
+}
+
+// WithSleep returns a copy of the Clock that calls the given function
+// whenever it needs to pause, allowing tests to avoid real sleeping.
+func (c Clock) WithSleep(sleep func(time.Duration)) Clock {
+	c.sleep = sleep
+	return c
 }
 
 func (c Clock) Now() time.Time {
 	return c.now()
 }
 
+// Sleep pauses for the given duration.
+func (c Clock) Sleep(duration time.Duration) {
+	c.sleep(duration)
+}
+
 func (c Clock) Measure(f func() error) (time.Duration, error) {
 	then := c.Now()
 	err := f()
 	return c.Now().Sub(then), err
 }
+
+// Retry calls f until it succeeds, it returns an error for which retryable
+// returns false, or it has been called the given number of attempts. Between
+// attempts the Clock sleeps for the given backoff, doubling it each time. The
+// error from the final attempt is returned.
+func (c Clock) Retry(attempts int, backoff time.Duration, retryable func(error) bool, f func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = f()
+		if err == nil || attempt >= attempts || !retryable(err) {
+			return err
+		}
+
+		c.Sleep(backoff)
+		backoff *= 2
+	}
+}
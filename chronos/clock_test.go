@@ -2,6 +2,7 @@ package chronos_test
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -62,4 +63,59 @@ func testClock(t *testing.T, context spec.G, it spec.S) {
 			})
 		})
 	})
+
+	context("Retry", func() {
+		var (
+			clock  chronos.Clock
+			sleeps []time.Duration
+		)
+
+		it.Before(func() {
+			sleeps = nil
+			clock = chronos.NewClock(time.Now).WithSleep(func(duration time.Duration) {
+				sleeps = append(sleeps, duration)
+			})
+		})
+
+		it("retries the operation with an increasing backoff until it succeeds", func() {
+			var calls int
+			err := clock.Retry(5, time.Second, func(error) bool { return true }, func() error {
+				calls++
+				if calls < 3 {
+					return errors.New("temporary failure")
+				}
+
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal(3))
+			Expect(sleeps).To(Equal([]time.Duration{time.Second, 2 * time.Second}))
+		})
+
+		context("when the operation never succeeds", func() {
+			it("returns the error from the final attempt", func() {
+				var calls int
+				err := clock.Retry(3, time.Second, func(error) bool { return true }, func() error {
+					calls++
+					return fmt.Errorf("failure %d", calls)
+				})
+				Expect(err).To(MatchError("failure 3"))
+				Expect(calls).To(Equal(3))
+				Expect(sleeps).To(Equal([]time.Duration{time.Second, 2 * time.Second}))
+			})
+		})
+
+		context("when the error is not retryable", func() {
+			it("returns the error immediately", func() {
+				var calls int
+				err := clock.Retry(5, time.Second, func(error) bool { return false }, func() error {
+					calls++
+					return errors.New("permanent failure")
+				})
+				Expect(err).To(MatchError("permanent failure"))
+				Expect(calls).To(Equal(1))
+				Expect(sleeps).To(BeEmpty())
+			})
+		})
+	})
 }
@@ -3,7 +3,6 @@ package packit
 import (
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/BurntSushi/toml"
 	"github.com/paketo-buildpacks/packit/internal"
@@ -38,6 +37,11 @@ type DetectContext struct {
 	// Stack is the value of the chosen stack. This value is populated from the
 	// $CNB_STACK_ID environment variable.
 	Stack string
+
+	// Target is the os, architecture, and distribution the lifecycle selected
+	// for the build. Buildpacks can use these values to select dependencies
+	// that are compatible with the build and run images.
+	Target Target
 }
 
 // DetectResult allows buildpack authors to indicate the result of the detect
@@ -69,10 +73,7 @@ func Detect(f DetectFunc, options ...Option) {
 		return
 	}
 
-	cnbPath, ok := os.LookupEnv("CNB_BUILDPACK_DIR")
-	if !ok {
-		cnbPath = filepath.Clean(strings.TrimSuffix(config.args[0], filepath.Join("bin", "detect")))
-	}
+	cnbPath := config.buildpackPath("detect")
 
 	var buildpackInfo struct {
 		Buildpack BuildpackInfo `toml:"buildpack"`
@@ -86,18 +87,19 @@ func Detect(f DetectFunc, options ...Option) {
 	result, err := f(DetectContext{
 		WorkingDir: dir,
 		Platform: Platform{
-			Path: config.args[1],
+			Path: config.arg(config.platformPath, 1),
 		},
 		CNBPath:       cnbPath,
 		BuildpackInfo: buildpackInfo.Buildpack,
 		Stack:         os.Getenv("CNB_STACK_ID"),
+		Target:        targetFromEnvironment(),
 	})
 	if err != nil {
 		config.exitHandler.Error(err)
 		return
 	}
 
-	file, err := os.OpenFile(config.args[2], os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0644)
+	file, err := os.OpenFile(config.arg(config.planPath, 2), os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0644)
 	if err != nil {
 		config.exitHandler.Error(err)
 		return
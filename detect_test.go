@@ -115,6 +115,76 @@ api = "0.5"
 		})
 	})
 
+	context("when the target environment variables are set", func() {
+		it.Before(func() {
+			Expect(os.Setenv("CNB_TARGET_OS", "linux")).To(Succeed())
+			Expect(os.Setenv("CNB_TARGET_ARCH", "arm64")).To(Succeed())
+			Expect(os.Setenv("CNB_TARGET_ARCH_VARIANT", "v8")).To(Succeed())
+			Expect(os.Setenv("CNB_TARGET_DISTRO_NAME", "ubuntu")).To(Succeed())
+			Expect(os.Setenv("CNB_TARGET_DISTRO_VERSION", "22.04")).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("CNB_TARGET_OS")).To(Succeed())
+			Expect(os.Unsetenv("CNB_TARGET_ARCH")).To(Succeed())
+			Expect(os.Unsetenv("CNB_TARGET_ARCH_VARIANT")).To(Succeed())
+			Expect(os.Unsetenv("CNB_TARGET_DISTRO_NAME")).To(Succeed())
+			Expect(os.Unsetenv("CNB_TARGET_DISTRO_VERSION")).To(Succeed())
+		})
+
+		it("provides the target in the detect context", func() {
+			var context packit.DetectContext
+
+			packit.Detect(func(ctx packit.DetectContext) (packit.DetectResult, error) {
+				context = ctx
+
+				return packit.DetectResult{}, nil
+			}, packit.WithArgs([]string{binaryPath, platformDir, planPath}))
+
+			Expect(context.Target).To(Equal(packit.Target{
+				OS:          "linux",
+				Arch:        "arm64",
+				ArchVariant: "v8",
+				Distro: packit.TargetDistro{
+					Name:    "ubuntu",
+					Version: "22.04",
+				},
+			}))
+		})
+	})
+
+	context("when the paths are given as options", func() {
+		it("provides the detect context and writes the buildplan.toml to those paths", func() {
+			var context packit.DetectContext
+
+			packit.Detect(func(ctx packit.DetectContext) (packit.DetectResult, error) {
+				context = ctx
+
+				return packit.DetectResult{
+					Plan: packit.BuildPlan{
+						Provides: []packit.BuildPlanProvision{
+							{Name: "some-provision"},
+						},
+					},
+				}, nil
+			},
+				packit.WithCNBPath(cnbDir),
+				packit.WithPlatformPath(platformDir),
+				packit.WithPlanPath(planPath),
+			)
+
+			Expect(context.CNBPath).To(Equal(cnbDir))
+			Expect(context.Platform.Path).To(Equal(platformDir))
+
+			contents, err := os.ReadFile(planPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(contents)).To(MatchTOML(`
+				[[provides]]
+				name = "some-provision"
+			`))
+		})
+	})
+
 	it("writes out the buildplan.toml", func() {
 		packit.Detect(func(packit.DetectContext) (packit.DetectResult, error) {
 			return packit.DetectResult{
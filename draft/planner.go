@@ -41,7 +41,7 @@ func (p Planner) Resolve(name string, entries []packit.BuildpackPlanEntry, prior
 		return packit.BuildpackPlanEntry{}, nil
 	}
 
-	sort.Slice(filteredEntries, func(i, j int) bool {
+	sort.SliceStable(filteredEntries, func(i, j int) bool {
 		leftSource := filteredEntries[i].Metadata["version-source"]
 		left, _ := leftSource.(string)
 		leftPriority := -1
@@ -78,6 +78,47 @@ func (p Planner) Resolve(name string, entries []packit.BuildpackPlanEntry, prior
 	return filteredEntries[0], filteredEntries
 }
 
+// Dedupe takes a list of buildpack plan entries and a priority list of
+// version-sources, as described for Resolve, and returns a single entry for
+// each name in the order that the names first appear. The returned entry is
+// the highest priority entry for that name with its build and launch metadata
+// set to the OR merged state of every entry with that name. Entries of equal
+// priority are resolved in the order they were given.
+func (p Planner) Dedupe(entries []packit.BuildpackPlanEntry, priorities []interface{}) []packit.BuildpackPlanEntry {
+	var names []string
+	seen := map[string]bool{}
+	for _, e := range entries {
+		if !seen[e.Name] {
+			seen[e.Name] = true
+			names = append(names, e.Name)
+		}
+	}
+
+	var deduped []packit.BuildpackPlanEntry
+	for _, name := range names {
+		entry, _ := p.Resolve(name, entries, priorities)
+
+		metadata := map[string]interface{}{}
+		for key, value := range entry.Metadata {
+			metadata[key] = value
+		}
+
+		launch, build := p.MergeLayerTypes(name, entries)
+		if launch {
+			metadata["launch"] = true
+		}
+
+		if build {
+			metadata["build"] = true
+		}
+
+		entry.Metadata = metadata
+		deduped = append(deduped, entry)
+	}
+
+	return deduped
+}
+
 // MergeLayerTypes takes the name of buildpack plan entries that you want and
 // the list buildpack plan entries you want merged layered types from. It
 // returns the OR result of the launch and build keys for all of the buildpack
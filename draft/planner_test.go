@@ -260,6 +260,96 @@ func testPlanner(t *testing.T, context spec.G, it spec.S) {
 		})
 	})
 
+	context("Dedupe", func() {
+		it("returns a single merged entry for each name", func() {
+			entries := planner.Dedupe([]packit.BuildpackPlanEntry{
+				{
+					Name: "node",
+					Metadata: map[string]interface{}{
+						"version":        "other-version",
+						"version-source": "lowest",
+						"build":          true,
+					},
+				},
+				{
+					Name: "npm",
+					Metadata: map[string]interface{}{
+						"version": "some-version",
+					},
+				},
+				{
+					Name: "node",
+					Metadata: map[string]interface{}{
+						"version":        "some-version",
+						"version-source": "highest",
+					},
+				},
+				{
+					Name: "node",
+					Metadata: map[string]interface{}{
+						"version": "another-version",
+						"launch":  true,
+					},
+				},
+			}, priorities)
+
+			Expect(entries).To(Equal([]packit.BuildpackPlanEntry{
+				{
+					Name: "node",
+					Metadata: map[string]interface{}{
+						"version":        "some-version",
+						"version-source": "highest",
+						"build":          true,
+						"launch":         true,
+					},
+				},
+				{
+					Name: "npm",
+					Metadata: map[string]interface{}{
+						"version": "some-version",
+					},
+				},
+			}))
+		})
+
+		context("when entries have the same priority", func() {
+			it("keeps the first entry that was given", func() {
+				entries := planner.Dedupe([]packit.BuildpackPlanEntry{
+					{
+						Name: "node",
+						Metadata: map[string]interface{}{
+							"version":        "some-version",
+							"version-source": "lowest",
+						},
+					},
+					{
+						Name: "node",
+						Metadata: map[string]interface{}{
+							"version":        "other-version",
+							"version-source": "lowest",
+						},
+					},
+				}, priorities)
+
+				Expect(entries).To(Equal([]packit.BuildpackPlanEntry{
+					{
+						Name: "node",
+						Metadata: map[string]interface{}{
+							"version":        "some-version",
+							"version-source": "lowest",
+						},
+					},
+				}))
+			})
+		})
+
+		context("when there are no entries", func() {
+			it("returns no entries", func() {
+				Expect(planner.Dedupe(nil, priorities)).To(BeNil())
+			})
+		})
+	})
+
 	context("MergeLayerTypes", func() {
 		it("resolves the layer types from plan metadata", func() {
 			launch, build := planner.MergeLayerTypes("node", []packit.BuildpackPlanEntry{
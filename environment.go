@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // Environment provides a key-value store for declaring environment variables.
@@ -47,6 +48,55 @@ func (e Environment) Prepend(name, value, delim string) {
 	}
 }
 
+// Summary returns a description of the contribution the environment makes to
+// each variable, keyed by variable name. Each description names the modifier
+// and its value, along with the delimiter for prepended and appended values,
+// for example:
+//
+//	PATH -> prepend "/layers/some-layer/bin" with delimiter ":"
+//
+// A variable that is modified more than once has its descriptions joined by
+// ", " in the order override, default, prepend, append.
+func (e Environment) Summary() map[string]string {
+	var names []string
+	for key := range e {
+		index := strings.LastIndex(key, ".")
+		if index < 0 {
+			continue
+		}
+
+		names = append(names, key[:index])
+	}
+
+	summary := map[string]string{}
+	for _, name := range names {
+		if _, ok := summary[name]; ok {
+			continue
+		}
+
+		var descriptions []string
+		for _, modifier := range []string{"override", "default", "prepend", "append"} {
+			value, ok := e[name+"."+modifier]
+			if !ok {
+				continue
+			}
+
+			description := fmt.Sprintf("%s %q", modifier, value)
+			if delim, ok := e[name+".delim"]; ok && (modifier == "prepend" || modifier == "append") {
+				description = fmt.Sprintf("%s with delimiter %q", description, delim)
+			}
+
+			descriptions = append(descriptions, description)
+		}
+
+		if len(descriptions) > 0 {
+			summary[name] = strings.Join(descriptions, ", ")
+		}
+	}
+
+	return summary
+}
+
 func newEnvironmentFromPath(path string) (Environment, error) {
 	envFiles, err := filepath.Glob(filepath.Join(path, "*"))
 	if err != nil {
@@ -140,4 +140,28 @@ func testEnvironment(t *testing.T, context spec.G, it spec.S) {
 			})
 		})
 	})
+
+	context("Summary", func() {
+		it("describes the contribution to each variable", func() {
+			env := packit.Environment{}
+			env.Override("SOME_VAR", "some-value")
+			env.Default("OTHER_VAR", "other-value")
+			env.Prepend("PATH", "/some/bin", ":")
+			env.Append("PATH", "/other/bin", ":")
+			env.Append("FLAGS", "--some-flag", "")
+
+			Expect(env.Summary()).To(Equal(map[string]string{
+				"SOME_VAR":  `override "some-value"`,
+				"OTHER_VAR": `default "other-value"`,
+				"PATH":      `prepend "/some/bin" with delimiter ":", append "/other/bin" with delimiter ":"`,
+				"FLAGS":     `append "--some-flag"`,
+			}))
+		})
+
+		context("when the environment is empty", func() {
+			it("returns an empty summary", func() {
+				Expect(packit.Environment{}.Summary()).To(BeEmpty())
+			})
+		})
+	})
 }
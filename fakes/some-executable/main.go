@@ -3,9 +3,15 @@ package main
 import (
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
-var fail string
+var (
+	fail    string
+	signals string
+)
 
 func main() {
 	fmt.Fprintf(os.Stdout, "Output on stdout\n")
@@ -19,6 +25,21 @@ func main() {
 		fmt.Printf("%s\n", env)
 	}
 
+	switch signals {
+	case "trap":
+		received := make(chan os.Signal, 1)
+		signal.Notify(received, syscall.SIGINT, syscall.SIGTERM)
+
+		fmt.Fprintf(os.Stdout, "Waiting for signal\n")
+		fmt.Fprintf(os.Stdout, "Received signal: %s\n", <-received)
+
+	case "ignore":
+		signal.Ignore(syscall.SIGINT, syscall.SIGTERM)
+
+		fmt.Fprintf(os.Stdout, "Waiting for signal\n")
+		time.Sleep(time.Minute)
+	}
+
 	if fail == "true" {
 		fmt.Fprintf(os.Stdout, "Error on stdout\n")
 		fmt.Fprintf(os.Stderr, "Error on stderr\n")
@@ -0,0 +1,30 @@
+package fakes
+
+import "sync"
+
+type TOMLWriter struct {
+	WriteCall struct {
+		sync.Mutex
+		CallCount int
+		Receives  struct {
+			Path  string
+			Value interface{}
+		}
+		Returns struct {
+			Error error
+		}
+		Stub func(string, interface{}) error
+	}
+}
+
+func (f *TOMLWriter) Write(param1 string, param2 interface{}) error {
+	f.WriteCall.Lock()
+	defer f.WriteCall.Unlock()
+	f.WriteCall.CallCount++
+	f.WriteCall.Receives.Path = param1
+	f.WriteCall.Receives.Value = param2
+	if f.WriteCall.Stub != nil {
+		return f.WriteCall.Stub(param1, param2)
+	}
+	return f.WriteCall.Returns.Error
+}
@@ -0,0 +1,57 @@
+package fs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// AtomicWriteFile writes data to the file at path, creating it with the given
+// permissions if it does not exist. The data is first written to a temporary
+// file in the same directory, which is then renamed into place, so that
+// readers of the file only ever see either its previous contents or the new
+// contents in full, even if the process exits part of the way through writing.
+// Unlike os.WriteFile, the given permissions are applied to the file exactly
+// and are not modified by the umask.
+func AtomicWriteFile(path string, data []byte, perm os.FileMode) error {
+	file, err := os.CreateTemp(filepath.Dir(path), fmt.Sprintf(".%s.*", filepath.Base(path)))
+	if err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	// The temporary file is removed if any of the steps below fail. Once it
+	// has been renamed, this removal is a no-op.
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(data)
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	err = file.Chmod(perm)
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	// The contents are flushed to disk before the rename so that a crash
+	// cannot leave the file renamed into place but still empty.
+	err = file.Sync()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	err = os.Rename(file.Name(), path)
+	if err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
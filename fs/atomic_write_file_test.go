@@ -0,0 +1,143 @@
+package fs_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/fs"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testAtomicWriteFile(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		dir  string
+		path string
+	)
+
+	it.Before(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "atomic-write")
+		Expect(err).NotTo(HaveOccurred())
+
+		path = filepath.Join(dir, "some-file.toml")
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	it("writes the file with the given permissions", func() {
+		err := fs.AtomicWriteFile(path, []byte("some-content"), 0640)
+		Expect(err).NotTo(HaveOccurred())
+
+		content, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("some-content"))
+
+		info, err := os.Stat(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Mode()).To(Equal(os.FileMode(0640)))
+	})
+
+	context("when the file already exists", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(path, []byte("some-old-content"), 0644)).To(Succeed())
+		})
+
+		it("replaces the file and leaves no temporary files behind", func() {
+			err := fs.AtomicWriteFile(path, []byte("some-new-content"), 0600)
+			Expect(err).NotTo(HaveOccurred())
+
+			content, err := os.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("some-new-content"))
+
+			info, err := os.Stat(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode()).To(Equal(os.FileMode(0600)))
+
+			files, err := filepath.Glob(filepath.Join(dir, "*"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(ConsistOf(path))
+
+			hidden, err := filepath.Glob(filepath.Join(dir, ".*"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hidden).To(BeEmpty())
+		})
+	})
+
+	context("when the file is read while it is being rewritten", func() {
+		var oldContent, newContent []byte
+
+		it.Before(func() {
+			oldContent = bytes.Repeat([]byte("a"), 1<<20)
+			newContent = bytes.Repeat([]byte("b"), 1<<20)
+
+			Expect(os.WriteFile(path, oldContent, 0644)).To(Succeed())
+		})
+
+		it("only ever observes the old or the new contents in full", func() {
+			done := make(chan error)
+			go func() {
+				defer close(done)
+
+				for i := 0; i < 50; i++ {
+					content := newContent
+					if i%2 == 1 {
+						content = oldContent
+					}
+
+					err := fs.AtomicWriteFile(path, content, 0644)
+					if err != nil {
+						done <- err
+						return
+					}
+				}
+			}()
+
+			for {
+				select {
+				case err := <-done:
+					Expect(err).NotTo(HaveOccurred())
+					return
+				default:
+				}
+
+				content, err := os.ReadFile(path)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(bytes.Equal(content, oldContent) || bytes.Equal(content, newContent)).To(BeTrue(), "observed a partially written file")
+			}
+		})
+	})
+
+	context("failure cases", func() {
+		context("when the directory does not exist", func() {
+			it("returns an error", func() {
+				err := fs.AtomicWriteFile(filepath.Join(dir, "missing", "some-file"), []byte("some-content"), 0644)
+				Expect(err).To(MatchError(ContainSubstring("failed to write file:")))
+				Expect(err).To(MatchError(ContainSubstring("no such file or directory")))
+			})
+		})
+
+		context("when the file cannot be replaced", func() {
+			it.Before(func() {
+				Expect(os.MkdirAll(filepath.Join(path, "some-dir"), os.ModePerm)).To(Succeed())
+			})
+
+			it("returns an error and removes the temporary file", func() {
+				err := fs.AtomicWriteFile(path, []byte("some-content"), 0644)
+				Expect(err).To(MatchError(ContainSubstring("failed to write file:")))
+
+				hidden, err := filepath.Glob(filepath.Join(dir, ".*"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hidden).To(BeEmpty())
+			})
+		})
+	})
+}
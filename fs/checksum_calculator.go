@@ -3,6 +3,8 @@ package fs
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -14,11 +16,50 @@ import (
 // ChecksumCalculator can be used to calculate the SHA256 checksum of a given file or
 // directory. When given a directory, checksum calculation will be performed in
 // parallel.
-type ChecksumCalculator struct{}
+type ChecksumCalculator struct {
+	cacheDir    string
+	concurrency int
+}
+
+// ChecksumCalculatorOption is a function that configures a ChecksumCalculator
+// when passed to NewChecksumCalculator.
+type ChecksumCalculatorOption func(ChecksumCalculator) ChecksumCalculator
+
+// WithCache configures the ChecksumCalculator to record the checksum of each
+// file it reads in the given directory. On later calls to Sum, files whose
+// size and modification time are unchanged reuse their recorded checksum
+// rather than being read again.
+func WithCache(dir string) ChecksumCalculatorOption {
+	return func(c ChecksumCalculator) ChecksumCalculator {
+		c.cacheDir = dir
+		return c
+	}
+}
+
+// WithConcurrency limits the number of files that the ChecksumCalculator will
+// read in parallel. By default, the limit is the value of runtime.GOMAXPROCS,
+// which respects any GOMAXPROCS setting in the environment.
+func WithConcurrency(n int) ChecksumCalculatorOption {
+	return func(c ChecksumCalculator) ChecksumCalculator {
+		c.concurrency = n
+		return c
+	}
+}
 
 // NewChecksumCalculator returns a new instance of a ChecksumCalculator.
-func NewChecksumCalculator() ChecksumCalculator {
-	return ChecksumCalculator{}
+func NewChecksumCalculator(options ...ChecksumCalculatorOption) ChecksumCalculator {
+	var calculator ChecksumCalculator
+	for _, option := range options {
+		calculator = option(calculator)
+	}
+
+	return calculator
+}
+
+type cachedChecksum struct {
+	Size     int64  `json:"size"`
+	ModTime  int64  `json:"mod_time"`
+	Checksum string `json:"checksum"`
 }
 
 type calculatedFile struct {
@@ -29,7 +70,8 @@ type calculatedFile struct {
 
 // Sum returns a hex-encoded SHA256 checksum value of a file or directory given a path.
 func (c ChecksumCalculator) Sum(paths ...string) (string, error) {
-	var files []string
+	var walked []string
+	infos := map[string]os.FileInfo{}
 	for _, path := range paths {
 		err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
 			if err != nil {
@@ -37,7 +79,8 @@ func (c ChecksumCalculator) Sum(paths ...string) (string, error) {
 			}
 
 			if info.Mode().IsRegular() {
-				files = append(files, path)
+				walked = append(walked, path)
+				infos[path] = info
 			}
 
 			return nil
@@ -47,9 +90,58 @@ func (c ChecksumCalculator) Sum(paths ...string) (string, error) {
 		}
 	}
 
+	cache, err := c.readCache()
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate checksum: %w", err)
+	}
+
+	//Serve unchanged files from the cache
+	var files []string
+	var calculatedFiles []calculatedFile
+	for _, path := range walked {
+		info := infos[path]
+		entry, ok := cache[c.cacheKey(path)]
+		if ok && entry.Size == info.Size() && entry.ModTime == info.ModTime().UnixNano() {
+			checksum, err := hex.DecodeString(entry.Checksum)
+			if err == nil {
+				calculatedFiles = append(calculatedFiles, calculatedFile{path: path, checksum: checksum})
+				continue
+			}
+		}
+
+		files = append(files, path)
+	}
+
+	concurrency := c.concurrency
+	if concurrency < 1 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+
+	for _, f := range getParallelChecksums(files, concurrency, fileChecksum) {
+		if f.err == nil {
+			cache[c.cacheKey(f.path)] = cachedChecksum{
+				Size:     infos[f.path].Size(),
+				ModTime:  infos[f.path].ModTime().UnixNano(),
+				Checksum: hex.EncodeToString(f.checksum),
+			}
+		}
+
+		calculatedFiles = append(calculatedFiles, f)
+	}
+
+	err = c.writeCache(cache)
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate checksum: %w", err)
+	}
+
+	//Sort calculated files for consistent checksuming
+	sort.Slice(calculatedFiles, func(i, j int) bool {
+		return calculatedFiles[i].path < calculatedFiles[j].path
+	})
+
 	//Gather all checksums
 	var sums [][]byte
-	for _, f := range getParallelChecksums(files) {
+	for _, f := range calculatedFiles {
 		if f.err != nil {
 			return "", fmt.Errorf("failed to calculate checksum: %w", f.err)
 		}
@@ -71,15 +163,65 @@ func (c ChecksumCalculator) Sum(paths ...string) (string, error) {
 	return hex.EncodeToString(hash.Sum(nil)), nil
 }
 
-func getParallelChecksums(filesFromDir []string) []calculatedFile {
+func (c ChecksumCalculator) cacheKey(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+
+	return abs
+}
+
+func (c ChecksumCalculator) readCache() (map[string]cachedChecksum, error) {
+	cache := map[string]cachedChecksum{}
+	if c.cacheDir == "" {
+		return cache, nil
+	}
+
+	content, err := os.ReadFile(filepath.Join(c.cacheDir, "checksums.json"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cache, nil
+		}
+
+		return nil, err
+	}
+
+	err = json.Unmarshal(content, &cache)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse checksum cache: %w", err)
+	}
+
+	return cache, nil
+}
+
+func (c ChecksumCalculator) writeCache(cache map[string]cachedChecksum) error {
+	if c.cacheDir == "" {
+		return nil
+	}
+
+	content, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(c.cacheDir, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(c.cacheDir, "checksums.json"), content, 0644)
+}
+
+func getParallelChecksums(filesFromDir []string, concurrency int, checksum func(path string) ([]byte, error)) []calculatedFile {
 	var checksumResults []calculatedFile
 	numFiles := len(filesFromDir)
 	files := make(chan string, numFiles)
 	calculatedFiles := make(chan calculatedFile, numFiles)
 
 	//Spawns workers
-	for i := 0; i < runtime.NumCPU(); i++ {
-		go fileChecksumer(files, calculatedFiles)
+	for i := 0; i < concurrency; i++ {
+		go fileChecksumer(files, calculatedFiles, checksum)
 	}
 
 	//Puts files in worker queue
@@ -102,32 +244,30 @@ func getParallelChecksums(filesFromDir []string) []calculatedFile {
 	return checksumResults
 }
 
-func fileChecksumer(files chan string, calculatedFiles chan calculatedFile) {
+func fileChecksumer(files chan string, calculatedFiles chan calculatedFile, checksum func(path string) ([]byte, error)) {
 	for path := range files {
-		result := calculatedFile{path: path}
-
-		file, err := os.Open(path)
-		if err != nil {
-			result.err = err
-			calculatedFiles <- result
-			continue
-		}
+		sum, err := checksum(path)
+		calculatedFiles <- calculatedFile{path: path, checksum: sum, err: err}
+	}
+}
 
-		hash := sha256.New()
-		_, err = io.Copy(hash, file)
-		if err != nil {
-			result.err = err
-			calculatedFiles <- result
-			continue
-		}
+func fileChecksum(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
 
-		if err := file.Close(); err != nil {
-			result.err = err
-			calculatedFiles <- result
-			continue
-		}
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
 
-		result.checksum = hash.Sum(nil)
-		calculatedFiles <- result
+	err = file.Close()
+	if err != nil {
+		return nil, err
 	}
+
+	return hash.Sum(nil), nil
 }
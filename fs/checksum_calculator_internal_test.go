@@ -0,0 +1,74 @@
+package fs
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestUnitFSInternal(t *testing.T) {
+	suite := spec.New("packit/fs/internal", spec.Report(report.Terminal{}))
+	suite("getParallelChecksums", testGetParallelChecksums)
+	suite.Run(t)
+}
+
+func testGetParallelChecksums(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		files    []string
+		checksum func(path string) ([]byte, error)
+
+		mutex             sync.Mutex
+		running, observed int
+	)
+
+	it.Before(func() {
+		files = nil
+		for i := 0; i < 20; i++ {
+			files = append(files, fmt.Sprintf("file-%02d", i))
+		}
+
+		running, observed = 0, 0
+		checksum = func(path string) ([]byte, error) {
+			mutex.Lock()
+			running++
+			if running > observed {
+				observed = running
+			}
+			mutex.Unlock()
+
+			time.Sleep(5 * time.Millisecond)
+
+			mutex.Lock()
+			running--
+			mutex.Unlock()
+
+			return []byte(path), nil
+		}
+	})
+
+	it("never runs more checksums at once than the given concurrency", func() {
+		results := getParallelChecksums(files, 3, checksum)
+		Expect(results).To(HaveLen(20))
+		Expect(results[0]).To(Equal(calculatedFile{path: "file-00", checksum: []byte("file-00")}))
+
+		Expect(observed).To(BeNumerically("<=", 3))
+		Expect(observed).To(BeNumerically(">", 1))
+	})
+
+	context("when the concurrency is 1", func() {
+		it("calculates the checksums one at a time", func() {
+			results := getParallelChecksums(files, 1, checksum)
+			Expect(results).To(HaveLen(20))
+
+			Expect(observed).To(Equal(1))
+		})
+	})
+}
@@ -146,6 +146,18 @@ func testChecksumCalculator(t *testing.T, context spec.G, it spec.S) {
 				Expect(sum).To(Equal("9fb03d22515ca48e57b578de80bbc1e75d5126dbb2de6db177947c3da3b2276f"))
 			})
 
+			context("when the concurrency is limited", func() {
+				it.Before(func() {
+					calculator = fs.NewChecksumCalculator(fs.WithConcurrency(1))
+				})
+
+				it("returns the same 256 sha sum", func() {
+					sum, err := calculator.Sum(dir1, dir2)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(sum).To(Equal("9fb03d22515ca48e57b578de80bbc1e75d5126dbb2de6db177947c3da3b2276f"))
+				})
+			})
+
 			context("failure cases", func() {
 				context("when one of the directories cannot be read", func() {
 					it.Before(func() {
@@ -220,6 +232,67 @@ func testChecksumCalculator(t *testing.T, context spec.G, it spec.S) {
 			})
 		})
 
+		context("when given a cache directory", func() {
+			var (
+				cacheDir           string
+				changed, unchanged string
+				unchangedInfo      os.FileInfo
+			)
+
+			it.Before(func() {
+				cacheDir = filepath.Join(workingDir, "cache")
+				calculator = fs.NewChecksumCalculator(fs.WithCache(cacheDir))
+
+				dir := filepath.Join(workingDir, "some-dir")
+				Expect(os.MkdirAll(dir, os.ModePerm)).To(Succeed())
+
+				changed = filepath.Join(dir, "changed-file")
+				Expect(os.WriteFile(changed, []byte("some-content"), 0644)).To(Succeed())
+
+				unchanged = filepath.Join(dir, "unchanged-file")
+				Expect(os.WriteFile(unchanged, []byte("some-content"), 0644)).To(Succeed())
+
+				var err error
+				unchangedInfo, err = os.Stat(unchanged)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = calculator.Sum(dir)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(filepath.Join(cacheDir, "checksums.json")).To(BeARegularFile())
+			})
+
+			it("serves unchanged files from the cache and recalculates changed files", func() {
+				// Rewrite the unchanged file with the same size and modification time
+				// so that only a cache hit can produce its original checksum.
+				Expect(os.WriteFile(unchanged, []byte("other-conten"), 0644)).To(Succeed())
+				Expect(os.Chtimes(unchanged, unchangedInfo.ModTime(), unchangedInfo.ModTime())).To(Succeed())
+
+				Expect(os.WriteFile(changed, []byte("some-other-content"), 0644)).To(Succeed())
+
+				sum, err := calculator.Sum(unchanged)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sum).To(Equal("0a8cac771ca188eacc57e2c96c31f5611925c5ecedccb16b8c236d6c0d325112"))
+
+				sum, err = calculator.Sum(changed)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sum).To(Equal("7a3e8221a00c6a0602f73a4fab1488049c0b54deff57c279bd00d19e3a9fde8d"))
+			})
+
+			context("failure cases", func() {
+				context("when the cache is malformed", func() {
+					it.Before(func() {
+						Expect(os.WriteFile(filepath.Join(cacheDir, "checksums.json"), []byte("%%%"), 0644)).To(Succeed())
+					})
+
+					it("returns an error", func() {
+						_, err := calculator.Sum(changed)
+						Expect(err).To(MatchError(ContainSubstring("failed to parse checksum cache")))
+					})
+				})
+			})
+		})
+
 		context("failure cases", func() {
 			context("when any of the given paths do not exist", func() {
 				it("returns an error", func() {
@@ -1,18 +1,85 @@
 package fs
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 )
 
 // Copy will move a source file or directory to a destination. For directories,
 // move will remap relative symlinks ensuring that they align with the
-// destination directory. If the destination exists prior to invocation, it
-// will be removed.
+// destination directory. The files within a directory are copied in
+// parallel. If the destination exists prior to invocation, it will be removed.
 func Copy(source, destination string) error {
+	return CopyWithContext(context.Background(), source, destination)
+}
+
+// CopyWithContext behaves like Copy, but stops copying when the given context
+// is cancelled. The context is checked between files and while the contents
+// of each file are copied. If the copy is stopped, anything already written
+// to the destination is removed and the context error is returned.
+func CopyWithContext(ctx context.Context, source, destination string) error {
+	return copyWithConfig(ctx, source, destination, CopyConfig{})
+}
+
+// CopyConfig is the set of configurable options for CopyWithOptions.
+type CopyConfig struct {
+	exclude []string
+}
+
+// CopyOption declares a function signature that can be used to define
+// optional modifications to the behavior of CopyWithOptions.
+type CopyOption func(config CopyConfig) CopyConfig
+
+// WithExclude is a CopyOption that skips any file or directory within the
+// source directory whose path, relative to the source directory, matches one
+// of the given glob patterns. Patterns use the syntax of filepath.Match. When
+// a directory is excluded, nothing inside of it is copied.
+func WithExclude(globs ...string) CopyOption {
+	return func(config CopyConfig) CopyConfig {
+		config.exclude = append(config.exclude, globs...)
+		return config
+	}
+}
+
+// CopyWithOptions behaves like Copy, but modifies its behavior according to
+// the given options.
+func CopyWithOptions(source, destination string, options ...CopyOption) error {
+	var config CopyConfig
+	for _, option := range options {
+		config = option(config)
+	}
+
+	for _, glob := range config.exclude {
+		_, err := filepath.Match(glob, "")
+		if err != nil {
+			return fmt.Errorf("failed to copy: invalid exclude pattern %q: %w", glob, err)
+		}
+	}
+
+	return copyWithConfig(context.Background(), source, destination, config)
+}
+
+// excludes reports whether the given path, relative to the source directory,
+// matches one of the exclude patterns.
+func (c CopyConfig) excludes(path string) bool {
+	for _, glob := range c.exclude {
+		// The patterns are validated before the copy begins, so the error can
+		// be ignored here.
+		if match, _ := filepath.Match(glob, filepath.ToSlash(path)); match {
+			return true
+		}
+	}
+
+	return false
+}
+
+func copyWithConfig(ctx context.Context, source, destination string, config CopyConfig) error {
 	err := os.Remove(destination)
 	if err != nil {
 		if !errors.Is(err, os.ErrNotExist) {
@@ -26,21 +93,23 @@ func Copy(source, destination string) error {
 	}
 
 	if info.IsDir() {
-		err = copyDirectory(source, destination)
-		if err != nil {
-			return err
-		}
+		err = copyDirectory(ctx, source, destination, config)
 	} else {
-		err = copyFile(source, destination)
-		if err != nil {
-			return err
+		err = copyFile(ctx, source, destination)
+	}
+
+	if err != nil {
+		if ctx.Err() != nil {
+			os.RemoveAll(destination)
 		}
+
+		return err
 	}
 
 	return nil
 }
 
-func copyFile(source, destination string) error {
+func copyFile(ctx context.Context, source, destination string) error {
 	sourceFile, err := os.Open(source)
 	if err != nil {
 		return err
@@ -53,7 +122,7 @@ func copyFile(source, destination string) error {
 	}
 	defer destinationFile.Close()
 
-	_, err = io.Copy(destinationFile, sourceFile)
+	_, err = io.Copy(destinationFile, contextReader{ctx: ctx, reader: sourceFile})
 	if err != nil {
 		return err
 	}
@@ -71,17 +140,34 @@ func copyFile(source, destination string) error {
 	return nil
 }
 
-func copyDirectory(source, destination string) error {
+func copyDirectory(ctx context.Context, source, destination string, config CopyConfig) error {
+	// Directories are created during the walk so that they exist before any of
+	// the files inside of them are copied. Files are then copied in parallel and
+	// symlinks are created last.
+	var files, links []string
 	err := filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 
+		err = ctx.Err()
+		if err != nil {
+			return err
+		}
+
 		path, err = filepath.Rel(source, path)
 		if err != nil {
 			return err
 		}
 
+		if path != "." && config.excludes(path) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
 		switch {
 		case info.IsDir():
 			err = os.Mkdir(filepath.Join(destination, path), os.ModePerm)
@@ -90,16 +176,10 @@ func copyDirectory(source, destination string) error {
 			}
 
 		case (info.Mode() & os.ModeSymlink) != 0:
-			err = copyLink(source, destination, path)
-			if err != nil {
-				return err
-			}
+			links = append(links, path)
 
 		default:
-			err = copyFile(filepath.Join(source, path), filepath.Join(destination, path))
-			if err != nil {
-				return err
-			}
+			files = append(files, path)
 		}
 
 		return nil
@@ -109,9 +189,67 @@ func copyDirectory(source, destination string) error {
 		return err
 	}
 
+	for _, f := range getParallelCopies(ctx, source, destination, files) {
+		if f.err != nil {
+			return f.err
+		}
+	}
+
+	for _, path := range links {
+		err = copyLink(source, destination, path)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+type copiedFile struct {
+	path string
+	err  error
+}
+
+func getParallelCopies(ctx context.Context, source, destination string, filesFromDir []string) []copiedFile {
+	var copyResults []copiedFile
+	numFiles := len(filesFromDir)
+	files := make(chan string, numFiles)
+	copiedFiles := make(chan copiedFile, numFiles)
+
+	//Spawns workers
+	for i := 0; i < runtime.NumCPU(); i++ {
+		go fileCopier(ctx, source, destination, files, copiedFiles)
+	}
+
+	//Puts files in worker queue
+	for _, f := range filesFromDir {
+		files <- f
+	}
+
+	close(files)
+
+	//Pull all copied files off of result queue
+	for i := 0; i < numFiles; i++ {
+		copyResults = append(copyResults, <-copiedFiles)
+	}
+
+	//Sort copied files so that errors are reported consistently
+	sort.Slice(copyResults, func(i, j int) bool {
+		return copyResults[i].path < copyResults[j].path
+	})
+
+	return copyResults
+}
+
+func fileCopier(ctx context.Context, source, destination string, files chan string, copiedFiles chan copiedFile) {
+	for path := range files {
+		copiedFiles <- copiedFile{
+			path: path,
+			err:  copyFile(ctx, filepath.Join(source, path), filepath.Join(destination, path)),
+		}
+	}
+}
+
 func copyLink(source, destination, path string) error {
 	link, err := os.Readlink(filepath.Join(source, path))
 	if err != nil {
@@ -125,3 +263,19 @@ func copyLink(source, destination, path string) error {
 
 	return nil
 }
+
+// contextReader stops reading from the wrapped reader once its context has
+// been cancelled.
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	err := r.ctx.Err()
+	if err != nil {
+		return 0, err
+	}
+
+	return r.reader.Read(p)
+}
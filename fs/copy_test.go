@@ -1,6 +1,9 @@
 package fs_test
 
 import (
+	"bytes"
+	gocontext "context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -146,6 +149,41 @@ func testCopy(t *testing.T, context spec.G, it spec.S) {
 				})
 			})
 
+			context("when the source directory contains many files", func() {
+				it.Before(func() {
+					for i := 0; i < 100; i++ {
+						dir := filepath.Join(source, fmt.Sprintf("dir-%d", i%10))
+						Expect(os.MkdirAll(dir, os.ModePerm)).To(Succeed())
+
+						err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("file-%d", i)), []byte(fmt.Sprintf("content-%d", i)), os.FileMode(0600+i%2*0100))
+						Expect(err).NotTo(HaveOccurred())
+					}
+				})
+
+				it("copies every file with the same contents and mode", func() {
+					err := fs.Copy(source, destination)
+					Expect(err).NotTo(HaveOccurred())
+
+					calculator := fs.NewChecksumCalculator()
+
+					sourceSum, err := calculator.Sum(source)
+					Expect(err).NotTo(HaveOccurred())
+
+					destinationSum, err := calculator.Sum(destination)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(destinationSum).To(Equal(sourceSum))
+
+					for i := 0; i < 100; i++ {
+						path := filepath.Join(fmt.Sprintf("dir-%d", i%10), fmt.Sprintf("file-%d", i))
+
+						info, err := os.Stat(filepath.Join(destination, path))
+						Expect(err).NotTo(HaveOccurred())
+						Expect(info.Mode()).To(Equal(os.FileMode(0600 + i%2*0100)))
+					}
+				})
+			})
+
 			context("when the destination is a file", func() {
 				it.Before(func() {
 					Expect(os.RemoveAll(destination))
@@ -207,4 +245,202 @@ func testCopy(t *testing.T, context spec.G, it spec.S) {
 			})
 		})
 	})
+	context("CopyWithContext", func() {
+		var (
+			sourceDir      string
+			destinationDir string
+		)
+
+		it.Before(func() {
+			var err error
+			sourceDir, err = os.MkdirTemp("", "source")
+			Expect(err).NotTo(HaveOccurred())
+
+			destinationDir, err = os.MkdirTemp("", "destination")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(os.MkdirAll(filepath.Join(sourceDir, "some-dir"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(sourceDir, "some-dir", "some-file"), []byte("some-content"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(sourceDir, "large-file"), bytes.Repeat([]byte("x"), 1024*1024), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.RemoveAll(sourceDir)).To(Succeed())
+			Expect(os.RemoveAll(destinationDir)).To(Succeed())
+		})
+
+		it("copies the source directory to the destination", func() {
+			destination := filepath.Join(destinationDir, "destination")
+
+			err := fs.CopyWithContext(gocontext.Background(), sourceDir, destination)
+			Expect(err).NotTo(HaveOccurred())
+
+			content, err := os.ReadFile(filepath.Join(destination, "some-dir", "some-file"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("some-content"))
+
+			info, err := os.Stat(filepath.Join(destination, "large-file"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Size()).To(Equal(int64(1024 * 1024)))
+		})
+
+		context("when the context is cancelled before the copy starts", func() {
+			it("returns the context error and leaves nothing behind", func() {
+				ctx, cancel := gocontext.WithCancel(gocontext.Background())
+				cancel()
+
+				destination := filepath.Join(destinationDir, "destination")
+
+				err := fs.CopyWithContext(ctx, sourceDir, destination)
+				Expect(err).To(MatchError(gocontext.Canceled))
+				Expect(destination).NotTo(BeAnExistingFile())
+			})
+		})
+
+		context("when the context is cancelled during the copy of a large file", func() {
+			it("returns the context error and removes the partial file", func() {
+				ctx := &cancelAfterContext{Context: gocontext.Background(), calls: 4}
+				destination := filepath.Join(destinationDir, "destination")
+
+				err := fs.CopyWithContext(ctx, filepath.Join(sourceDir, "large-file"), destination)
+				Expect(err).To(MatchError(gocontext.Canceled))
+				Expect(ctx.calls).To(Equal(0))
+				Expect(destination).NotTo(BeAnExistingFile())
+			})
+		})
+	})
+
+	context("CopyWithOptions", func() {
+		var (
+			sourceDir      string
+			destinationDir string
+		)
+
+		it.Before(func() {
+			var err error
+			sourceDir, err = os.MkdirTemp("", "source")
+			Expect(err).NotTo(HaveOccurred())
+
+			destinationDir, err = os.MkdirTemp("", "destination")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(os.MkdirAll(filepath.Join(sourceDir, ".git", "objects"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(sourceDir, ".git", "objects", "some-object"), []byte("some-object"), 0644)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(sourceDir, "docs"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(sourceDir, "docs", "README.md"), []byte("some-docs"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(sourceDir, "docs", "diagram.png"), []byte("some-diagram"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(sourceDir, "some-file"), []byte("some-content"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(sourceDir, "some-file.md"), []byte("some-notes"), 0644)).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.RemoveAll(sourceDir)).To(Succeed())
+			Expect(os.RemoveAll(destinationDir)).To(Succeed())
+		})
+
+		it("copies everything when no options are given", func() {
+			destination := filepath.Join(destinationDir, "destination")
+
+			err := fs.CopyWithOptions(sourceDir, destination)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(filepath.Join(destination, ".git", "objects", "some-object")).To(BeARegularFile())
+			Expect(filepath.Join(destination, "docs", "README.md")).To(BeARegularFile())
+			Expect(filepath.Join(destination, "some-file.md")).To(BeARegularFile())
+		})
+
+		context("when a subdirectory is excluded", func() {
+			it("does not copy the subdirectory or anything inside of it", func() {
+				destination := filepath.Join(destinationDir, "destination")
+
+				err := fs.CopyWithOptions(sourceDir, destination, fs.WithExclude(".git"))
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(filepath.Join(destination, ".git")).NotTo(BeAnExistingFile())
+				Expect(filepath.Join(destination, "docs", "README.md")).To(BeARegularFile())
+				Expect(filepath.Join(destination, "some-file")).To(BeARegularFile())
+			})
+		})
+
+		context("when a glob is excluded", func() {
+			it("does not copy the files whose relative path matches the glob", func() {
+				destination := filepath.Join(destinationDir, "destination")
+
+				err := fs.CopyWithOptions(sourceDir, destination, fs.WithExclude("*.md", "docs/*.md"))
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(filepath.Join(destination, "some-file.md")).NotTo(BeAnExistingFile())
+				Expect(filepath.Join(destination, "docs", "README.md")).NotTo(BeAnExistingFile())
+				Expect(filepath.Join(destination, "docs", "diagram.png")).To(BeARegularFile())
+				Expect(filepath.Join(destination, "some-file")).To(BeARegularFile())
+
+				content, err := os.ReadFile(filepath.Join(destination, "some-file"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(Equal("some-content"))
+			})
+		})
+
+		context("failure cases", func() {
+			context("when an exclude pattern is malformed", func() {
+				it("returns an error", func() {
+					destination := filepath.Join(destinationDir, "destination")
+
+					err := fs.CopyWithOptions(sourceDir, destination, fs.WithExclude("["))
+					Expect(err).To(MatchError(ContainSubstring(`failed to copy: invalid exclude pattern "["`)))
+					Expect(destination).NotTo(BeAnExistingFile())
+				})
+			})
+		})
+	})
+}
+
+// cancelAfterContext reports itself as cancelled after Err has been called a
+// given number of times so that a copy can be interrupted part way through.
+type cancelAfterContext struct {
+	gocontext.Context
+	calls int
+}
+
+func (c *cancelAfterContext) Err() error {
+	if c.calls > 0 {
+		c.calls--
+		return nil
+	}
+
+	return gocontext.Canceled
+}
+
+func BenchmarkCopy(b *testing.B) {
+	source, err := os.MkdirTemp("", "source")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.RemoveAll(source)
+
+	for i := 0; i < 1000; i++ {
+		dir := filepath.Join(source, fmt.Sprintf("dir-%d", i%50))
+		err = os.MkdirAll(dir, os.ModePerm)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		err = os.WriteFile(filepath.Join(dir, fmt.Sprintf("file-%d", i)), bytes.Repeat([]byte("x"), 64*1024), 0644)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	destinationDir, err := os.MkdirTemp("", "destination")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.RemoveAll(destinationDir)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err = fs.Copy(source, filepath.Join(destinationDir, fmt.Sprintf("destination-%d", i)))
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
 }
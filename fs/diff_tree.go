@@ -0,0 +1,119 @@
+package fs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// DiffTree compares the directory trees rooted at a and b. It returns the
+// paths, relative to the tree roots, of files that exist only in b (added),
+// files that exist only in a (removed), and files that exist in both but whose
+// contents, symlink target, or mode differ (modified). Directories themselves
+// are not reported. Each of the returned slices is sorted.
+func DiffTree(a, b string) (added, removed, modified []string, err error) {
+	before, err := walkTree(a)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to diff tree: %w", err)
+	}
+
+	after, err := walkTree(b)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to diff tree: %w", err)
+	}
+
+	calculator := NewChecksumCalculator()
+	for path, afterInfo := range after {
+		beforeInfo, ok := before[path]
+		if !ok {
+			added = append(added, path)
+			continue
+		}
+
+		changed, err := fileChanged(calculator, filepath.Join(a, path), beforeInfo, filepath.Join(b, path), afterInfo)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to diff tree: %w", err)
+		}
+
+		if changed {
+			modified = append(modified, path)
+		}
+	}
+
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			removed = append(removed, path)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Strings(modified)
+
+	return added, removed, modified, nil
+}
+
+// walkTree returns the file info of every non-directory entry in the tree
+// rooted at root, keyed by its path relative to root.
+func walkTree(root string) (map[string]os.FileInfo, error) {
+	infos := map[string]os.FileInfo{}
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		infos[rel] = info
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return infos, nil
+}
+
+func fileChanged(calculator ChecksumCalculator, beforePath string, beforeInfo os.FileInfo, afterPath string, afterInfo os.FileInfo) (bool, error) {
+	if beforeInfo.Mode() != afterInfo.Mode() {
+		return true, nil
+	}
+
+	if beforeInfo.Mode()&os.ModeSymlink != 0 {
+		beforeTarget, err := os.Readlink(beforePath)
+		if err != nil {
+			return false, err
+		}
+
+		afterTarget, err := os.Readlink(afterPath)
+		if err != nil {
+			return false, err
+		}
+
+		return beforeTarget != afterTarget, nil
+	}
+
+	if beforeInfo.Size() != afterInfo.Size() {
+		return true, nil
+	}
+
+	beforeSum, err := calculator.Sum(beforePath)
+	if err != nil {
+		return false, err
+	}
+
+	afterSum, err := calculator.Sum(afterPath)
+	if err != nil {
+		return false, err
+	}
+
+	return beforeSum != afterSum, nil
+}
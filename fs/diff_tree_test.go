@@ -0,0 +1,131 @@
+package fs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/fs"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testDiffTree(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		before string
+		after  string
+	)
+
+	it.Before(func() {
+		var err error
+		before, err = os.MkdirTemp("", "before")
+		Expect(err).NotTo(HaveOccurred())
+
+		after, err = os.MkdirTemp("", "after")
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, dir := range []string{before, after} {
+			Expect(os.MkdirAll(filepath.Join(dir, "some-dir"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "some-file"), []byte("some-content"), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(dir, "some-dir", "nested-file"), []byte("nested-content"), 0644)).To(Succeed())
+			Expect(os.Symlink("some-file", filepath.Join(dir, "some-link"))).To(Succeed())
+		}
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(before)).To(Succeed())
+		Expect(os.RemoveAll(after)).To(Succeed())
+	})
+
+	it("reports no differences between identical trees", func() {
+		added, removed, modified, err := fs.DiffTree(before, after)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(added).To(BeEmpty())
+		Expect(removed).To(BeEmpty())
+		Expect(modified).To(BeEmpty())
+	})
+
+	context("when a file is added", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(after, "some-dir", "added-file"), []byte("added-content"), 0644)).To(Succeed())
+		})
+
+		it("reports the file as added", func() {
+			added, removed, modified, err := fs.DiffTree(before, after)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(added).To(Equal([]string{filepath.Join("some-dir", "added-file")}))
+			Expect(removed).To(BeEmpty())
+			Expect(modified).To(BeEmpty())
+		})
+	})
+
+	context("when a file is removed", func() {
+		it.Before(func() {
+			Expect(os.Remove(filepath.Join(after, "some-dir", "nested-file"))).To(Succeed())
+		})
+
+		it("reports the file as removed", func() {
+			added, removed, modified, err := fs.DiffTree(before, after)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(added).To(BeEmpty())
+			Expect(removed).To(Equal([]string{filepath.Join("some-dir", "nested-file")}))
+			Expect(modified).To(BeEmpty())
+		})
+	})
+
+	context("when the contents of a file change", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(after, "some-file"), []byte("other-content"), 0644)).To(Succeed())
+		})
+
+		it("reports the file as modified", func() {
+			added, removed, modified, err := fs.DiffTree(before, after)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(added).To(BeEmpty())
+			Expect(removed).To(BeEmpty())
+			Expect(modified).To(Equal([]string{"some-file"}))
+		})
+	})
+
+	context("when only the mode of a file changes", func() {
+		it.Before(func() {
+			Expect(os.Chmod(filepath.Join(after, "some-dir", "nested-file"), 0755)).To(Succeed())
+		})
+
+		it("reports the file as modified", func() {
+			added, removed, modified, err := fs.DiffTree(before, after)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(added).To(BeEmpty())
+			Expect(removed).To(BeEmpty())
+			Expect(modified).To(Equal([]string{filepath.Join("some-dir", "nested-file")}))
+		})
+	})
+
+	context("when the target of a symlink changes", func() {
+		it.Before(func() {
+			Expect(os.Remove(filepath.Join(after, "some-link"))).To(Succeed())
+			Expect(os.Symlink(filepath.Join("some-dir", "nested-file"), filepath.Join(after, "some-link"))).To(Succeed())
+		})
+
+		it("reports the symlink as modified", func() {
+			added, removed, modified, err := fs.DiffTree(before, after)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(added).To(BeEmpty())
+			Expect(removed).To(BeEmpty())
+			Expect(modified).To(Equal([]string{"some-link"}))
+		})
+	})
+
+	context("failure cases", func() {
+		context("when a tree does not exist", func() {
+			it("returns an error", func() {
+				_, _, _, err := fs.DiffTree(before, "no-such-dir")
+				Expect(err).To(MatchError(ContainSubstring("failed to diff tree:")))
+				Expect(err).To(MatchError(ContainSubstring("no such file or directory")))
+			})
+		})
+	})
+}
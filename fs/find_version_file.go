@@ -0,0 +1,31 @@
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FindVersionFile looks in the given directory for each of the candidate
+// files in priority order, such as ".nvmrc" or "buildpack.yml". It returns the
+// trimmed contents of the first candidate that exists along with its name so
+// that detect can use the name as the version-source of a requirement. If
+// none of the candidates exist, the returned name is empty.
+func FindVersionFile(dir string, candidates ...string) (string, string, error) {
+	for _, candidate := range candidates {
+		content, err := os.ReadFile(filepath.Join(dir, candidate))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			return "", "", fmt.Errorf("failed to read version file: %w", err)
+		}
+
+		return strings.TrimSpace(string(content)), candidate, nil
+	}
+
+	return "", "", nil
+}
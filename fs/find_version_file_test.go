@@ -0,0 +1,76 @@
+package fs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/fs"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testFindVersionFile(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		workingDir string
+	)
+
+	it.Before(func() {
+		var err error
+		workingDir, err = os.MkdirTemp("", "working-dir")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.WriteFile(filepath.Join(workingDir, ".node-version"), []byte("16.4.0\n"), 0644)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(workingDir, "buildpack.yml"), []byte("nodejs:\n  version: 12.*\n"), 0644)).To(Succeed())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(workingDir)).To(Succeed())
+	})
+
+	it("returns the contents and name of the first candidate that exists", func() {
+		content, source, err := fs.FindVersionFile(workingDir, ".nvmrc", ".node-version", "buildpack.yml")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(content).To(Equal("16.4.0"))
+		Expect(source).To(Equal(".node-version"))
+	})
+
+	context("when a higher priority candidate also exists", func() {
+		it.Before(func() {
+			Expect(os.WriteFile(filepath.Join(workingDir, ".nvmrc"), []byte("14\n"), 0644)).To(Succeed())
+		})
+
+		it("returns that candidate", func() {
+			content, source, err := fs.FindVersionFile(workingDir, ".nvmrc", ".node-version", "buildpack.yml")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content).To(Equal("14"))
+			Expect(source).To(Equal(".nvmrc"))
+		})
+	})
+
+	context("when none of the candidates exist", func() {
+		it("returns an empty source", func() {
+			content, source, err := fs.FindVersionFile(workingDir, ".python-version", "runtime.txt")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content).To(BeEmpty())
+			Expect(source).To(BeEmpty())
+		})
+	})
+
+	context("failure cases", func() {
+		context("when a candidate cannot be read", func() {
+			it.Before(func() {
+				Expect(os.Mkdir(filepath.Join(workingDir, ".nvmrc"), os.ModePerm)).To(Succeed())
+			})
+
+			it("returns an error", func() {
+				_, _, err := fs.FindVersionFile(workingDir, ".nvmrc", ".node-version")
+				Expect(err).To(MatchError(ContainSubstring("failed to read version file:")))
+				Expect(err).To(MatchError(ContainSubstring("is a directory")))
+			})
+		})
+	})
+}
@@ -13,5 +13,8 @@ func TestUnitFS(t *testing.T) {
 	suite("Copy", testCopy)
 	suite("IsEmptyDir", testIsEmptyDir)
 	suite("ChecksumCalculator", testChecksumCalculator)
+	suite("FindVersionFile", testFindVersionFile)
+	suite("DiffTree", testDiffTree)
+	suite("AtomicWriteFile", testAtomicWriteFile)
 	suite.Run(t)
 }
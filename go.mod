@@ -12,6 +12,7 @@ require (
 	github.com/google/go-containerregistry v0.5.1
 	github.com/onsi/gomega v1.14.0
 	github.com/pelletier/go-toml v1.9.3
+	github.com/pierrec/lz4/v4 v4.1.8
 	github.com/sclevine/spec v1.4.0
 	github.com/spf13/cobra v1.2.1
 	github.com/ulikunitz/xz v0.5.10
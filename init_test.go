@@ -10,6 +10,8 @@ import (
 func TestUnitPackit(t *testing.T) {
 	suite := spec.New("packit", spec.Report(report.Terminal{}))
 	suite("Build", testBuild)
+	suite("BuildPlan", testBuildPlan)
+	suite("BuildpackPlan", testBuildpackPlan)
 	suite("Detect", testDetect)
 	suite("Environment", testEnvironment)
 	suite("Layer", testLayer)
@@ -1,8 +1,11 @@
 package packit
 
 import (
+	"bytes"
 	"fmt"
 	"os"
+
+	"github.com/BurntSushi/toml"
 )
 
 // Layer provides a representation of a layer managed by a buildpack as
@@ -89,3 +92,44 @@ func (l Layer) Reset() (Layer, error) {
 
 	return l, nil
 }
+
+// EncodeMetadata replaces the metadata of the layer with the given value,
+// which must be a struct or map that can be encoded as a TOML table. The value
+// is converted into the same representation that is read back from the Layer
+// Content Metadata TOML file, so that buildpacks can store typed metadata
+// without converting it by hand.
+func (l Layer) EncodeMetadata(v interface{}) (Layer, error) {
+	buffer := bytes.NewBuffer(nil)
+	err := toml.NewEncoder(buffer).Encode(v)
+	if err != nil {
+		return Layer{}, fmt.Errorf("failed to encode layer metadata: %w", err)
+	}
+
+	metadata := map[string]interface{}{}
+	_, err = toml.Decode(buffer.String(), &metadata)
+	if err != nil {
+		return Layer{}, fmt.Errorf("failed to encode layer metadata: %w", err)
+	}
+
+	l.Metadata = metadata
+
+	return l, nil
+}
+
+// DecodeMetadata populates the given value, which must be a pointer to a
+// struct or map, with the metadata of the layer. Fields of the value are
+// matched to the keys of the metadata using their toml struct tags.
+func (l Layer) DecodeMetadata(v interface{}) error {
+	buffer := bytes.NewBuffer(nil)
+	err := toml.NewEncoder(buffer).Encode(l.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to decode layer metadata: %w", err)
+	}
+
+	_, err = toml.Decode(buffer.String(), v)
+	if err != nil {
+		return fmt.Errorf("failed to decode layer metadata: %w", err)
+	}
+
+	return nil
+}
@@ -4,7 +4,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/paketo-buildpacks/packit"
 	"github.com/sclevine/spec"
 
@@ -162,4 +164,93 @@ func testLayer(t *testing.T, context spec.G, it spec.S) {
 			})
 		})
 	})
+
+	context("EncodeMetadata and DecodeMetadata", func() {
+		type metadata struct {
+			CacheKey string    `toml:"cache_key"`
+			Version  string    `toml:"version"`
+			Files    []string  `toml:"files"`
+			Count    int       `toml:"count"`
+			BuiltAt  time.Time `toml:"built_at"`
+		}
+
+		var (
+			layer packit.Layer
+			value metadata
+		)
+
+		it.Before(func() {
+			layer = packit.Layer{
+				Name: "some-layer",
+				Path: filepath.Join(layersDir, "some-layer"),
+			}
+
+			value = metadata{
+				CacheKey: "some-cache-key",
+				Version:  "1.2.3",
+				Files:    []string{"some-file", "other-file"},
+				Count:    3,
+				BuiltAt:  time.Date(2021, time.July, 1, 12, 0, 0, 0, time.UTC),
+			}
+		})
+
+		it("round-trips a struct through the layer metadata", func() {
+			layer, err := layer.EncodeMetadata(value)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(layer.Metadata).To(HaveKeyWithValue("cache_key", "some-cache-key"))
+			Expect(layer.Metadata).To(HaveKeyWithValue("version", "1.2.3"))
+
+			var decoded metadata
+			Expect(layer.DecodeMetadata(&decoded)).To(Succeed())
+			Expect(decoded).To(Equal(value))
+		})
+
+		it("round-trips a struct through the layer metadata file", func() {
+			layer, err := layer.EncodeMetadata(value)
+			Expect(err).NotTo(HaveOccurred())
+
+			file, err := os.Create(filepath.Join(layersDir, "some-layer.toml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(toml.NewEncoder(file).Encode(layer)).To(Succeed())
+			Expect(file.Close()).To(Succeed())
+
+			layer, err = packit.Layers{Path: layersDir}.Get("some-layer")
+			Expect(err).NotTo(HaveOccurred())
+
+			var decoded metadata
+			Expect(layer.DecodeMetadata(&decoded)).To(Succeed())
+			Expect(decoded).To(Equal(value))
+		})
+
+		context("when the layer has no metadata", func() {
+			it("leaves the value unchanged", func() {
+				decoded := metadata{Version: "some-version"}
+				Expect(layer.DecodeMetadata(&decoded)).To(Succeed())
+				Expect(decoded).To(Equal(metadata{Version: "some-version"}))
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the value cannot be encoded as a table", func() {
+				it("returns an error", func() {
+					_, err := layer.EncodeMetadata("some-string")
+					Expect(err).To(MatchError(ContainSubstring("failed to encode layer metadata:")))
+				})
+			})
+
+			context("when the metadata does not match the value", func() {
+				it.Before(func() {
+					layer.Metadata = map[string]interface{}{
+						"count": "some-count",
+					}
+				})
+
+				it("returns an error", func() {
+					var decoded metadata
+					err := layer.DecodeMetadata(&decoded)
+					Expect(err).To(MatchError(ContainSubstring("failed to decode layer metadata:")))
+				})
+			})
+		})
+	})
 }
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/BurntSushi/toml"
 )
@@ -68,3 +70,40 @@ func (l Layers) Get(name string) (Layer, error) {
 
 	return layer, nil
 }
+
+// List returns every layer that has a layer content metadata file (<name>.toml)
+// in the layers directory, sorted by name. This includes layers whose metadata
+// was restored without their contents. The build.toml, launch.toml, and
+// store.toml files in the layers directory are not layers and are ignored.
+func (l Layers) List() ([]Layer, error) {
+	files, err := filepath.Glob(filepath.Join(l.Path, "*.toml"))
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".toml")
+
+		switch name {
+		case "build", "launch", "store":
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var layers []Layer
+	for _, name := range names {
+		layer, err := l.Get(name)
+		if err != nil {
+			return nil, err
+		}
+
+		layers = append(layers, layer)
+	}
+
+	return layers, nil
+}
@@ -237,4 +237,77 @@ some-key = "some-value"`), 0644)
 			})
 		})
 	})
+	context("layers.List", func() {
+		it.Before(func() {
+			Expect(os.MkdirAll(filepath.Join(layersDir, "some-layer"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(layersDir, "some-layer.toml"), []byte(`
+launch = true
+
+[metadata]
+some-key = "some-value"`), 0644)).To(Succeed())
+
+			Expect(os.MkdirAll(filepath.Join(layersDir, "other-layer"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(layersDir, "other-layer.toml"), []byte(`
+build = true
+cache = true`), 0644)).To(Succeed())
+
+			Expect(os.WriteFile(filepath.Join(layersDir, "orphan-layer.toml"), []byte(`
+launch = true`), 0644)).To(Succeed())
+
+			Expect(os.WriteFile(filepath.Join(layersDir, "launch.toml"), []byte(""), 0644)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(layersDir, "store.toml"), []byte(""), 0644)).To(Succeed())
+			Expect(os.MkdirAll(filepath.Join(layersDir, "no-metadata-layer"), os.ModePerm)).To(Succeed())
+		})
+
+		it("returns the layers that have metadata on disk", func() {
+			list, err := layers.List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(list).To(Equal([]packit.Layer{
+				{
+					Name:             "orphan-layer",
+					Path:             filepath.Join(layersDir, "orphan-layer"),
+					Launch:           true,
+					SharedEnv:        packit.Environment{},
+					BuildEnv:         packit.Environment{},
+					LaunchEnv:        packit.Environment{},
+					ProcessLaunchEnv: map[string]packit.Environment{},
+				},
+				{
+					Name:             "other-layer",
+					Path:             filepath.Join(layersDir, "other-layer"),
+					Build:            true,
+					Cache:            true,
+					SharedEnv:        packit.Environment{},
+					BuildEnv:         packit.Environment{},
+					LaunchEnv:        packit.Environment{},
+					ProcessLaunchEnv: map[string]packit.Environment{},
+				},
+				{
+					Name:             "some-layer",
+					Path:             filepath.Join(layersDir, "some-layer"),
+					Launch:           true,
+					SharedEnv:        packit.Environment{},
+					BuildEnv:         packit.Environment{},
+					LaunchEnv:        packit.Environment{},
+					ProcessLaunchEnv: map[string]packit.Environment{},
+					Metadata: map[string]interface{}{
+						"some-key": "some-value",
+					},
+				},
+			}))
+		})
+
+		context("failure cases", func() {
+			context("when a layer toml is malformed", func() {
+				it.Before(func() {
+					Expect(os.WriteFile(filepath.Join(layersDir, "other-layer.toml"), []byte("%%%"), 0644)).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := layers.List()
+					Expect(err).To(MatchError(ContainSubstring("failed to parse layer content metadata:")))
+				})
+			})
+		})
+	})
 }
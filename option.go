@@ -1,12 +1,22 @@
 package packit
 
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
 // OptionConfig is the set of configurable options for the Build and Detect
 // functions.
 type OptionConfig struct {
-	exitHandler ExitHandler
-	args        []string
-	tomlWriter  TOMLWriter
-	envWriter   EnvironmentWriter
+	exitHandler  ExitHandler
+	args         []string
+	tomlWriter   TOMLWriter
+	envWriter    EnvironmentWriter
+	cnbPath      string
+	layersPath   string
+	platformPath string
+	planPath     string
 }
 
 // Option declares a function signature that can be used to define optional
@@ -24,6 +34,8 @@ type ExitHandler interface {
 	Error(error)
 }
 
+//go:generate faux --interface TOMLWriter --output fakes/toml_writer.go
+
 // TOMLWriter serves as the interface for types that can handle the writing of
 // TOML files. TOMLWriters take a path to a file location on disk and a
 // datastructure to marshal.
@@ -55,3 +67,83 @@ func WithArgs(args []string) Option {
 		return config
 	}
 }
+
+// WithTOMLWriter is an Option that overrides the TOMLWriter used to persist
+// the results of a given invocation of Build.
+func WithTOMLWriter(tomlWriter TOMLWriter) Option {
+	return func(config OptionConfig) OptionConfig {
+		config.tomlWriter = tomlWriter
+		return config
+	}
+}
+
+// WithEnvironmentWriter is an Option that overrides the EnvironmentWriter used
+// to persist the layer environments of a given invocation of Build.
+func WithEnvironmentWriter(envWriter EnvironmentWriter) Option {
+	return func(config OptionConfig) OptionConfig {
+		config.envWriter = envWriter
+		return config
+	}
+}
+
+// WithCNBPath is an Option that overrides the location of the buildpack
+// contents for a given invocation of Build or Detect, taking precedence over
+// both $CNB_BUILDPACK_DIR and the path of the executable.
+func WithCNBPath(path string) Option {
+	return func(config OptionConfig) OptionConfig {
+		config.cnbPath = path
+		return config
+	}
+}
+
+// WithLayersPath is an Option that overrides the layers directory given as an
+// argument to a given invocation of Build.
+func WithLayersPath(path string) Option {
+	return func(config OptionConfig) OptionConfig {
+		config.layersPath = path
+		return config
+	}
+}
+
+// WithPlatformPath is an Option that overrides the platform directory given
+// as an argument to a given invocation of Build or Detect.
+func WithPlatformPath(path string) Option {
+	return func(config OptionConfig) OptionConfig {
+		config.platformPath = path
+		return config
+	}
+}
+
+// WithPlanPath is an Option that overrides the buildpack plan path given as an
+// argument to a given invocation of Build or Detect.
+func WithPlanPath(path string) Option {
+	return func(config OptionConfig) OptionConfig {
+		config.planPath = path
+		return config
+	}
+}
+
+// arg returns the override when it is set, or the argument at the given index
+// otherwise.
+func (c OptionConfig) arg(override string, index int) string {
+	if override != "" {
+		return override
+	}
+
+	return c.args[index]
+}
+
+// buildpackPath returns the location of the buildpack contents, given the
+// name of the executable for the current phase.
+func (c OptionConfig) buildpackPath(executable string) string {
+	if c.cnbPath != "" {
+		return c.cnbPath
+	}
+
+	cnbPath, ok := os.LookupEnv("CNB_BUILDPACK_DIR")
+	if !ok {
+		cnbPath = filepath.Clean(strings.TrimSuffix(c.args[0], filepath.Join("bin", executable)))
+	}
+
+	return cnbPath
+}
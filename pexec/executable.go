@@ -1,10 +1,15 @@
 package pexec
 
 import (
+	"context"
+	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 )
 
 // Executable represents an executable on the $PATH.
@@ -45,7 +50,14 @@ func (e Executable) Execute(execution Execution) error {
 
 	os.Setenv("PATH", envPath)
 
-	cmd := exec.Command(executable, execution.Args...)
+	ctx := context.Background()
+	if execution.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, execution.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, executable, execution.Args...)
 
 	if execution.Dir != "" {
 		cmd.Dir = execution.Dir
@@ -58,7 +70,79 @@ func (e Executable) Execute(execution Execution) error {
 	cmd.Stdout = execution.Stdout
 	cmd.Stderr = execution.Stderr
 
-	return cmd.Run()
+	if execution.CombinedOutput != nil {
+		cmd.Stdout = execution.CombinedOutput
+		cmd.Stderr = execution.CombinedOutput
+	}
+
+	if !execution.ForwardSignals {
+		err = cmd.Run()
+	} else {
+		err = run(cmd, execution.GracePeriod)
+	}
+
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return TimeoutError{Timeout: execution.Timeout, Err: err}
+	}
+
+	return err
+}
+
+// TimeoutError is returned by Execute when the executable is killed because
+// it did not exit within the Timeout of its Execution. Any output written by
+// the executable before it was killed will already have been written to the
+// Stdout, Stderr, or CombinedOutput of the Execution.
+type TimeoutError struct {
+	// Timeout is the duration the executable was allowed to run.
+	Timeout time.Duration
+
+	// Err is the error returned when the killed executable exited.
+	Err error
+}
+
+func (e TimeoutError) Error() string {
+	return fmt.Sprintf("executable timed out after %s: %s", e.Timeout, e.Err)
+}
+
+func (e TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// run starts the command and relays any SIGINT or SIGTERM received by the
+// current process to it. Once a signal has been forwarded, the command is
+// given the grace period to exit before it is killed.
+func run(cmd *exec.Cmd, gracePeriod time.Duration) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	err := cmd.Start()
+	if err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	var deadline <-chan time.Time
+	for {
+		select {
+		case err := <-done:
+			return err
+
+		case sig := <-signals:
+			_ = cmd.Process.Signal(sig)
+
+			if deadline == nil && gracePeriod > 0 {
+				deadline = time.After(gracePeriod)
+			}
+
+		case <-deadline:
+			_ = cmd.Process.Kill()
+		}
+	}
 }
 
 // Execution is the set of configurable options for a given execution of the
@@ -81,4 +165,25 @@ type Execution struct {
 
 	// Stderr is where the output of stderr will be written during the execution.
 	Stderr io.Writer
+
+	// CombinedOutput is where the output of both stdout and stderr will be
+	// written during the execution, in the order in which it was emitted. When
+	// CombinedOutput is set, Stdout and Stderr are ignored.
+	CombinedOutput io.Writer
+
+	// ForwardSignals, when true, relays any SIGINT or SIGTERM received by the
+	// current process to the executable while it is running, giving it a chance
+	// to shut down gracefully.
+	ForwardSignals bool
+
+	// GracePeriod is how long the executable is given to exit after a signal
+	// has been forwarded to it before it is killed. If GracePeriod is not set,
+	// the executable is never killed. It has no effect unless ForwardSignals is
+	// set.
+	GracePeriod time.Duration
+
+	// Timeout is how long the executable is allowed to run before it is
+	// killed, in which case Execute returns a TimeoutError. If Timeout is not
+	// set, the executable may run indefinitely.
+	Timeout time.Duration
 }
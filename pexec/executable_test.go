@@ -2,13 +2,17 @@ package pexec_test
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/sclevine/spec"
 
+	"github.com/onsi/gomega/gbytes"
 	"github.com/onsi/gomega/gexec"
 	"github.com/paketo-buildpacks/packit/pexec"
 
@@ -17,7 +21,9 @@ import (
 
 func testPexec(t *testing.T, context spec.G, it spec.S) {
 	var (
-		Expect = NewWithT(t).Expect
+		withT      = NewWithT(t)
+		Expect     = withT.Expect
+		Eventually = withT.Eventually
 
 		tmpDir         string
 		stdout, stderr *bytes.Buffer
@@ -88,6 +94,23 @@ func testPexec(t *testing.T, context spec.G, it spec.S) {
 			})
 		})
 
+		context("when given a writer for combined output", func() {
+			it("writes stdout and stderr to that writer in the order they were emitted", func() {
+				combined := bytes.NewBuffer(nil)
+				err := executable.Execute(pexec.Execution{
+					Args:           []string{"something"},
+					Stdout:         stdout,
+					Stderr:         stderr,
+					CombinedOutput: combined,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(combined.String()).To(HavePrefix(fmt.Sprintf("Output on stdout\nOutput on stderr\nArguments: [%s something]\n", fakeCLI)))
+				Expect(stdout.String()).To(BeEmpty())
+				Expect(stderr.String()).To(BeEmpty())
+			})
+		})
+
 		context("when the executable is on the PATH given as an argument", func() {
 			it.Before(func() {
 				os.Setenv("PATH", "some-path")
@@ -105,6 +128,126 @@ func testPexec(t *testing.T, context spec.G, it spec.S) {
 			})
 		})
 
+		context("when forwarding signals", func() {
+			var (
+				signalCLI string
+				path      string
+				output    *gbytes.Buffer
+			)
+
+			it.Before(func() {
+				path = os.Getenv("PATH")
+				Expect(os.Setenv("PATH", existingPath)).To(Succeed())
+
+				var err error
+				signalCLI, err = gexec.Build("github.com/paketo-buildpacks/packit/fakes/some-executable", "-ldflags", "-X main.signals=trap")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(os.Setenv("PATH", filepath.Dir(signalCLI))).To(Succeed())
+
+				output = gbytes.NewBuffer()
+			})
+
+			it.After(func() {
+				Expect(os.Setenv("PATH", path)).To(Succeed())
+			})
+
+			it("forwards the signal to the executable", func() {
+				errs := make(chan error, 1)
+				go func() {
+					errs <- executable.Execute(pexec.Execution{
+						Stdout:         output,
+						ForwardSignals: true,
+						GracePeriod:    10 * time.Second,
+					})
+				}()
+
+				Eventually(output).Should(gbytes.Say("Waiting for signal"))
+				Expect(syscall.Kill(os.Getpid(), syscall.SIGTERM)).To(Succeed())
+
+				Eventually(errs, "5s").Should(Receive(BeNil()))
+				Expect(output).To(gbytes.Say("Received signal: terminated"))
+			})
+
+			context("when the executable does not exit within the grace period", func() {
+				it.Before(func() {
+					Expect(os.Setenv("PATH", existingPath)).To(Succeed())
+
+					var err error
+					signalCLI, err = gexec.Build("github.com/paketo-buildpacks/packit/fakes/some-executable", "-ldflags", "-X main.signals=ignore")
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(os.Setenv("PATH", filepath.Dir(signalCLI))).To(Succeed())
+				})
+
+				it("kills the executable", func() {
+					errs := make(chan error, 1)
+					go func() {
+						errs <- executable.Execute(pexec.Execution{
+							Stdout:         output,
+							ForwardSignals: true,
+							GracePeriod:    100 * time.Millisecond,
+						})
+					}()
+
+					Eventually(output).Should(gbytes.Say("Waiting for signal"))
+					Expect(syscall.Kill(os.Getpid(), syscall.SIGTERM)).To(Succeed())
+
+					Eventually(errs, "5s").Should(Receive(MatchError("signal: killed")))
+				})
+			})
+		})
+
+		context("when given a timeout", func() {
+			var path string
+
+			it.Before(func() {
+				path = os.Getenv("PATH")
+				Expect(os.Setenv("PATH", filepath.Dir(fakeCLI))).To(Succeed())
+			})
+
+			it.After(func() {
+				Expect(os.Setenv("PATH", path)).To(Succeed())
+			})
+
+			it("executes the executable when it finishes in time", func() {
+				err := executable.Execute(pexec.Execution{
+					Args:    []string{"something"},
+					Stdout:  stdout,
+					Timeout: 10 * time.Second,
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(stdout).To(ContainSubstring(fmt.Sprintf("Arguments: [%s something]", fakeCLI)))
+			})
+
+			context("when the executable does not finish in time", func() {
+				it.Before(func() {
+					Expect(os.Setenv("PATH", existingPath)).To(Succeed())
+
+					slowCLI, err := gexec.Build("github.com/paketo-buildpacks/packit/fakes/some-executable", "-ldflags", "-X main.signals=ignore")
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(os.Setenv("PATH", filepath.Dir(slowCLI))).To(Succeed())
+				})
+
+				it("kills the executable and returns a timeout error with the partial output", func() {
+					err := executable.Execute(pexec.Execution{
+						Stdout:  stdout,
+						Stderr:  stderr,
+						Timeout: 500 * time.Millisecond,
+					})
+					Expect(err).To(MatchError("executable timed out after 500ms: signal: killed"))
+
+					var timeoutErr pexec.TimeoutError
+					Expect(errors.As(err, &timeoutErr)).To(BeTrue())
+					Expect(timeoutErr.Timeout).To(Equal(500 * time.Millisecond))
+
+					Expect(stdout).To(ContainSubstring("Waiting for signal"))
+					Expect(stderr).To(ContainSubstring("Output on stderr"))
+				})
+			})
+		})
+
 		context("failure cases", func() {
 			context("when the executable cannot be found on the path", func() {
 				it.Before(func() {
@@ -5,7 +5,7 @@ import (
 	"os"
 	"time"
 
-	"github.com/BurntSushi/toml"
+	"github.com/paketo-buildpacks/packit/cargo"
 )
 
 // Dependency is a representation of a buildpack dependency.
@@ -16,21 +16,54 @@ type Dependency struct {
 	// ID is the identifier used to specify the dependency.
 	ID string `toml:"id"`
 
+	// Licenses is a list of the licenses under which the dependency is
+	// distributed.
+	Licenses []License `toml:"licenses"`
+
 	// Name is the human-readable name of the dependency.
 	Name string `toml:"name"`
 
 	// SHA256 is the hex-encoded SHA256 checksum of the built dependency.
 	SHA256 string `toml:"sha256"`
 
+	// Checksums is a list of checksums of the built dependency, each in the
+	// "<algorithm>:<hex>" format, such as "sha512:ab12...". The supported
+	// algorithms are sha256 and sha512. Deliver accepts the dependency if it
+	// matches any one of them or its SHA256, which allows a buildpack to list
+	// both an old and a new checksum while migrating between them.
+	Checksums []string `toml:"checksums"`
+
+	// ChecksumURI is the uri location of a file containing the hex-encoded
+	// SHA256 checksum of the built dependency, either on its own or in the
+	// "<checksum>  <filename>" format written by sha256sum. It is used to
+	// validate the dependency when SHA256 is not set.
+	ChecksumURI string `toml:"checksum_uri"`
+
 	// Source is the uri location of the source-code representation of the dependency.
 	Source string `toml:"source"`
 
 	// SourceSHA256 is the hex-encoded SHA256 checksum of the source-code representation of the dependency.
 	SourceSHA256 string `toml:"source_sha256"`
 
+	// CPE is a list of Common Platform Enumeration identifiers for the
+	// dependency. In buildpack.toml it may be given as a single string or a
+	// list of strings.
+	CPE []string `toml:"cpe"`
+
+	// PURL is the Package URL identifying the dependency.
+	PURL string `toml:"purl"`
+
 	// Stacks is a list of stacks for which the dependency is built.
 	Stacks []string `toml:"stacks"`
 
+	// Arch is the CPU architecture for which the dependency is built, such as
+	// "amd64" or "arm64".
+	Arch string `toml:"arch"`
+
+	// OS is the operating system for which the dependency is built, such as
+	// "linux".
+	OS string `toml:"os"`
+
 	// URI is the uri location of the built dependency.
 	URI string `toml:"uri"`
 
@@ -40,31 +73,172 @@ type Dependency struct {
 	// StripComponents behaves like the --strip-components flag on tar command
 	// removing the first n levels from the final decompression destination.
 	StripComponents int `toml:"strip-components"`
+
+	// Format is the archive format of the built dependency. When set, Deliver
+	// decompresses the dependency using that format rather than detecting it
+	// from the contents. The supported values are "tar", "tgz", "txz", "tbz2",
+	// "tlz4", "zip", "xz", and "raw", which copies the dependency into the
+	// layer as a single file without decompressing it. Zstandard ("zst") is not
+	// supported, because vacation cannot decompress it, and Deliver returns an
+	// error for it as for any other unsupported format. StripComponents cannot
+	// be combined with the "zip" format. A single xz compressed file is
+	// written to the layer under the name of its URI without the .xz
+	// extension, whether its format is "xz" or detected.
+	Format string `toml:"format"`
+
+	// FileCount is the number of regular files the dependency is expected to
+	// contain. When set, Deliver counts the regular files that the extraction
+	// added to the layer, or to its TargetPath, and fails if the counts differ.
+	// Files that were already present are not counted. A value of zero skips
+	// the check.
+	FileCount int `toml:"file-count"`
+
+	// TargetPath is a path, relative to the layer, into which Deliver extracts
+	// the dependency instead of the root of the layer. It allows several
+	// dependencies to be installed into one layer without clobbering each
+	// other. The path must stay within the layer.
+	TargetPath string `toml:"target-path"`
+}
+
+// License is a representation of a license under which a dependency is
+// distributed.
+type License struct {
+	// Type is the SPDX identifier or name of the license.
+	Type string `toml:"type"`
+
+	// URI is the location of the license text.
+	URI string `toml:"uri"`
+}
+
+// DependencyConstraint is a representation of an entry in the
+// metadata.dependency-constraints table of a buildpack.toml file. It declares
+// the versions of a dependency that the buildpack author intends to support.
+type DependencyConstraint struct {
+	// ID is the identifier of the dependency the constraint applies to.
+	ID string `toml:"id"`
+
+	// Constraint is a SemVer constraint that supported versions must satisfy.
+	Constraint string `toml:"constraint"`
+
+	// Patches is the number of patch versions within the constraint that
+	// should be kept when updating the dependencies of the buildpack.
+	Patches int `toml:"patches"`
+}
+
+// buildpackMetadata holds the parts of the metadata table of a buildpack.toml
+// file that are used to resolve dependencies.
+type buildpackMetadata struct {
+	dependencies          []Dependency
+	defaultVersions       map[string]string
+	stackDefaultVersions  map[string]map[string]string
+	dependencyConstraints []DependencyConstraint
+}
+
+// defaultVersion returns the default version constraint for the dependency
+// with the given id. A default declared for one of the given stacks in the
+// metadata.stack-default-versions table takes precedence over the default
+// declared in the metadata.default-versions table.
+func (m buildpackMetadata) defaultVersion(id string, stacks []string) string {
+	for _, stack := range stacks {
+		if version, ok := m.stackDefaultVersions[stack][id]; ok {
+			return version
+		}
+	}
+
+	return m.defaultVersions[id]
 }
 
-func parseBuildpack(path, name string) ([]Dependency, string, error) {
+// parseConfig decodes the buildpack.toml file at the given path.
+func parseConfig(path string) (cargo.Config, error) {
 	file, err := os.Open(path)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to parse buildpack.toml: %w", err)
+		return cargo.Config{}, fmt.Errorf("failed to parse buildpack.toml: %w", err)
 	}
+	defer file.Close()
 
-	var buildpack struct {
-		Metadata struct {
-			DefaultVersions map[string]string `toml:"default-versions"`
-			Dependencies    []Dependency      `toml:"dependencies"`
-		} `toml:"metadata"`
-	}
-	_, err = toml.DecodeReader(file, &buildpack)
+	var config cargo.Config
+	err = cargo.DecodeConfig(file, &config)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to parse buildpack.toml: %w", err)
+		return cargo.Config{}, ErrMalformedBuildpackTOML{Path: path, Err: err}
+	}
+
+	return config, nil
+}
+
+// metadataFromConfig converts the metadata of an already parsed
+// buildpack.toml.
+func metadataFromConfig(config cargo.Config) buildpackMetadata {
+	var dependencies []Dependency
+	for _, d := range config.Metadata.Dependencies {
+		dependency := Dependency{
+			ID:              d.ID,
+			Name:            d.Name,
+			SHA256:          d.SHA256,
+			Checksums:       d.Checksums,
+			ChecksumURI:     d.ChecksumURI,
+			Source:          d.Source,
+			SourceSHA256:    d.SourceSHA256,
+			PURL:            d.PURL,
+			Stacks:          d.Stacks,
+			Arch:            d.Arch,
+			OS:              d.OS,
+			URI:             d.URI,
+			Version:         d.Version,
+			StripComponents: d.StripComponents,
+			Format:          d.Format,
+			FileCount:       d.FileCount,
+			TargetPath:      d.TargetPath,
+		}
+
+		if d.DeprecationDate != nil {
+			dependency.DeprecationDate = *d.DeprecationDate
+		}
+
+		if d.CPE != "" {
+			dependency.CPE = []string{d.CPE}
+		}
+		dependency.CPE = append(dependency.CPE, d.CPEs...)
+
+		for _, license := range d.Licenses {
+			dependency.Licenses = append(dependency.Licenses, License{Type: license})
+		}
+
+		for _, license := range d.LicenseTables {
+			dependency.Licenses = append(dependency.Licenses, License{Type: license.Type, URI: license.URI})
+		}
+
+		dependencies = append(dependencies, dependency)
 	}
 
-	return buildpack.Metadata.Dependencies, buildpack.Metadata.DefaultVersions[name], nil
+	var constraints []DependencyConstraint
+	for _, c := range config.Metadata.DependencyConstraints {
+		constraints = append(constraints, DependencyConstraint{
+			ID:         c.ID,
+			Constraint: c.Constraint,
+			Patches:    c.Patches,
+		})
+	}
+
+	return buildpackMetadata{
+		dependencies:          dependencies,
+		defaultVersions:       config.Metadata.DefaultVersions,
+		stackDefaultVersions:  config.Metadata.StackDefaultVersions,
+		dependencyConstraints: constraints,
+	}
 }
 
 func stacksInclude(stacks []string, stack string) bool {
 	for _, s := range stacks {
-		if s == stack {
+		if s == stack || s == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func stacksIntersect(stacks, candidates []string) bool {
+	for _, candidate := range candidates {
+		if stacksInclude(stacks, candidate) {
 			return true
 		}
 	}
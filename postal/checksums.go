@@ -0,0 +1,140 @@
+package postal
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+
+	"github.com/paketo-buildpacks/packit/cargo"
+)
+
+// checksumValidator is a reader that validates the content it reads once it
+// has been read in full.
+type checksumValidator interface {
+	io.Reader
+	Valid() (bool, error)
+}
+
+// newChecksumValidator returns a reader that validates the dependency as it is
+// read. Dependencies that declare Checksums are validated against each of the
+// candidates returned by checksumCandidates, and all others are validated
+// against their SHA256.
+func newChecksumValidator(reader io.Reader, dependency Dependency) (checksumValidator, error) {
+	if len(dependency.Checksums) == 0 {
+		return cargo.NewValidatedReader(reader, dependency.SHA256), nil
+	}
+
+	return newChecksumsReader(reader, checksumCandidates(dependency))
+}
+
+// checksumCandidates returns the Checksums of the dependency, along with its
+// SHA256 in the same "<algorithm>:<hex>" format if it is not already listed.
+func checksumCandidates(dependency Dependency) []string {
+	candidates := append([]string{}, dependency.Checksums...)
+	if dependency.SHA256 == "" {
+		return candidates
+	}
+
+	sha := fmt.Sprintf("sha256:%s", dependency.SHA256)
+	for _, candidate := range candidates {
+		if strings.EqualFold(candidate, sha) {
+			return candidates
+		}
+	}
+
+	return append(candidates, sha)
+}
+
+// mappingChecksum returns the hex-encoded sha256 checksum under which a
+// dependency mapping for the dependency is recorded. It is the SHA256 of the
+// dependency or, when that is not set, the first sha256 entry of its
+// Checksums. An empty string is returned if the dependency has neither.
+func mappingChecksum(dependency Dependency) string {
+	if dependency.SHA256 != "" {
+		return dependency.SHA256
+	}
+
+	for _, c := range dependency.Checksums {
+		parts := strings.SplitN(c, ":", 2)
+		if len(parts) == 2 && strings.EqualFold(parts[0], "sha256") {
+			return strings.ToLower(parts[1])
+		}
+	}
+
+	return ""
+}
+
+type checksum struct {
+	algorithm string
+	digest    string
+}
+
+// checksumsReader computes a digest for each of the algorithms used by its
+// checksums as the content is read, and considers the content valid if any of
+// the checksums match.
+type checksumsReader struct {
+	reader    io.Reader
+	checksums []checksum
+	hashes    map[string]hash.Hash
+}
+
+func newChecksumsReader(reader io.Reader, checksums []string) (checksumsReader, error) {
+	r := checksumsReader{
+		hashes: map[string]hash.Hash{},
+	}
+
+	var writers []io.Writer
+	for _, c := range checksums {
+		parts := strings.SplitN(c, ":", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			return checksumsReader{}, fmt.Errorf("failed to parse checksum %q: expected the format <algorithm>:<hex>", c)
+		}
+
+		algorithm := strings.ToLower(parts[0])
+		if _, ok := r.hashes[algorithm]; !ok {
+			var h hash.Hash
+			switch algorithm {
+			case "sha256":
+				h = sha256.New()
+			case "sha512":
+				h = sha512.New()
+			default:
+				return checksumsReader{}, fmt.Errorf("failed to parse checksum %q: unsupported algorithm %q", c, parts[0])
+			}
+
+			r.hashes[algorithm] = h
+			writers = append(writers, h)
+		}
+
+		r.checksums = append(r.checksums, checksum{algorithm: algorithm, digest: strings.ToLower(parts[1])})
+	}
+
+	r.reader = io.TeeReader(reader, io.MultiWriter(writers...))
+
+	return r, nil
+}
+
+func (r checksumsReader) Read(p []byte) (int, error) {
+	return r.reader.Read(p)
+}
+
+// Valid reads the rest of the content and reports whether its digest matches
+// any of the checksums.
+func (r checksumsReader) Valid() (bool, error) {
+	_, err := io.Copy(io.Discard, r.reader)
+	if err != nil {
+		return false, err
+	}
+
+	for _, c := range r.checksums {
+		if hex.EncodeToString(r.hashes[c.algorithm].Sum(nil)) == c.digest {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
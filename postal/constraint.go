@@ -0,0 +1,50 @@
+package postal
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+var (
+	versionPrefixPattern = regexp.MustCompile(`(^|[\s,<>=~^|])v(\d)`)
+	wildcardPattern      = regexp.MustCompile(`\.[xX](\.[xX*])*\b`)
+)
+
+// NormalizeConstraint converts a version constraint, as it might be written in
+// a version file or buildpack.yml, into a SemVer constraint. Surrounding
+// whitespace and "v" prefixes on versions are removed, ".x" components are
+// converted into wildcards, and the pessimistic operator (~>) is mapped onto a
+// tilde range when given a major, minor, and patch version or onto a caret
+// range otherwise. It returns an error naming the given constraint if the
+// result is not a valid SemVer constraint.
+func NormalizeConstraint(version string) (string, error) {
+	constraint := strings.TrimSpace(version)
+
+	if strings.HasPrefix(constraint, "~>") {
+		res := strings.TrimSpace(strings.TrimPrefix(constraint, "~>"))
+		parts := strings.Split(res, ".")
+
+		// if the version contains a major, minor, and patch use "~" Tilde Range Comparison
+		// if the version contains a major and minor only, or a major version only use "^" Caret Range Comparison
+		if len(parts) == 3 {
+			constraint = "~" + res
+		} else {
+			constraint = "^" + res
+		}
+	}
+
+	constraint = versionPrefixPattern.ReplaceAllString(constraint, "$1$2")
+	constraint = wildcardPattern.ReplaceAllStringFunc(constraint, func(match string) string {
+		return strings.NewReplacer("x", "*", "X", "*").Replace(match)
+	})
+
+	_, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return "", fmt.Errorf("invalid version constraint %q: %w", version, err)
+	}
+
+	return constraint, nil
+}
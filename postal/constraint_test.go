@@ -0,0 +1,49 @@
+package postal_test
+
+import (
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/postal"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testNormalizeConstraint(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	for _, tt := range []struct {
+		description string
+		version     string
+		expected    string
+	}{
+		{"leaves a valid constraint unchanged", ">= 1.2.3, < 2", ">= 1.2.3, < 2"},
+		{"trims surrounding whitespace", "  1.2.3 \n", "1.2.3"},
+		{"strips a v prefix", "v1.2.3", "1.2.3"},
+		{"strips v prefixes after operators", ">=v1.2, <v2", ">=1.2, <2"},
+		{"translates .x components into wildcards", "1.2.x", "1.2.*"},
+		{"translates several .X components into wildcards", "1.X.X", "1.*.*"},
+		{"maps ~> with a patch version onto a tilde range", "~> 1.2.0", "~1.2.0"},
+		{"maps ~> with a minor version onto a caret range", "~> 1.1", "^1.1"},
+		{"maps ~> with a major version onto a caret range", "~>2", "^2"},
+		{"maps ~> with a v prefix", "~> v1.2.3", "~1.2.3"},
+	} {
+		tt := tt
+
+		it(tt.description, func() {
+			constraint, err := postal.NormalizeConstraint(tt.version)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(constraint).To(Equal(tt.expected))
+		})
+	}
+
+	context("failure cases", func() {
+		context("when the constraint is not valid", func() {
+			it("returns an error naming the constraint", func() {
+				_, err := postal.NormalizeConstraint("this-is-not-semver")
+				Expect(err).To(MatchError(ContainSubstring(`invalid version constraint "this-is-not-semver"`)))
+				Expect(err).To(MatchError(ContainSubstring("improper constraint")))
+			})
+		})
+	})
+}
@@ -0,0 +1,125 @@
+package postal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrNoCompatibleVersion is returned by the Resolve methods when none of the
+// dependencies declared in buildpack.toml satisfy the requested version
+// constraint.
+type ErrNoCompatibleVersion struct {
+	// ID is the id of the dependency that was requested. It is empty when the
+	// error is returned by ResolveAny.
+	ID string
+
+	// IDs are the ids of the dependencies that were considered by ResolveAny.
+	IDs []string
+
+	// Constraint is the version constraint that could not be satisfied, after
+	// any "default" version has been expanded.
+	Constraint string
+
+	// SupportedVersions are the versions available for the requested id. When
+	// the error is returned by ResolveAny, each version is prefixed with the id
+	// it belongs to, as in "some-id@1.2.3".
+	SupportedVersions []string
+}
+
+func (e ErrNoCompatibleVersion) Error() string {
+	if e.IDs != nil {
+		return fmt.Sprintf("failed to satisfy any of %q dependency version constraint %q: no compatible versions. Supported versions are: [%s]", e.IDs, e.Constraint, strings.Join(e.SupportedVersions, ", "))
+	}
+
+	return fmt.Sprintf("failed to satisfy %q dependency version constraint %q: no compatible versions. Supported versions are: [%s]", e.ID, e.Constraint, strings.Join(e.SupportedVersions, ", "))
+}
+
+// ErrChecksumMismatch is returned by Deliver when the SHA256 checksum of the
+// downloaded dependency does not match the checksum it declares.
+type ErrChecksumMismatch struct {
+	// ID is the id of the dependency that was delivered.
+	ID string
+
+	// Version is the version of the dependency that was delivered.
+	Version string
+
+	// SHA256 is the checksum the download was expected to have.
+	SHA256 string
+
+	// Checksums are the checksums, in the "<algorithm>:<hex>" format, that
+	// were tried when the dependency declares more than one. The download was
+	// expected to match at least one of them.
+	Checksums []string
+
+	// Err is the error encountered while extracting the download, if the
+	// download was so corrupt that it could not be extracted.
+	Err error
+}
+
+func (e ErrChecksumMismatch) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("checksum does not match: dependency download is corrupt: %s", e.Err)
+	}
+
+	if e.Checksums != nil {
+		return fmt.Sprintf("checksum does not match: %q version %s was expected to match one of [%s]", e.ID, e.Version, strings.Join(e.Checksums, ", "))
+	}
+
+	return fmt.Sprintf("checksum does not match: %q version %s was expected to have sha256 %s", e.ID, e.Version, e.SHA256)
+}
+
+func newChecksumMismatch(dependency Dependency, err error) ErrChecksumMismatch {
+	mismatch := ErrChecksumMismatch{
+		ID:      dependency.ID,
+		Version: dependency.Version,
+		SHA256:  dependency.SHA256,
+		Err:     err,
+	}
+
+	if len(dependency.Checksums) > 0 {
+		mismatch.Checksums = checksumCandidates(dependency)
+	}
+
+	return mismatch
+}
+
+func (e ErrChecksumMismatch) Unwrap() error {
+	return e.Err
+}
+
+// ErrFetchFailed is returned by Deliver when the dependency cannot be fetched
+// from its URI.
+type ErrFetchFailed struct {
+	// URI is the location the dependency was fetched from, after any dependency
+	// mapping and URI rewriting have been applied.
+	URI string
+
+	// Err is the error returned by the Transport.
+	Err error
+}
+
+func (e ErrFetchFailed) Error() string {
+	return fmt.Sprintf("failed to fetch dependency: %s", e.Err)
+}
+
+func (e ErrFetchFailed) Unwrap() error {
+	return e.Err
+}
+
+// ErrMalformedBuildpackTOML is returned when the buildpack.toml file cannot be
+// parsed or describes its dependencies in an unexpected way.
+type ErrMalformedBuildpackTOML struct {
+	// Path is the location of the buildpack.toml file.
+	Path string
+
+	// Err describes what is wrong with the file.
+	Err error
+}
+
+func (e ErrMalformedBuildpackTOML) Error() string {
+	return fmt.Sprintf("failed to parse buildpack.toml: %s", e.Err)
+}
+
+func (e ErrMalformedBuildpackTOML) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,8 @@
+package postal
+
+// InstallWithPlatformPath behaves like Install, but uses the given platform
+// path in place of /platform so that tests do not depend on the machine they
+// run on.
+func (s Service) InstallWithPlatformPath(dependency Dependency, cnbPath, layerPath, platformPath string) error {
+	return s.install(dependency, cnbPath, layerPath, platformPath)
+}
@@ -0,0 +1,28 @@
+package fakes
+
+import (
+	"sync"
+
+	"github.com/paketo-buildpacks/packit/postal"
+)
+
+type ResolutionLogger struct {
+	DependencyResolutionCall struct {
+		sync.Mutex
+		CallCount int
+		Receives  struct {
+			Resolution postal.Resolution
+		}
+		Stub func(postal.Resolution)
+	}
+}
+
+func (f *ResolutionLogger) DependencyResolution(param1 postal.Resolution) {
+	f.DependencyResolutionCall.Lock()
+	defer f.DependencyResolutionCall.Unlock()
+	f.DependencyResolutionCall.CallCount++
+	f.DependencyResolutionCall.Receives.Resolution = param1
+	if f.DependencyResolutionCall.Stub != nil {
+		f.DependencyResolutionCall.Stub(param1)
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 func TestUnitPostal(t *testing.T) {
 	suite := spec.New("packit/postal", spec.Report(report.Terminal{}))
+	suite("NormalizeConstraint", testNormalizeConstraint)
 	suite("Service", testService)
 
 	suite.Run(t)
@@ -0,0 +1,82 @@
+package postal
+
+import (
+	"sort"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// Resolution describes how one of the Resolve methods selected a dependency.
+// It is given to the ResolutionLogger of the Service after each successful
+// resolution.
+type Resolution struct {
+	// ID is the id of the dependency that was requested. It is empty when the
+	// dependency was resolved by ResolveAny.
+	ID string
+
+	// IDs are the ids of the dependencies that were considered by ResolveAny.
+	IDs []string
+
+	// Constraint is the version constraint that was used, after any "default"
+	// version has been expanded.
+	Constraint string
+
+	// Candidates are the versions that were available for the requested ids and
+	// stacks, ordered from the highest version to the lowest.
+	Candidates []ResolutionCandidate
+
+	// Selected is the dependency that was returned.
+	Selected Dependency
+}
+
+// ResolutionCandidate is a version of a dependency that was considered during
+// a Resolution.
+type ResolutionCandidate struct {
+	// ID is the id of the dependency.
+	ID string
+
+	// Version is the version of the dependency.
+	Version string
+
+	// Compatible indicates whether the version satisfies the constraint of the
+	// Resolution.
+	Compatible bool
+
+	// Selected indicates whether this is the version that was returned.
+	Selected bool
+}
+
+// newResolutionCandidates returns a candidate for each of the supported
+// versions of the given id, marking those that are compatible and the one that
+// was selected.
+func newResolutionCandidates(id string, supportedVersions []string, compatibleVersions []Dependency, selected Dependency) []ResolutionCandidate {
+	var candidates []ResolutionCandidate
+	for _, version := range supportedVersions {
+		candidate := ResolutionCandidate{
+			ID:       id,
+			Version:  version,
+			Selected: id == selected.ID && version == selected.Version,
+		}
+
+		for _, dependency := range compatibleVersions {
+			if dependency.ID == id && dependency.Version == version {
+				candidate.Compatible = true
+				break
+			}
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates
+}
+
+// sortCandidatesByVersion sorts the candidates from the highest version to the
+// lowest, keeping the given order of candidates that share a version.
+func sortCandidatesByVersion(candidates []ResolutionCandidate) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		iVersion := semver.MustParse(candidates[i].Version)
+		jVersion := semver.MustParse(candidates[j].Version)
+		return iVersion.GreaterThan(jVersion)
+	})
+}
@@ -3,6 +3,8 @@ package postal
 import (
 	"fmt"
 	"io"
+	"net/url"
+	"os"
 	"path/filepath"
 	"regexp"
 	"sort"
@@ -12,6 +14,7 @@ import (
 	"github.com/Masterminds/semver/v3"
 	"github.com/paketo-buildpacks/packit"
 	"github.com/paketo-buildpacks/packit/cargo"
+	"github.com/paketo-buildpacks/packit/fs"
 	"github.com/paketo-buildpacks/packit/postal/internal"
 	"github.com/paketo-buildpacks/packit/vacation"
 )
@@ -31,11 +34,52 @@ type MappingResolver interface {
 	FindDependencyMapping(SHA256, bindingPath string) (string, error)
 }
 
+//go:generate faux --interface ResolutionLogger --output fakes/resolution_logger.go
+
+// ResolutionLogger serves as the interface for types that record how the
+// Resolve methods selected a dependency, such as scribe.Emitter.
+type ResolutionLogger interface {
+	DependencyResolution(resolution Resolution)
+}
+
+// DeprecationPolicy determines how the Service treats a dependency that is
+// being delivered after its deprecation date has passed.
+type DeprecationPolicy int
+
+const (
+	// Ignore delivers deprecated dependencies without comment. This is the
+	// default policy.
+	Ignore DeprecationPolicy = iota
+
+	// WarnAfter delivers deprecated dependencies, but writes a warning to the
+	// logger given to the Service.
+	WarnAfter
+
+	// FailAfter refuses to deliver deprecated dependencies, returning an error
+	// from Deliver.
+	FailAfter
+)
+
 // Service provides a mechanism for resolving and installing dependencies given
 // a Transport.
 type Service struct {
-	transport       Transport
-	mappingResolver MappingResolver
+	transport         Transport
+	mappingResolver   MappingResolver
+	deprecationPolicy DeprecationPolicy
+	logger            io.Writer
+	warnOnNoChecksum  bool
+	uriRewriter       func(uri string) string
+	honorConstraints  bool
+	skipChecksum      bool
+	bindingRoots      []string
+	allowedHosts      []string
+	resolutionLogger  ResolutionLogger
+	preValidate       bool
+
+	// skipMissingPlatformBindings is set by Install so that a failure to look
+	// up dependency mappings in the bindings directory of its hardcoded
+	// platform path is ignored when that directory does not exist.
+	skipMissingPlatformBindings bool
 }
 
 // NewService creates an instance of a Servicel given a Transport.
@@ -43,6 +87,7 @@ func NewService(transport Transport) Service {
 	return Service{
 		transport:       transport,
 		mappingResolver: internal.NewDependencyMappingResolver(),
+		logger:          io.Discard,
 	}
 }
 
@@ -51,19 +96,324 @@ func (s Service) WithDependencyMappingResolver(mappingResolver MappingResolver)
 	return s
 }
 
+// WithBindingRoots sets the directories that Deliver searches, in order, for
+// a dependency-mapping binding that matches the dependency being delivered.
+// The first mapping found is used. By default, the directory named by the
+// $SERVICE_BINDING_ROOT environment variable is searched first, followed by
+// the bindings directory of the platform path given to Deliver.
+func (s Service) WithBindingRoots(roots ...string) Service {
+	s.bindingRoots = roots
+	return s
+}
+
+// WithDeprecationPolicy sets the policy used by Deliver when it is given a
+// dependency whose deprecation date is in the past.
+func (s Service) WithDeprecationPolicy(policy DeprecationPolicy) Service {
+	s.deprecationPolicy = policy
+	return s
+}
+
+// WithMissingChecksumWarning configures the Resolve methods to write
+// a warning to the logger when the selected dependency has no SHA256 checksum,
+// rather than returning an error.
+func (s Service) WithMissingChecksumWarning() Service {
+	s.warnOnNoChecksum = true
+	return s
+}
+
+// WithDependencyConstraints configures the Resolve methods to only consider
+// the versions of a dependency that satisfy one of the constraints declared
+// for its id in the metadata.dependency-constraints table of buildpack.toml.
+// Dependencies with no declared constraints are unaffected.
+func (s Service) WithDependencyConstraints() Service {
+	s.honorConstraints = true
+	return s
+}
+
+// WithPreValidate configures Deliver to download each dependency to a
+// temporary file and validate its checksum before extracting it, when
+// preValidate is true. This costs an extra write of the download to disk, but
+// nothing is written to the layer when the download is corrupt. By default,
+// the dependency is validated as it is extracted. Pre-validation has no effect
+// when checksum validation is skipped.
+func (s Service) WithPreValidate(preValidate bool) Service {
+	s.preValidate = preValidate
+	return s
+}
+
+// WithInsecureSkipChecksum configures Deliver to extract dependencies without
+// validating their SHA256 checksum when skip is true. This is intended only for
+// local development against artifacts that change frequently. Every delivery
+// made without validation writes a warning to the logger. Checksums are
+// validated by default.
+func (s Service) WithInsecureSkipChecksum(skip bool) Service {
+	s.skipChecksum = skip
+	return s
+}
+
+// WithURIRewriter sets a function that Deliver uses to rewrite the URI of a
+// dependency before it is fetched, after any dependency mapping has been
+// applied. This can be used to direct every download to a mirror. The
+// dependency is still validated against its original checksum.
+func (s Service) WithURIRewriter(rewriter func(uri string) string) Service {
+	s.uriRewriter = rewriter
+	return s
+}
+
+// WithAllowedHosts restricts Deliver to fetching dependencies and checksum
+// files from the given hosts. The host is checked after any dependency mapping
+// and URI rewriting have been applied, and Deliver returns an error naming the
+// host of any URI that is not allowed. Hosts are compared without their port
+// and without regard to case. URIs without a host, such as file:// URIs, are
+// not restricted. By default, every host is allowed.
+func (s Service) WithAllowedHosts(hosts []string) Service {
+	s.allowedHosts = hosts
+	return s
+}
+
+// WithResolutionLog configures the Resolve methods to give the
+// ResolutionLogger a Resolution describing every version that was considered
+// and the one that was selected each time a dependency is resolved.
+func (s Service) WithResolutionLog(logger ResolutionLogger) Service {
+	s.resolutionLogger = logger
+	return s
+}
+
+// WithLogger sets the writer that the Service will use to report warnings.
+// By default, warnings are discarded.
+func (s Service) WithLogger(logger io.Writer) Service {
+	s.logger = logger
+	return s
+}
+
 // Resolve will pick the best matching dependency given a path to a
 // buildpack.toml file, and the id, version, and stack value of a dependency.
 // The version value is treated as a SemVer constraint and will pick the
 // version that matches that constraint best. If the version is given as
 // "default", the default version for the dependency with the given id will be
-// used. If there is no default version for that dependency, a wildcard
-// constraint will be used.
+// used. A default declared for the stack in the metadata.stack-default-versions
+// table takes precedence over the one in the metadata.default-versions table.
+// If there is no default version for that dependency, a wildcard constraint
+// will be used.
 func (s Service) Resolve(path, id, version, stack string) (Dependency, error) {
-	dependencies, defaultVersion, err := parseBuildpack(path, id)
+	config, err := parseConfig(path)
+	if err != nil {
+		return Dependency{}, err
+	}
+
+	return s.ResolveFromConfig(config, id, version, stack)
+}
+
+// ResolveFromConfig behaves like Resolve, but resolves the dependency from
+// an already parsed buildpack.toml so that buildpacks resolving several
+// dependencies only need to parse the file once.
+func (s Service) ResolveFromConfig(config cargo.Config, id, version, stack string) (Dependency, error) {
+	return s.resolve(metadataFromConfig(config), id, version, []string{stack})
+}
+
+// ResolveForStacks behaves like Resolve, but accepts a set of stacks. A
+// dependency is considered compatible if any of its stacks are included in the
+// given set of stacks.
+func (s Service) ResolveForStacks(path, id, version string, stacks []string) (Dependency, error) {
+	config, err := parseConfig(path)
+	if err != nil {
+		return Dependency{}, err
+	}
+
+	return s.resolve(metadataFromConfig(config), id, version, stacks)
+}
+
+func (s Service) resolve(metadata buildpackMetadata, id, version string, stacks []string) (Dependency, error) {
+	compatibleVersions, supportedVersions, version, err := s.findCompatibleVersions(metadata, id, version, stacks)
+	if err != nil {
+		return Dependency{}, err
+	}
+
+	if len(compatibleVersions) == 0 {
+		return Dependency{}, ErrNoCompatibleVersion{
+			ID:                id,
+			Constraint:        version,
+			SupportedVersions: supportedVersions,
+		}
+	}
+
+	sortByVersion(compatibleVersions)
+
+	err = s.checkChecksum(compatibleVersions[0])
+	if err != nil {
+		return Dependency{}, err
+	}
+
+	if s.resolutionLogger != nil {
+		s.logResolution(Resolution{
+			ID:         id,
+			Constraint: version,
+			Candidates: newResolutionCandidates(id, supportedVersions, compatibleVersions, compatibleVersions[0]),
+			Selected:   compatibleVersions[0],
+		})
+	}
+
+	return compatibleVersions[0], nil
+}
+
+// ResolveAny behaves like Resolve, but considers the dependencies for each of
+// the given ids and picks the version that matches the constraint best across
+// all of them. The ID of the returned Dependency indicates which id was
+// selected. If the version is given as "default", the default version for
+// each id is used when considering the dependencies with that id.
+func (s Service) ResolveAny(path string, ids []string, version, stack string) (Dependency, error) {
+	config, err := parseConfig(path)
+	if err != nil {
+		return Dependency{}, err
+	}
+	metadata := metadataFromConfig(config)
+
+	var candidates []Dependency
+	var supportedVersions []string
+	supportedByID := map[string][]string{}
+	for _, id := range ids {
+		compatibleVersions, supported, _, err := s.findCompatibleVersions(metadata, id, version, []string{stack})
+		if err != nil {
+			return Dependency{}, err
+		}
+
+		candidates = append(candidates, compatibleVersions...)
+		for _, v := range supported {
+			supportedVersions = append(supportedVersions, fmt.Sprintf("%s@%s", id, v))
+		}
+		supportedByID[id] = supported
+	}
+
+	if len(candidates) == 0 {
+		return Dependency{}, ErrNoCompatibleVersion{
+			IDs:               ids,
+			Constraint:        version,
+			SupportedVersions: supportedVersions,
+		}
+	}
+
+	sortByVersion(candidates)
+
+	err = s.checkChecksum(candidates[0])
 	if err != nil {
 		return Dependency{}, err
 	}
 
+	if s.resolutionLogger != nil {
+		var resolutionCandidates []ResolutionCandidate
+		for _, id := range ids {
+			resolutionCandidates = append(resolutionCandidates, newResolutionCandidates(id, supportedByID[id], candidates, candidates[0])...)
+		}
+
+		s.logResolution(Resolution{
+			IDs:        ids,
+			Constraint: version,
+			Candidates: resolutionCandidates,
+			Selected:   candidates[0],
+		})
+	}
+
+	return candidates[0], nil
+}
+
+// logResolution gives the resolution to the ResolutionLogger with its
+// candidates ordered from the highest version to the lowest.
+func (s Service) logResolution(resolution Resolution) {
+	sortCandidatesByVersion(resolution.Candidates)
+	s.resolutionLogger.DependencyResolution(resolution)
+}
+
+// DependencyConstraints returns the entries of the
+// metadata.dependency-constraints table of the buildpack.toml file at the
+// given path.
+func (s Service) DependencyConstraints(path string) ([]DependencyConstraint, error) {
+	config, err := parseConfig(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return metadataFromConfig(config).dependencyConstraints, nil
+}
+
+// findDependencyMapping searches each of the binding roots for a dependency
+// mapping for the given checksum and returns the first one found.
+func (s Service) findDependencyMapping(sha256, platformPath string) (string, error) {
+	// Dependency mappings are keyed by sha256, so there is nothing to look up
+	// for a dependency that declares no sha256 checksum.
+	if sha256 == "" {
+		return "", nil
+	}
+
+	var platformBindings string
+
+	roots := s.bindingRoots
+	if len(roots) == 0 {
+		if root := os.Getenv("SERVICE_BINDING_ROOT"); root != "" {
+			roots = append(roots, root)
+		}
+
+		platformBindings = filepath.Join(platformPath, "bindings")
+		roots = append(roots, platformBindings)
+	}
+
+	for _, root := range roots {
+		uri, err := s.mappingResolver.FindDependencyMapping(sha256, root)
+		if err != nil {
+			if s.skipMissingPlatformBindings && root == platformBindings {
+				if _, statErr := os.Stat(root); os.IsNotExist(statErr) {
+					continue
+				}
+			}
+
+			return "", err
+		}
+
+		if uri != "" {
+			return uri, nil
+		}
+	}
+
+	return "", nil
+}
+
+func (s Service) checkChecksum(dependency Dependency) error {
+	if dependency.SHA256 != "" || len(dependency.Checksums) > 0 || dependency.ChecksumURI != "" {
+		return nil
+	}
+
+	if !s.warnOnNoChecksum {
+		return fmt.Errorf("failed to resolve %q version %s: dependency has no sha256 checksum and cannot be verified", dependency.ID, dependency.Version)
+	}
+
+	fmt.Fprintf(s.logger, "Warning: %q version %s has no sha256 checksum and cannot be verified\n", dependency.ID, dependency.Version)
+
+	return nil
+}
+
+// findCompatibleVersions returns the dependencies with the given id and stacks
+// that satisfy the version constraint, along with every version available for
+// that id and the constraint that was used after the "default" version and
+// pessimistic operator are expanded. When the Service honors dependency
+// constraints, versions outside of the constraints declared for the id are
+// not considered compatible.
+func (s Service) findCompatibleVersions(metadata buildpackMetadata, id, version string, stacks []string) ([]Dependency, []string, string, error) {
+	var authorConstraints []*semver.Constraints
+	if s.honorConstraints {
+		for _, c := range metadata.dependencyConstraints {
+			if c.ID != id {
+				continue
+			}
+
+			constraint, err := semver.NewConstraint(c.Constraint)
+			if err != nil {
+				return nil, nil, "", fmt.Errorf("failed to parse dependency constraint %q for %q: %w", c.Constraint, id, err)
+			}
+
+			authorConstraints = append(authorConstraints, constraint)
+		}
+	}
+
+	defaultVersion := metadata.defaultVersion(id, stacks)
 	if version == "" {
 		version = "default"
 	}
@@ -75,36 +425,30 @@ func (s Service) Resolve(path, id, version, stack string) (Dependency, error) {
 		}
 	}
 
-	// Handle the pessmistic operator (~>)
-	var re = regexp.MustCompile(`~>`)
-	if re.MatchString(version) {
-		res := re.ReplaceAllString(version, "")
-		parts := strings.Split(res, ".")
-
-		// if the version contains a major, minor, and patch use "~" Tilde Range Comparison
-		// if the version contains a major and minor only, or a major version only use "^" Caret Range Comparison
-		if len(parts) == 3 {
-			version = "~" + res
-		} else {
-			version = "^" + res
-		}
+	version, err := NormalizeConstraint(version)
+	if err != nil {
+		return nil, nil, "", err
 	}
 
 	var compatibleVersions []Dependency
 	versionConstraint, err := semver.NewConstraint(version)
 	if err != nil {
-		return Dependency{}, err
+		return nil, nil, "", err
 	}
 
 	var supportedVersions []string
-	for _, dependency := range dependencies {
-		if dependency.ID != id || !stacksInclude(dependency.Stacks, stack) {
+	for _, dependency := range metadata.dependencies {
+		if dependency.ID != id || !stacksIntersect(dependency.Stacks, stacks) {
 			continue
 		}
 
 		sVersion, err := semver.NewVersion(dependency.Version)
 		if err != nil {
-			return Dependency{}, err
+			return nil, nil, "", err
+		}
+
+		if !satisfiesAny(authorConstraints, sVersion) {
+			continue
 		}
 
 		if versionConstraint.Check(sVersion) {
@@ -114,22 +458,33 @@ func (s Service) Resolve(path, id, version, stack string) (Dependency, error) {
 		supportedVersions = append(supportedVersions, dependency.Version)
 	}
 
-	if len(compatibleVersions) == 0 {
-		return Dependency{}, fmt.Errorf(
-			"failed to satisfy %q dependency version constraint %q: no compatible versions. Supported versions are: [%s]",
-			id,
-			version,
-			strings.Join(supportedVersions, ", "),
-		)
+	return compatibleVersions, supportedVersions, version, nil
+}
+
+// satisfiesAny reports whether the version satisfies any of the given
+// constraints, or whether there are no constraints at all.
+func satisfiesAny(constraints []*semver.Constraints, version *semver.Version) bool {
+	if len(constraints) == 0 {
+		return true
 	}
 
-	sort.Slice(compatibleVersions, func(i, j int) bool {
-		iVersion := semver.MustParse(compatibleVersions[i].Version)
-		jVersion := semver.MustParse(compatibleVersions[j].Version)
+	for _, constraint := range constraints {
+		if constraint.Check(version) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sortByVersion sorts the dependencies from the highest version to the lowest,
+// keeping the given order of dependencies that share a version.
+func sortByVersion(dependencies []Dependency) {
+	sort.SliceStable(dependencies, func(i, j int) bool {
+		iVersion := semver.MustParse(dependencies[i].Version)
+		jVersion := semver.MustParse(dependencies[j].Version)
 		return iVersion.GreaterThan(jVersion)
 	})
-
-	return compatibleVersions[0], nil
 }
 
 // Deliver will fetch and expand a dependency into a layer path location. The
@@ -138,47 +493,405 @@ func (s Service) Resolve(path, id, version, stack string) (Dependency, error) {
 // there is a dependency mapping for the specified dependency, Deliver will use
 // the given dependency mapping URI to fetch the dependency. The dependency is
 // validated against the checksum value provided on the Dependency and will
-// error if there are inconsistencies in the fetched result.
+// error if there are inconsistencies in the fetched result. If the Dependency
+// declares Checksums, the fetched result only needs to match one of them or
+// its SHA256. If the Dependency has neither, the checksum is fetched from its
+// ChecksumURI instead. If the Dependency declares a TargetPath, it is
+// extracted into that subdirectory of the layer path rather than the layer
+// path itself.
 func (s Service) Deliver(dependency Dependency, cnbPath, layerPath, platformPath string) error {
-	dependencyMappingURI, err := s.mappingResolver.FindDependencyMapping(dependency.SHA256, filepath.Join(platformPath, "bindings"))
+	err := s.checkDeprecation(dependency, time.Now())
+	if err != nil {
+		return err
+	}
+
+	layerPath, err = targetPath(layerPath, dependency.TargetPath)
+	if err != nil {
+		return err
+	}
+
+	if s.skipChecksum {
+		fmt.Fprintf(s.logger, "Warning: checksum validation is disabled, %q version %s will not be verified\n", dependency.ID, dependency.Version)
+	}
+
+	if dependency.SHA256 == "" && len(dependency.Checksums) == 0 && dependency.ChecksumURI != "" && !s.skipChecksum {
+		dependency.SHA256, err = s.fetchChecksum(cnbPath, dependency.ChecksumURI)
+		if err != nil {
+			return err
+		}
+	}
+
+	dependencyMappingURI, err := s.findDependencyMapping(mappingChecksum(dependency), platformPath)
 	if err != nil {
-		return fmt.Errorf("failure checking out the bindings")
+		return fmt.Errorf("failed to resolve dependency mapping: %w", err)
 	}
 	if dependencyMappingURI != "" {
 		dependency.URI = dependencyMappingURI
 	}
 
+	if s.uriRewriter != nil {
+		dependency.URI = s.uriRewriter(dependency.URI)
+	}
+
+	err = s.checkHost(dependency.URI)
+	if err != nil {
+		return err
+	}
+
+	var existing map[string]struct{}
+	if dependency.FileCount != 0 {
+		existing, err = regularFiles(layerPath)
+		if err != nil {
+			return fmt.Errorf("failed to validate dependency: %w", err)
+		}
+	}
+
 	bundle, err := s.transport.Drop(cnbPath, dependency.URI)
 	if err != nil {
-		return fmt.Errorf("failed to fetch dependency: %s", err)
+		return ErrFetchFailed{URI: dependency.URI, Err: err}
 	}
 	defer bundle.Close()
 
-	validatedReader := cargo.NewValidatedReader(bundle, dependency.SHA256)
-
 	name := filepath.Base(dependency.URI)
-	err = vacation.NewArchive(validatedReader).WithName(name).StripComponents(dependency.StripComponents).Decompress(layerPath)
+	if s.skipChecksum {
+		decompressor, destination, err := newDecompressor(dependency, bundle, name, layerPath)
+		if err != nil {
+			return err
+		}
+
+		err = decompressor.Decompress(destination)
+		if err != nil {
+			return fmt.Errorf("failed to extract dependency: %w", err)
+		}
+
+		return checkFileCount(dependency, layerPath, existing)
+	}
+
+	validatedReader, err := newChecksumValidator(bundle, dependency)
+	if err != nil {
+		return err
+	}
+
+	if s.preValidate {
+		return deliverPreValidated(dependency, validatedReader, name, layerPath, existing)
+	}
+
+	decompressor, destination, err := newDecompressor(dependency, validatedReader, name, layerPath)
 	if err != nil {
 		return err
 	}
 
+	err = decompressor.Decompress(destination)
+	if err != nil {
+		// Drain the rest of the download so that a corrupt download can be told
+		// apart from an intact download whose contents could not be extracted.
+		ok, validErr := validatedReader.Valid()
+		if validErr == nil && !ok {
+			return newChecksumMismatch(dependency, err)
+		}
+
+		return fmt.Errorf("failed to extract dependency: %w", err)
+	}
+
+	ok, err := validatedReader.Valid()
+	if err != nil {
+		return fmt.Errorf("failed to validate dependency: %s", err)
+	}
+
+	if !ok {
+		return newChecksumMismatch(dependency, nil)
+	}
+
+	return checkFileCount(dependency, layerPath, existing)
+}
+
+// ResolveAndDeliver resolves the dependency with the given id, version, and
+// stack from the buildpack.toml file at the given path, just as Resolve does,
+// and then delivers it into the layer path, just as Deliver does. It returns
+// the dependency that was delivered so that it can be reported or recorded in
+// a bill of materials.
+func (s Service) ResolveAndDeliver(path, id, version, stack, cnbPath, layerPath, platformPath string) (Dependency, error) {
+	dependency, err := s.Resolve(path, id, version, stack)
+	if err != nil {
+		return Dependency{}, err
+	}
+
+	err = s.Deliver(dependency, cnbPath, layerPath, platformPath)
+	if err != nil {
+		return Dependency{}, err
+	}
+
+	return dependency, nil
+}
+
+// targetPath returns the directory within the layer into which a dependency
+// with the given target path is extracted, creating it if necessary.
+func targetPath(layerPath, target string) (string, error) {
+	if target == "" {
+		return layerPath, nil
+	}
+
+	rel := filepath.Clean(target)
+	if filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("failed to deliver dependency: target path %q is outside of the layer", target)
+	}
+
+	path := filepath.Join(layerPath, rel)
+	err := os.MkdirAll(path, os.ModePerm)
+	if err != nil {
+		return "", fmt.Errorf("failed to deliver dependency: %w", err)
+	}
+
+	return path, nil
+}
+
+// deliverPreValidated copies the download to a temporary file and validates
+// its checksum before extracting it from that file, so that nothing is written
+// to the layer when the download is corrupt.
+func deliverPreValidated(dependency Dependency, validatedReader checksumValidator, name, layerPath string, existing map[string]struct{}) error {
+	file, err := os.CreateTemp("", "dependency")
+	if err != nil {
+		return fmt.Errorf("failed to download dependency: %w", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	_, err = io.Copy(file, validatedReader)
+	if err != nil {
+		// A validated reader that reaches the end of a corrupt download reports
+		// the mismatch as a read error.
+		ok, validErr := validatedReader.Valid()
+		if validErr == nil && !ok {
+			return newChecksumMismatch(dependency, nil)
+		}
+
+		return fmt.Errorf("failed to download dependency: %w", err)
+	}
+
 	ok, err := validatedReader.Valid()
 	if err != nil {
 		return fmt.Errorf("failed to validate dependency: %s", err)
 	}
 
 	if !ok {
-		return fmt.Errorf("checksum does not match: %s", err)
+		return newChecksumMismatch(dependency, nil)
+	}
+
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		return fmt.Errorf("failed to download dependency: %w", err)
+	}
+
+	decompressor, destination, err := newDecompressor(dependency, file, name, layerPath)
+	if err != nil {
+		return err
+	}
+
+	err = decompressor.Decompress(destination)
+	if err != nil {
+		return fmt.Errorf("failed to extract dependency: %w", err)
+	}
+
+	return checkFileCount(dependency, layerPath, existing)
+}
+
+// checkFileCount compares the number of regular files that the extraction
+// added to the layer with the file count declared by the dependency, if any.
+// The existing files are those that were in the layer before the extraction
+// and are not counted, even if the dependency overwrote them.
+func checkFileCount(dependency Dependency, layerPath string, existing map[string]struct{}) error {
+	if dependency.FileCount == 0 {
+		return nil
+	}
+
+	files, err := regularFiles(layerPath)
+	if err != nil {
+		return fmt.Errorf("failed to validate dependency: %w", err)
+	}
+
+	var count int
+	for path := range files {
+		if _, ok := existing[path]; !ok {
+			count++
+		}
+	}
+
+	if count != dependency.FileCount {
+		return fmt.Errorf("failed to validate dependency: expected %d files but extracted %d", dependency.FileCount, count)
+	}
+
+	return nil
+}
+
+// regularFiles returns the paths of the regular files under the given
+// directory. A directory that does not exist has no regular files.
+func regularFiles(dir string) (map[string]struct{}, error) {
+	files := map[string]struct{}{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if path == dir && os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+
+			return err
+		}
+
+		if info.Mode().IsRegular() {
+			files[path] = struct{}{}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// newDecompressor returns the decompressor for the format of the dependency
+// along with the destination it should decompress into. When the dependency
+// has no format, the format is detected from the contents of the reader.
+func newDecompressor(dependency Dependency, reader io.Reader, name, layerPath string) (vacation.Decompressor, string, error) {
+	// A single xz compressed file is written without its .xz extension.
+	decompressedName := strings.TrimSuffix(name, ".xz")
+
+	switch dependency.Format {
+	case "":
+		return vacation.NewArchive(reader).WithName(decompressedName).StripComponents(dependency.StripComponents), layerPath, nil
+	case "tar":
+		return vacation.NewTarArchive(reader).StripComponents(dependency.StripComponents), layerPath, nil
+	case "tgz":
+		return vacation.NewTarGzipArchive(reader).StripComponents(dependency.StripComponents), layerPath, nil
+	case "txz":
+		return vacation.NewTarXZArchive(reader).StripComponents(dependency.StripComponents), layerPath, nil
+	case "tbz2":
+		return vacation.NewTarBzip2Archive(reader).StripComponents(dependency.StripComponents), layerPath, nil
+	case "tlz4":
+		return vacation.NewTarLZ4Archive(reader).StripComponents(dependency.StripComponents), layerPath, nil
+	case "zip":
+		if dependency.StripComponents > 0 {
+			return nil, "", fmt.Errorf("failed to deliver dependency: format %q does not support strip-components", dependency.Format)
+		}
+
+		return vacation.NewZipArchive(reader), layerPath, nil
+	case "xz":
+		return vacation.NewXZArchive(reader).WithName(decompressedName), layerPath, nil
+	case "raw":
+		return vacation.NewNopArchive(reader), filepath.Join(layerPath, name), nil
+	default:
+		return nil, "", fmt.Errorf("failed to deliver dependency: unsupported format %q", dependency.Format)
+	}
+}
+
+// fetchChecksum downloads the checksum file at the given uri and returns the
+// hex-encoded SHA256 checksum it contains. The file may hold the checksum on
+// its own or followed by a filename, as written by sha256sum.
+func (s Service) fetchChecksum(cnbPath, uri string) (string, error) {
+	if s.uriRewriter != nil {
+		uri = s.uriRewriter(uri)
+	}
+
+	err := s.checkHost(uri)
+	if err != nil {
+		return "", err
+	}
+
+	bundle, err := s.transport.Drop(cnbPath, uri)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch dependency checksum: %s", err)
+	}
+	defer bundle.Close()
+
+	content, err := io.ReadAll(bundle)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch dependency checksum: %s", err)
+	}
+
+	fields := strings.Fields(string(content))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("failed to parse dependency checksum: %s is empty", uri)
+	}
+
+	checksum := strings.ToLower(fields[0])
+	if !regexp.MustCompile(`^[0-9a-f]{64}$`).MatchString(checksum) {
+		return "", fmt.Errorf("failed to parse dependency checksum: %q is not a sha256 checksum", fields[0])
+	}
+
+	return checksum, nil
+}
+
+// checkHost returns an error if the Service restricts the hosts it fetches
+// from and the host of the given uri is not one of them.
+func (s Service) checkHost(uri string) error {
+	if len(s.allowedHosts) == 0 {
+		return nil
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return fmt.Errorf("failed to parse dependency uri: %w", err)
+	}
+
+	host := u.Hostname()
+	if host == "" {
+		return nil
+	}
+
+	for _, allowed := range s.allowedHosts {
+		if strings.EqualFold(host, allowed) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("failed to fetch dependency: host %q is not allowed", host)
+}
+
+func (s Service) checkDeprecation(dependency Dependency, now time.Time) error {
+	if (dependency.DeprecationDate == time.Time{}) || dependency.DeprecationDate.After(now) {
+		return nil
+	}
+
+	deprecationDate := dependency.DeprecationDate.Format("2006-01-02")
+
+	switch s.deprecationPolicy {
+	case FailAfter:
+		return fmt.Errorf("failed to deliver dependency: %q version %s was deprecated on %s", dependency.ID, dependency.Version, deprecationDate)
+	case WarnAfter:
+		fmt.Fprintf(s.logger, "Warning: %q version %s was deprecated on %s\n", dependency.ID, dependency.Version, deprecationDate)
 	}
 
 	return nil
 }
 
 // Install will invoke Deliver with a hardcoded value of /platform for the platform path.
+// If /platform/bindings does not exist, any failure to look up dependency
+// mappings there is ignored.
 //
 // Deprecated: Use Deliver instead.
 func (s Service) Install(dependency Dependency, cnbPath, layerPath string) error {
-	return s.Deliver(dependency, cnbPath, layerPath, "/platform")
+	return s.install(dependency, cnbPath, layerPath, "/platform")
+}
+
+// install delivers the dependency as Install does, using the given platform
+// path in place of /platform.
+func (s Service) install(dependency Dependency, cnbPath, layerPath, platformPath string) error {
+	s.skipMissingPlatformBindings = true
+	return s.Deliver(dependency, cnbPath, layerPath, platformPath)
+}
+
+// VerifyLayer reports whether the contents of the layer at layerPath still
+// match the expected hex-encoded SHA256 checksum, as calculated by
+// fs.ChecksumCalculator.Sum. Buildpacks that reuse a cached layer can use it
+// to confirm that the layer has not changed since its checksum was recorded in
+// the layer metadata.
+func (s Service) VerifyLayer(layerPath, expected string) (bool, error) {
+	sum, err := fs.NewChecksumCalculator().Sum(layerPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to verify layer: %w", err)
+	}
+
+	return sum == expected, nil
 }
 
 // GenerateBillOfMaterials will generate a list of BOMEntry values given a
@@ -200,6 +913,10 @@ func (s Service) GenerateBillOfMaterials(dependencies ...Dependency) []packit.BO
 			entry.Metadata["deprecation-date"] = dependency.DeprecationDate
 		}
 
+		if len(dependency.Licenses) > 0 {
+			entry.Metadata["licenses"] = dependency.Licenses
+		}
+
 		entries = append(entries, entry)
 	}
 
@@ -2,22 +2,30 @@ package postal_test
 
 import (
 	"archive/tar"
+	"archive/zip"
 	"bytes"
 	"compress/gzip"
 	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	dsnetBzip2 "github.com/dsnet/compress/bzip2"
 	"github.com/paketo-buildpacks/packit"
+	"github.com/paketo-buildpacks/packit/cargo"
+	"github.com/paketo-buildpacks/packit/fs"
 	"github.com/paketo-buildpacks/packit/postal"
 	"github.com/paketo-buildpacks/packit/postal/fakes"
+	"github.com/pierrec/lz4/v4"
 	"github.com/sclevine/spec"
+	"github.com/ulikunitz/xz"
 
 	. "github.com/onsi/gomega"
 )
@@ -64,6 +72,8 @@ version = "1.2.5"
 
 [[metadata.dependencies]]
 id = "some-random-entry"
+arch = "arm64"
+os = "linux"
 sha256 = "some-random-sha"
 stacks = ["other-random-stack"]
 uri = "some-uri"
@@ -114,6 +124,12 @@ strip-components = 1
 			}))
 		})
 
+		it("normalizes the version constraint before resolving", func() {
+			dependency, err := service.Resolve(path, "some-entry", " v1.2.x ", "some-stack")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dependency.Version).To(Equal("1.2.3"))
+		})
+
 		context("when there is NOT a default version", func() {
 			context("when the entry version is empty", func() {
 				it("picks the dependency with the highest semantic version number", func() {
@@ -267,6 +283,243 @@ version = "4.5.6"
 			})
 		})
 
+		context("when there is a stack-specific default version", func() {
+			it.Before(func() {
+				err := os.WriteFile(path, []byte(`
+[metadata]
+[metadata.default-versions]
+some-entry = "4.*"
+
+[metadata.stack-default-versions.some-stack]
+some-entry = "1.2.*"
+
+[[metadata.dependencies]]
+id = "some-entry"
+sha256 = "some-sha"
+stacks = ["some-stack", "other-stack"]
+uri = "some-uri"
+version = "1.2.3"
+
+[[metadata.dependencies]]
+id = "some-entry"
+sha256 = "some-sha"
+stacks = ["some-stack", "other-stack"]
+uri = "some-uri"
+version = "4.5.6"
+`), 0600)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			it("picks the dependency that best matches the default for that stack", func() {
+				dependency, err := service.Resolve(path, "some-entry", "default", "some-stack")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dependency.Version).To(Equal("1.2.3"))
+			})
+
+			it("falls back to the global default for other stacks", func() {
+				dependency, err := service.Resolve(path, "some-entry", "default", "other-stack")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dependency.Version).To(Equal("4.5.6"))
+			})
+
+			it("does not apply the default when a version is given", func() {
+				dependency, err := service.Resolve(path, "some-entry", "*", "some-stack")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dependency.Version).To(Equal("4.5.6"))
+			})
+		})
+
+		context("when the dependencies have purl and cpe identifiers", func() {
+			it.Before(func() {
+				err := os.WriteFile(path, []byte(`
+[[metadata.dependencies]]
+cpe = ["cpe:2.3:a:some:entry:1.2.3:*:*:*:*:*:*:*", "cpe:2.3:a:other:entry:1.2.3:*:*:*:*:*:*:*"]
+id = "some-entry"
+purl = "pkg:generic/some-entry@1.2.3"
+sha256 = "some-sha"
+stacks = ["some-stack"]
+uri = "some-uri"
+version = "1.2.3"
+
+[[metadata.dependencies]]
+cpe = "cpe:2.3:a:some:other-entry:4.5.6:*:*:*:*:*:*:*"
+id = "some-other-entry"
+purl = "pkg:generic/some-other-entry@4.5.6"
+sha256 = "some-other-sha"
+stacks = ["some-stack"]
+uri = "some-uri"
+version = "4.5.6"
+`), 0600)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			it("includes them in the resolved dependency", func() {
+				dependency, err := service.Resolve(path, "some-entry", "1.2.3", "some-stack")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dependency).To(Equal(postal.Dependency{
+					CPE: []string{
+						"cpe:2.3:a:some:entry:1.2.3:*:*:*:*:*:*:*",
+						"cpe:2.3:a:other:entry:1.2.3:*:*:*:*:*:*:*",
+					},
+					ID:      "some-entry",
+					PURL:    "pkg:generic/some-entry@1.2.3",
+					Stacks:  []string{"some-stack"},
+					URI:     "some-uri",
+					SHA256:  "some-sha",
+					Version: "1.2.3",
+				}))
+			})
+
+			it("accepts a single cpe string", func() {
+				dependency, err := service.Resolve(path, "some-other-entry", "4.5.6", "some-stack")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dependency.CPE).To(Equal([]string{"cpe:2.3:a:some:other-entry:4.5.6:*:*:*:*:*:*:*"}))
+				Expect(dependency.PURL).To(Equal("pkg:generic/some-other-entry@4.5.6"))
+			})
+		})
+
+		context("when the dependencies have licenses", func() {
+			it.Before(func() {
+				err := os.WriteFile(path, []byte(`
+[[metadata.dependencies]]
+id = "some-entry"
+sha256 = "some-sha"
+stacks = ["some-stack"]
+uri = "some-uri"
+version = "1.2.3"
+
+  [[metadata.dependencies.licenses]]
+  type = "MIT"
+  uri = "https://spdx.org/licenses/MIT.html"
+
+  [[metadata.dependencies.licenses]]
+  type = "Apache-2.0"
+
+[[metadata.dependencies]]
+id = "some-other-entry"
+licenses = ["MIT", "BSD-3-Clause"]
+sha256 = "some-other-sha"
+stacks = ["some-stack"]
+uri = "some-uri"
+version = "4.5.6"
+`), 0600)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			it("parses structured licenses", func() {
+				dependency, err := service.Resolve(path, "some-entry", "1.2.3", "some-stack")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dependency.Licenses).To(Equal([]postal.License{
+					{Type: "MIT", URI: "https://spdx.org/licenses/MIT.html"},
+					{Type: "Apache-2.0"},
+				}))
+			})
+
+			it("parses a legacy list of license strings", func() {
+				dependency, err := service.Resolve(path, "some-other-entry", "4.5.6", "some-stack")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dependency.Licenses).To(Equal([]postal.License{
+					{Type: "MIT"},
+					{Type: "BSD-3-Clause"},
+				}))
+			})
+		})
+
+		context("when a dependency lists the wildcard stack", func() {
+			it.Before(func() {
+				err := os.WriteFile(path, []byte(`
+[[metadata.dependencies]]
+id = "some-entry"
+sha256 = "some-sha"
+stacks = ["*"]
+uri = "some-uri"
+version = "1.2.3"
+`), 0600)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			it("matches any requested stack", func() {
+				for _, stack := range []string{"some-stack", "other-stack"} {
+					dependency, err := service.Resolve(path, "some-entry", "1.2.3", stack)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(dependency).To(Equal(postal.Dependency{
+						ID:      "some-entry",
+						Stacks:  []string{"*"},
+						URI:     "some-uri",
+						SHA256:  "some-sha",
+						Version: "1.2.3",
+					}))
+				}
+			})
+		})
+
+		context("when the selected dependency has no checksum", func() {
+			it.Before(func() {
+				err := os.WriteFile(path, []byte(`
+[[metadata.dependencies]]
+id = "some-entry"
+sha256 = "some-sha"
+stacks = ["some-stack"]
+uri = "some-uri"
+version = "1.2.3"
+
+[[metadata.dependencies]]
+id = "some-entry"
+stacks = ["some-stack"]
+uri = "some-uri"
+version = "4.5.6"
+`), 0600)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			it("resolves a dependency that has a checksum", func() {
+				dependency, err := service.Resolve(path, "some-entry", "1.2.3", "some-stack")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dependency.SHA256).To(Equal("some-sha"))
+			})
+
+			it("returns an error", func() {
+				_, err := service.Resolve(path, "some-entry", "4.5.6", "some-stack")
+				Expect(err).To(MatchError(`failed to resolve "some-entry" version 4.5.6: dependency has no sha256 checksum and cannot be verified`))
+			})
+
+			context("when the dependency has a checksum uri", func() {
+				it.Before(func() {
+					err := os.WriteFile(path, []byte(`
+[[metadata.dependencies]]
+id = "some-entry"
+checksum_uri = "some-uri.sha256"
+stacks = ["some-stack"]
+uri = "some-uri"
+version = "4.5.6"
+`), 0600)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				it("resolves the dependency", func() {
+					dependency, err := service.Resolve(path, "some-entry", "4.5.6", "some-stack")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(dependency.ChecksumURI).To(Equal("some-uri.sha256"))
+				})
+			})
+
+			context("when the service is configured to warn", func() {
+				var buffer *bytes.Buffer
+
+				it.Before(func() {
+					buffer = bytes.NewBuffer(nil)
+					service = service.WithMissingChecksumWarning().WithLogger(buffer)
+				})
+
+				it("resolves the dependency and writes a warning", func() {
+					dependency, err := service.Resolve(path, "some-entry", "4.5.6", "some-stack")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(dependency.Version).To(Equal("4.5.6"))
+					Expect(buffer.String()).To(Equal("Warning: \"some-entry\" version 4.5.6 has no sha256 checksum and cannot be verified\n"))
+				})
+			})
+		})
+
 		context("failure cases", func() {
 			context("when the buildpack.toml is malformed", func() {
 				it.Before(func() {
@@ -277,6 +530,11 @@ version = "4.5.6"
 				it("returns an error", func() {
 					_, err := service.Resolve(path, "some-entry", "1.2.3", "some-stack")
 					Expect(err).To(MatchError(ContainSubstring("failed to parse buildpack.toml")))
+
+					var malformed postal.ErrMalformedBuildpackTOML
+					Expect(errors.As(err, &malformed)).To(BeTrue())
+					Expect(malformed.Path).To(Equal(path))
+					Expect(malformed.Err).To(HaveOccurred())
 				})
 			})
 
@@ -310,148 +568,1075 @@ version = "this is super not semver"
 				it("returns an error with all the supported versions listed", func() {
 					_, err := service.Resolve(path, "some-entry", "9.9.9", "some-stack")
 					Expect(err).To(MatchError(ContainSubstring("failed to satisfy \"some-entry\" dependency version constraint \"9.9.9\": no compatible versions. Supported versions are: [1.2.3, 4.5.6]")))
+
+					var noCompatibleVersion postal.ErrNoCompatibleVersion
+					Expect(errors.As(err, &noCompatibleVersion)).To(BeTrue())
+					Expect(noCompatibleVersion.ID).To(Equal("some-entry"))
+					Expect(noCompatibleVersion.Constraint).To(Equal("9.9.9"))
+					Expect(noCompatibleVersion.SupportedVersions).To(Equal([]string{"1.2.3", "4.5.6"}))
 				})
 			})
 		})
 	})
 
-	context("Deliver", func() {
-		var (
-			dependencySHA string
-			layerPath     string
-			platformPath  string
-			deliver       func() error
-		)
-
-		it.Before(func() {
-			var err error
-			layerPath, err = os.MkdirTemp("", "layer")
+	context("ResolveForStacks", func() {
+		it("finds the best matching dependency for any of the given stacks", func() {
+			dependency, err := service.ResolveForStacks(path, "some-entry", "1.2.*", []string{"some-stack", "other-stack"})
 			Expect(err).NotTo(HaveOccurred())
+			Expect(dependency).To(Equal(postal.Dependency{
+				ID:      "some-entry",
+				Stacks:  []string{"other-stack"},
+				URI:     "some-uri",
+				SHA256:  "some-sha",
+				Version: "1.2.5",
+			}))
+		})
 
-			platformPath, err = os.MkdirTemp("", "platform")
+		it("finds a dependency that lists only one of the given stacks", func() {
+			dependency, err := service.ResolveForStacks(path, "some-random-entry", "*", []string{"some-stack", "other-random-stack"})
 			Expect(err).NotTo(HaveOccurred())
+			Expect(dependency).To(Equal(postal.Dependency{
+				ID:      "some-random-entry",
+				Stacks:  []string{"other-random-stack"},
+				Arch:    "arm64",
+				OS:      "linux",
+				URI:     "some-uri",
+				SHA256:  "some-random-sha",
+				Version: "1.3.0",
+			}))
+		})
 
-			buffer := bytes.NewBuffer(nil)
-			zw := gzip.NewWriter(buffer)
-			tw := tar.NewWriter(zw)
+		context("failure cases", func() {
+			context("when none of the given stacks are supported", func() {
+				it("returns an error", func() {
+					_, err := service.ResolveForStacks(path, "some-random-entry", "*", []string{"some-stack", "other-stack"})
+					Expect(err).To(MatchError(ContainSubstring("failed to satisfy \"some-random-entry\" dependency version constraint \"*\": no compatible versions")))
+				})
+			})
+		})
+	})
 
-			Expect(tw.WriteHeader(&tar.Header{Name: "./some-dir", Mode: 0755, Typeflag: tar.TypeDir})).To(Succeed())
-			_, err = tw.Write(nil)
+	context("when the service honors dependency constraints", func() {
+		it.Before(func() {
+			file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
 			Expect(err).NotTo(HaveOccurred())
 
-			nestedFile := "./some-dir/some-file"
-			Expect(tw.WriteHeader(&tar.Header{Name: nestedFile, Mode: 0755, Size: int64(len(nestedFile))})).To(Succeed())
-			_, err = tw.Write([]byte(nestedFile))
+			_, err = file.WriteString(`
+[[metadata.dependency-constraints]]
+id = "some-entry"
+constraint = "1.*"
+patches = 2
+`)
 			Expect(err).NotTo(HaveOccurred())
+			Expect(file.Close()).To(Succeed())
 
-			for _, file := range []string{"./first", "./second", "./third"} {
-				Expect(tw.WriteHeader(&tar.Header{Name: file, Mode: 0755, Size: int64(len(file))})).To(Succeed())
-				_, err = tw.Write([]byte(file))
-				Expect(err).NotTo(HaveOccurred())
-			}
+			service = service.WithDependencyConstraints()
+		})
 
-			linkName := "./symlink"
-			linkDest := "./first"
-			Expect(tw.WriteHeader(&tar.Header{Name: linkName, Mode: 0777, Size: int64(0), Typeflag: tar.TypeSymlink, Linkname: linkDest})).To(Succeed())
-			_, err = tw.Write([]byte{})
+		it("only considers versions that satisfy the declared constraints", func() {
+			dependency, err := service.Resolve(path, "some-entry", "*", "some-stack")
 			Expect(err).NotTo(HaveOccurred())
-
-			Expect(tw.Close()).To(Succeed())
-			Expect(zw.Close()).To(Succeed())
-
-			sum := sha256.Sum256(buffer.Bytes())
-			dependencySHA = hex.EncodeToString(sum[:])
-
-			transport.DropCall.Returns.ReadCloser = io.NopCloser(buffer)
-
-			deliver = func() error {
-				return service.Deliver(postal.Dependency{
-					ID:      "some-entry",
-					Stacks:  []string{"some-stack"},
-					URI:     "some-entry.tgz",
-					SHA256:  dependencySHA,
-					Version: "1.2.3",
-				}, "some-cnb-path",
-					layerPath,
-					platformPath,
-				)
-			}
+			Expect(dependency.Version).To(Equal("1.2.3"))
 		})
 
-		it.After(func() {
-			Expect(os.RemoveAll(layerPath)).To(Succeed())
+		it("does not affect dependencies without declared constraints", func() {
+			dependency, err := service.Resolve(path, "some-other-entry", "*", "some-stack")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dependency.Version).To(Equal("1.2.4"))
 		})
 
-		it("downloads the dependency and unpackages it into the path", func() {
-			err := deliver()
-
+		it("honors the constraints when resolving from a config", func() {
+			file, err := os.Open(path)
 			Expect(err).NotTo(HaveOccurred())
+			defer file.Close()
 
-			Expect(transport.DropCall.Receives.Root).To(Equal("some-cnb-path"))
-			Expect(transport.DropCall.Receives.Uri).To(Equal("some-entry.tgz"))
-
-			files, err := filepath.Glob(fmt.Sprintf("%s/*", layerPath))
-			Expect(err).NotTo(HaveOccurred())
-			Expect(files).To(ConsistOf([]string{
-				filepath.Join(layerPath, "first"),
-				filepath.Join(layerPath, "second"),
-				filepath.Join(layerPath, "third"),
-				filepath.Join(layerPath, "some-dir"),
-				filepath.Join(layerPath, "symlink"),
-			}))
+			var config cargo.Config
+			Expect(cargo.DecodeConfig(file, &config)).To(Succeed())
 
-			info, err := os.Stat(filepath.Join(layerPath, "first"))
+			dependency, err := service.ResolveFromConfig(config, "some-entry", "*", "some-stack")
 			Expect(err).NotTo(HaveOccurred())
-			Expect(info.Mode()).To(Equal(os.FileMode(0755)))
+			Expect(dependency.Version).To(Equal("1.2.3"))
 		})
 
-		context("when the dependency has a strip-components value set", func() {
-			it.Before(func() {
-				var err error
-				layerPath, err = os.MkdirTemp("", "path")
+		context("failure cases", func() {
+			context("when no version satisfies the declared constraints", func() {
+				it("returns an error", func() {
+					_, err := service.Resolve(path, "some-entry", "4.*", "some-stack")
+					Expect(err).To(MatchError(`failed to satisfy "some-entry" dependency version constraint "4.*": no compatible versions. Supported versions are: [1.2.3]`))
+				})
+			})
+
+			context("when a declared constraint is not valid", func() {
+				it.Before(func() {
+					file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+					Expect(err).NotTo(HaveOccurred())
+
+					_, err = file.WriteString(`
+[[metadata.dependency-constraints]]
+id = "some-entry"
+constraint = "not-a-constraint"
+`)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(file.Close()).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					_, err := service.Resolve(path, "some-entry", "*", "some-stack")
+					Expect(err).To(MatchError(ContainSubstring(`failed to parse dependency constraint "not-a-constraint" for "some-entry"`)))
+				})
+			})
+		})
+	})
+
+	context("DependencyConstraints", func() {
+		it.Before(func() {
+			file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = file.WriteString(`
+[[metadata.dependency-constraints]]
+id = "some-entry"
+constraint = "1.*"
+patches = 2
+
+[[metadata.dependency-constraints]]
+id = "some-other-entry"
+constraint = "~1.2"
+patches = 1
+`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(file.Close()).To(Succeed())
+		})
+
+		it("returns the declared dependency constraints", func() {
+			constraints, err := service.DependencyConstraints(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(constraints).To(Equal([]postal.DependencyConstraint{
+				{ID: "some-entry", Constraint: "1.*", Patches: 2},
+				{ID: "some-other-entry", Constraint: "~1.2", Patches: 1},
+			}))
+		})
+
+		context("failure cases", func() {
+			context("when the buildpack.toml cannot be parsed", func() {
+				it("returns an error", func() {
+					_, err := service.DependencyConstraints("/no/such/buildpack.toml")
+					Expect(err).To(MatchError(ContainSubstring("failed to parse buildpack.toml")))
+				})
+			})
+		})
+	})
+
+	context("WithResolutionLog", func() {
+		var resolutionLogger *fakes.ResolutionLogger
+
+		it.Before(func() {
+			resolutionLogger = &fakes.ResolutionLogger{}
+			service = service.WithResolutionLog(resolutionLogger)
+		})
+
+		it("logs every supported version and marks the one that was selected", func() {
+			dependency, err := service.Resolve(path, "some-entry", "1.2.*", "some-stack")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(resolutionLogger.DependencyResolutionCall.CallCount).To(Equal(1))
+			Expect(resolutionLogger.DependencyResolutionCall.Receives.Resolution).To(Equal(postal.Resolution{
+				ID:         "some-entry",
+				Constraint: "1.2.*",
+				Candidates: []postal.ResolutionCandidate{
+					{ID: "some-entry", Version: "4.5.6"},
+					{ID: "some-entry", Version: "1.2.3", Compatible: true, Selected: true},
+				},
+				Selected: dependency,
+			}))
+		})
+
+		context("when resolving across several ids", func() {
+			it("logs the supported versions of every id", func() {
+				dependency, err := service.ResolveAny(path, []string{"some-other-entry", "some-entry"}, "1.*", "some-stack")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(resolutionLogger.DependencyResolutionCall.CallCount).To(Equal(1))
+				Expect(resolutionLogger.DependencyResolutionCall.Receives.Resolution).To(Equal(postal.Resolution{
+					IDs:        []string{"some-other-entry", "some-entry"},
+					Constraint: "1.*",
+					Candidates: []postal.ResolutionCandidate{
+						{ID: "some-entry", Version: "4.5.6"},
+						{ID: "some-other-entry", Version: "1.2.4", Compatible: true, Selected: true},
+						{ID: "some-entry", Version: "1.2.3", Compatible: true},
+					},
+					Selected: dependency,
+				}))
+			})
+		})
+
+		context("when the dependency cannot be resolved", func() {
+			it("does not log a resolution", func() {
+				_, err := service.Resolve(path, "some-entry", "9.9.9", "some-stack")
+				Expect(err).To(HaveOccurred())
+
+				Expect(resolutionLogger.DependencyResolutionCall.CallCount).To(Equal(0))
+			})
+		})
+	})
+
+	context("ResolveFromConfig", func() {
+		var config cargo.Config
+
+		it.Before(func() {
+			file, err := os.Open(path)
+			Expect(err).NotTo(HaveOccurred())
+			defer file.Close()
+
+			Expect(cargo.DecodeConfig(file, &config)).To(Succeed())
+		})
+
+		it("resolves the same dependencies as Resolve", func() {
+			for _, tt := range []struct {
+				id      string
+				version string
+				stack   string
+			}{
+				{"some-entry", "1.2.*", "some-stack"},
+				{"some-entry", "default", "some-stack"},
+				{"some-entry", "~> 1.2.0", "some-stack"},
+				{"some-entry", "*", "other-stack"},
+				{"some-random-entry", "*", "other-random-stack"},
+			} {
+				expected, err := service.Resolve(path, tt.id, tt.version, tt.stack)
+				Expect(err).NotTo(HaveOccurred())
+
+				dependency, err := service.ResolveFromConfig(config, tt.id, tt.version, tt.stack)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dependency).To(Equal(expected))
+			}
+		})
+
+		context("when the dependency has structured licenses and a cpe list", func() {
+			it.Before(func() {
+				err := os.WriteFile(path, []byte(`
+[[metadata.dependencies]]
+cpe = ["cpe:2.3:a:some:entry:1.2.3:*:*:*:*:*:*:*", "cpe:2.3:a:other:entry:1.2.3:*:*:*:*:*:*:*"]
+id = "some-entry"
+sha256 = "some-sha"
+stacks = ["some-stack"]
+uri = "some-uri"
+version = "1.2.3"
+
+  [[metadata.dependencies.licenses]]
+  type = "MIT"
+  uri = "https://spdx.org/licenses/MIT.html"
+
+  [[metadata.dependencies.licenses]]
+  type = "Apache-2.0"
+`), 0600)
+				Expect(err).NotTo(HaveOccurred())
+
+				file, err := os.Open(path)
+				Expect(err).NotTo(HaveOccurred())
+				defer file.Close()
+
+				config = cargo.Config{}
+				Expect(cargo.DecodeConfig(file, &config)).To(Succeed())
+			})
+
+			it("resolves the same dependency as Resolve", func() {
+				expected, err := service.Resolve(path, "some-entry", "1.2.3", "some-stack")
+				Expect(err).NotTo(HaveOccurred())
+
+				dependency, err := service.ResolveFromConfig(config, "some-entry", "1.2.3", "some-stack")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dependency).To(Equal(expected))
+
+				Expect(dependency.CPE).To(Equal([]string{
+					"cpe:2.3:a:some:entry:1.2.3:*:*:*:*:*:*:*",
+					"cpe:2.3:a:other:entry:1.2.3:*:*:*:*:*:*:*",
+				}))
+				Expect(dependency.Licenses).To(Equal([]postal.License{
+					{Type: "MIT", URI: "https://spdx.org/licenses/MIT.html"},
+					{Type: "Apache-2.0"},
+				}))
+			})
+		})
+
+		context("when there is a default version", func() {
+			it.Before(func() {
+				config.Metadata.DefaultVersions = map[string]string{"some-entry": "1.2.x"}
+			})
+
+			it("picks the dependency that matches the default version", func() {
+				dependency, err := service.ResolveFromConfig(config, "some-entry", "default", "some-stack")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(dependency.Version).To(Equal("1.2.3"))
+			})
+
+			context("when there is a stack-specific default version", func() {
+				it.Before(func() {
+					config.Metadata.StackDefaultVersions = map[string]map[string]string{
+						"some-stack": {"some-entry": "4.*"},
+					}
+				})
+
+				it("picks the dependency that matches the default for that stack", func() {
+					dependency, err := service.ResolveFromConfig(config, "some-entry", "default", "some-stack")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(dependency.Version).To(Equal("4.5.6"))
+
+					dependency, err = service.ResolveFromConfig(config, "some-entry", "default", "other-stack")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(dependency.Version).To(Equal("1.2.5"))
+				})
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the entry version constraint cannot be satisfied", func() {
+				it("returns the same error as Resolve", func() {
+					_, expected := service.Resolve(path, "some-entry", "9.9.9", "some-stack")
+					Expect(expected).To(HaveOccurred())
+
+					_, err := service.ResolveFromConfig(config, "some-entry", "9.9.9", "some-stack")
+					Expect(err).To(MatchError(expected.Error()))
+				})
+			})
+		})
+	})
+
+	context("ResolveAny", func() {
+		it("finds the best matching dependency across all of the given ids", func() {
+			dependency, err := service.ResolveAny(path, []string{"some-entry", "some-random-other-entry", "some-random-entry"}, "*", "some-other-random-stack")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dependency).To(Equal(postal.Dependency{
+				ID:      "some-random-other-entry",
+				Stacks:  []string{"some-other-random-stack"},
+				URI:     "some-uri",
+				SHA256:  "some-random-other-sha",
+				Version: "2.0.0",
+			}))
+		})
+
+		it("selects the highest version when several ids have candidates", func() {
+			dependency, err := service.ResolveAny(path, []string{"some-other-entry", "some-entry"}, "1.*", "some-stack")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dependency.ID).To(Equal("some-other-entry"))
+			Expect(dependency.Version).To(Equal("1.2.4"))
+
+			dependency, err = service.ResolveAny(path, []string{"some-other-entry", "some-entry"}, "*", "some-stack")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dependency.ID).To(Equal("some-entry"))
+			Expect(dependency.Version).To(Equal("4.5.6"))
+		})
+
+		context("failure cases", func() {
+			context("when none of the ids have a compatible version", func() {
+				it("returns an error with all the supported versions listed", func() {
+					_, err := service.ResolveAny(path, []string{"some-entry", "some-other-entry"}, "9.9.9", "some-stack")
+					Expect(err).To(MatchError(`failed to satisfy any of ["some-entry" "some-other-entry"] dependency version constraint "9.9.9": no compatible versions. Supported versions are: [some-entry@1.2.3, some-entry@4.5.6, some-other-entry@1.2.4]`))
+
+					var noCompatibleVersion postal.ErrNoCompatibleVersion
+					Expect(errors.As(err, &noCompatibleVersion)).To(BeTrue())
+					Expect(noCompatibleVersion.ID).To(BeEmpty())
+					Expect(noCompatibleVersion.IDs).To(Equal([]string{"some-entry", "some-other-entry"}))
+					Expect(noCompatibleVersion.Constraint).To(Equal("9.9.9"))
+				})
+			})
+
+			context("when the version constraint is not valid", func() {
+				it("returns an error", func() {
+					_, err := service.ResolveAny(path, []string{"some-entry"}, "this-is-not-semver", "some-stack")
+					Expect(err).To(MatchError(ContainSubstring("improper constraint")))
+				})
+			})
+		})
+	})
+
+	context("Deliver", func() {
+		var (
+			dependencySHA string
+			layerPath     string
+			platformPath  string
+			deliver       func() error
+		)
+
+		it.Before(func() {
+			var err error
+			layerPath, err = os.MkdirTemp("", "layer")
+			Expect(err).NotTo(HaveOccurred())
+
+			platformPath, err = os.MkdirTemp("", "platform")
+			Expect(err).NotTo(HaveOccurred())
+
+			buffer := bytes.NewBuffer(nil)
+			zw := gzip.NewWriter(buffer)
+			tw := tar.NewWriter(zw)
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "./some-dir", Mode: 0755, Typeflag: tar.TypeDir})).To(Succeed())
+			_, err = tw.Write(nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			nestedFile := "./some-dir/some-file"
+			Expect(tw.WriteHeader(&tar.Header{Name: nestedFile, Mode: 0755, Size: int64(len(nestedFile))})).To(Succeed())
+			_, err = tw.Write([]byte(nestedFile))
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, file := range []string{"./first", "./second", "./third"} {
+				Expect(tw.WriteHeader(&tar.Header{Name: file, Mode: 0755, Size: int64(len(file))})).To(Succeed())
+				_, err = tw.Write([]byte(file))
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			linkName := "./symlink"
+			linkDest := "./first"
+			Expect(tw.WriteHeader(&tar.Header{Name: linkName, Mode: 0777, Size: int64(0), Typeflag: tar.TypeSymlink, Linkname: linkDest})).To(Succeed())
+			_, err = tw.Write([]byte{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tw.Close()).To(Succeed())
+			Expect(zw.Close()).To(Succeed())
+
+			sum := sha256.Sum256(buffer.Bytes())
+			dependencySHA = hex.EncodeToString(sum[:])
+
+			transport.DropCall.Returns.ReadCloser = io.NopCloser(buffer)
+
+			deliver = func() error {
+				return service.Deliver(postal.Dependency{
+					ID:      "some-entry",
+					Stacks:  []string{"some-stack"},
+					URI:     "some-entry.tgz",
+					SHA256:  dependencySHA,
+					Version: "1.2.3",
+				}, "some-cnb-path",
+					layerPath,
+					platformPath,
+				)
+			}
+		})
+
+		it.After(func() {
+			Expect(os.RemoveAll(layerPath)).To(Succeed())
+		})
+
+		it("downloads the dependency and unpackages it into the path", func() {
+			err := deliver()
+
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(transport.DropCall.Receives.Root).To(Equal("some-cnb-path"))
+			Expect(transport.DropCall.Receives.Uri).To(Equal("some-entry.tgz"))
+
+			files, err := filepath.Glob(fmt.Sprintf("%s/*", layerPath))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(ConsistOf([]string{
+				filepath.Join(layerPath, "first"),
+				filepath.Join(layerPath, "second"),
+				filepath.Join(layerPath, "third"),
+				filepath.Join(layerPath, "some-dir"),
+				filepath.Join(layerPath, "symlink"),
+			}))
+
+			info, err := os.Stat(filepath.Join(layerPath, "first"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode()).To(Equal(os.FileMode(0755)))
+		})
+
+		context("when the dependency declares several checksums", func() {
+			var (
+				dependencySHA512 string
+				checksums        []string
+			)
+
+			it.Before(func() {
+				content, err := io.ReadAll(transport.DropCall.Returns.ReadCloser)
+				Expect(err).NotTo(HaveOccurred())
+				transport.DropCall.Returns.ReadCloser = io.NopCloser(bytes.NewReader(content))
+
+				sum := sha512.Sum512(content)
+				dependencySHA512 = hex.EncodeToString(sum[:])
+
+				checksums = []string{
+					"sha256:0000000000000000000000000000000000000000000000000000000000000000",
+					fmt.Sprintf("sha512:%s", dependencySHA512),
+				}
+
+				deliver = func() error {
+					return service.Deliver(postal.Dependency{
+						ID:        "some-entry",
+						Stacks:    []string{"some-stack"},
+						URI:       "some-entry.tgz",
+						Checksums: checksums,
+						Version:   "1.2.3",
+					}, "some-cnb-path",
+						layerPath,
+						platformPath,
+					)
+				}
+			})
+
+			it("delivers the dependency when it matches any one of them", func() {
+				err := deliver()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(filepath.Join(layerPath, "first")).To(BeARegularFile())
+				Expect(filepath.Join(layerPath, "some-dir", "some-file")).To(BeARegularFile())
+			})
+
+			context("when a dependency mapping binding is present", func() {
+				var bindingPath string
+
+				it.Before(func() {
+					bindingPath = filepath.Join(platformPath, "bindings", "some-binding")
+					Expect(os.MkdirAll(bindingPath, os.ModePerm)).To(Succeed())
+					Expect(os.WriteFile(filepath.Join(bindingPath, "type"), []byte("dependency-mapping"), 0644)).To(Succeed())
+
+					service = postal.NewService(transport)
+				})
+
+				it("uses the mapping recorded for the sha256 entry of the checksums", func() {
+					Expect(os.WriteFile(filepath.Join(bindingPath, "0000000000000000000000000000000000000000000000000000000000000000"), []byte("mapped-entry.tgz"), 0644)).To(Succeed())
+
+					err := deliver()
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(transport.DropCall.Receives.Uri).To(Equal("mapped-entry.tgz"))
+				})
+
+				context("when none of the checksums is a sha256", func() {
+					it.Before(func() {
+						checksums = []string{fmt.Sprintf("sha512:%s", dependencySHA512)}
+					})
+
+					it("skips the mapping lookup and delivers the dependency", func() {
+						err := deliver()
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(transport.DropCall.Receives.Uri).To(Equal("some-entry.tgz"))
+						Expect(filepath.Join(layerPath, "first")).To(BeARegularFile())
+					})
+				})
+			})
+
+			context("failure cases", func() {
+				context("when none of the checksums match", func() {
+					it.Before(func() {
+						checksums = []string{
+							"sha256:0000000000000000000000000000000000000000000000000000000000000000",
+							"sha512:1111",
+						}
+					})
+
+					it("returns an error listing every checksum that was tried", func() {
+						err := deliver()
+						Expect(err).To(MatchError(`checksum does not match: "some-entry" version 1.2.3 was expected to match one of [sha256:0000000000000000000000000000000000000000000000000000000000000000, sha512:1111]`))
+
+						var checksumMismatch postal.ErrChecksumMismatch
+						Expect(errors.As(err, &checksumMismatch)).To(BeTrue())
+						Expect(checksumMismatch.Checksums).To(Equal(checksums))
+					})
+				})
+
+				context("when a checksum is malformed", func() {
+					it.Before(func() {
+						checksums = []string{"some-checksum"}
+					})
+
+					it("returns an error", func() {
+						err := deliver()
+						Expect(err).To(MatchError(`failed to parse checksum "some-checksum": expected the format <algorithm>:<hex>`))
+					})
+				})
+
+				context("when a checksum uses an unsupported algorithm", func() {
+					it.Before(func() {
+						checksums = []string{"md5:d41d8cd98f00b204e9800998ecf8427e"}
+					})
+
+					it("returns an error", func() {
+						err := deliver()
+						Expect(err).To(MatchError(`failed to parse checksum "md5:d41d8cd98f00b204e9800998ecf8427e": unsupported algorithm "md5"`))
+					})
+				})
+			})
+		})
+
+		context("when the dependency has a strip-components value set", func() {
+			it.Before(func() {
+				var err error
+				layerPath, err = os.MkdirTemp("", "path")
+				Expect(err).NotTo(HaveOccurred())
+
+				buffer := bytes.NewBuffer(nil)
+				zw := gzip.NewWriter(buffer)
+				tw := tar.NewWriter(zw)
+
+				Expect(tw.WriteHeader(&tar.Header{Name: "some-dir", Mode: 0755, Typeflag: tar.TypeDir})).To(Succeed())
+				_, err = tw.Write(nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				nestedFile := "some-dir/some-file"
+				Expect(tw.WriteHeader(&tar.Header{Name: nestedFile, Mode: 0755, Size: int64(len(nestedFile))})).To(Succeed())
+				_, err = tw.Write([]byte(nestedFile))
+				Expect(err).NotTo(HaveOccurred())
+
+				for _, file := range []string{"some-dir/first", "some-dir/second", "some-dir/third"} {
+					Expect(tw.WriteHeader(&tar.Header{Name: file, Mode: 0755, Size: int64(len(file))})).To(Succeed())
+					_, err = tw.Write([]byte(file))
+					Expect(err).NotTo(HaveOccurred())
+				}
+
+				linkName := "some-dir/symlink"
+				linkDest := "./first"
+				Expect(tw.WriteHeader(&tar.Header{Name: linkName, Mode: 0777, Size: int64(0), Typeflag: tar.TypeSymlink, Linkname: linkDest})).To(Succeed())
+				_, err = tw.Write([]byte{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(tw.Close()).To(Succeed())
+				Expect(zw.Close()).To(Succeed())
+
+				sum := sha256.Sum256(buffer.Bytes())
+				dependencySHA = hex.EncodeToString(sum[:])
+
+				transport.DropCall.Returns.ReadCloser = io.NopCloser(buffer)
+
+				deliver = func() error {
+					return service.Deliver(postal.Dependency{
+						ID:              "some-entry",
+						Stacks:          []string{"some-stack"},
+						URI:             "some-entry.tgz",
+						SHA256:          dependencySHA,
+						Version:         "1.2.3",
+						StripComponents: 1,
+					}, "some-cnb-path",
+						layerPath,
+						platformPath,
+					)
+				}
+			})
+
+			it.After(func() {
+				Expect(os.RemoveAll(layerPath)).To(Succeed())
+			})
+
+			it("downloads the dependency, strips given number of componenets and unpackages it into the path", func() {
+				err := deliver()
+
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(transport.DropCall.Receives.Root).To(Equal("some-cnb-path"))
+				Expect(transport.DropCall.Receives.Uri).To(Equal("some-entry.tgz"))
+
+				files, err := filepath.Glob(fmt.Sprintf("%s/*", layerPath))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(files).To(ConsistOf([]string{
+					filepath.Join(layerPath, "first"),
+					filepath.Join(layerPath, "second"),
+					filepath.Join(layerPath, "third"),
+					filepath.Join(layerPath, "symlink"),
+					filepath.Join(layerPath, "some-file"),
+				}))
+
+				info, err := os.Stat(filepath.Join(layerPath, "first"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Mode()).To(Equal(os.FileMode(0755)))
+			})
+		})
+
+		context("when the dependency should be a named file", func() {
+			it.Before(func() {
+				var err error
+				layerPath, err = os.MkdirTemp("", "path")
+				Expect(err).NotTo(HaveOccurred())
+
+				buffer := bytes.NewBuffer(nil)
+				buffer.WriteString("some-file-contents")
+
+				sum := sha256.Sum256(buffer.Bytes())
+				dependencySHA = hex.EncodeToString(sum[:])
+
+				transport.DropCall.Returns.ReadCloser = io.NopCloser(buffer)
+
+				deliver = func() error {
+					return service.Deliver(postal.Dependency{
+						ID:      "some-entry",
+						Stacks:  []string{"some-stack"},
+						URI:     "https://dependencies.example.com/dependencies/some-file-name.txt",
+						SHA256:  dependencySHA,
+						Version: "1.2.3",
+					}, "some-cnb-path",
+						layerPath,
+						platformPath,
+					)
+				}
+			})
+
+			it.After(func() {
+				Expect(os.RemoveAll(layerPath)).To(Succeed())
+			})
+
+			it("downloads the dependency and copies it into the path with the given name", func() {
+				err := deliver()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(transport.DropCall.Receives.Root).To(Equal("some-cnb-path"))
+				Expect(transport.DropCall.Receives.Uri).To(Equal("https://dependencies.example.com/dependencies/some-file-name.txt"))
+
+				files, err := filepath.Glob(fmt.Sprintf("%s/*", layerPath))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(files).To(ConsistOf([]string{filepath.Join(layerPath, "some-file-name.txt")}))
+
+				content, err := os.ReadFile(filepath.Join(layerPath, "some-file-name.txt"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(Equal("some-file-contents"))
+			})
+		})
+
+		context("when there is a dependency mapping via binding", func() {
+			it.Before(func() {
+				mappingResolver.FindDependencyMappingCall.Returns.String = "dependency-mapping-entry.tgz"
+			})
+
+			it("looks up the dependency from the platform binding and downloads that instead", func() {
+				err := deliver()
+
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(mappingResolver.FindDependencyMappingCall.Receives.SHA256).To(Equal(dependencySHA))
+				Expect(mappingResolver.FindDependencyMappingCall.Receives.BindingPath).To(Equal(filepath.Join(platformPath, "bindings")))
+				Expect(transport.DropCall.Receives.Root).To(Equal("some-cnb-path"))
+				Expect(transport.DropCall.Receives.Uri).To(Equal("dependency-mapping-entry.tgz"))
+
+				files, err := filepath.Glob(fmt.Sprintf("%s/*", layerPath))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(files).To(ConsistOf([]string{
+					filepath.Join(layerPath, "first"),
+					filepath.Join(layerPath, "second"),
+					filepath.Join(layerPath, "third"),
+					filepath.Join(layerPath, "some-dir"),
+					filepath.Join(layerPath, "symlink"),
+				}))
+
+				info, err := os.Stat(filepath.Join(layerPath, "first"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Mode()).To(Equal(os.FileMode(0755)))
+			})
+		})
+
+		context("when the dependency declares a file count", func() {
+			var fileCount int
+
+			it.Before(func() {
+				deliver = func() error {
+					return service.Deliver(postal.Dependency{
+						ID:        "some-entry",
+						Stacks:    []string{"some-stack"},
+						URI:       "some-entry.tgz",
+						SHA256:    dependencySHA,
+						Version:   "1.2.3",
+						FileCount: fileCount,
+					}, "some-cnb-path",
+						layerPath,
+						platformPath,
+					)
+				}
+			})
+
+			context("when the count matches the extracted files", func() {
+				it.Before(func() {
+					fileCount = 4
+				})
+
+				it("delivers the dependency", func() {
+					Expect(deliver()).To(Succeed())
+					Expect(filepath.Join(layerPath, "some-dir", "some-file")).To(BeARegularFile())
+				})
+			})
+
+			context("when the layer already contains files", func() {
+				it.Before(func() {
+					fileCount = 4
+
+					Expect(os.WriteFile(filepath.Join(layerPath, "existing-file"), []byte("existing"), 0644)).To(Succeed())
+					Expect(os.MkdirAll(filepath.Join(layerPath, "some-dir"), os.ModePerm)).To(Succeed())
+					Expect(os.WriteFile(filepath.Join(layerPath, "some-dir", "other-file"), []byte("other"), 0644)).To(Succeed())
+				})
+
+				it("only counts the files that were extracted", func() {
+					Expect(deliver()).To(Succeed())
+					Expect(filepath.Join(layerPath, "existing-file")).To(BeARegularFile())
+					Expect(filepath.Join(layerPath, "some-dir", "some-file")).To(BeARegularFile())
+				})
+			})
+
+			context("when the count does not match the extracted files", func() {
+				it.Before(func() {
+					fileCount = 5
+				})
+
+				it("returns an error", func() {
+					err := deliver()
+					Expect(err).To(MatchError("failed to validate dependency: expected 5 files but extracted 4"))
+				})
+			})
+		})
+
+		context("when the dependencies declare target paths", func() {
+			var content []byte
+
+			it.Before(func() {
+				var err error
+				content, err = io.ReadAll(transport.DropCall.Returns.ReadCloser)
+				Expect(err).NotTo(HaveOccurred())
+
+				deliver = func() error {
+					for _, target := range []string{"first-dependency", filepath.Join("nested", "second-dependency")} {
+						transport.DropCall.Returns.ReadCloser = io.NopCloser(bytes.NewReader(content))
+
+						err := service.Deliver(postal.Dependency{
+							ID:         "some-entry",
+							Stacks:     []string{"some-stack"},
+							URI:        "some-entry.tgz",
+							SHA256:     dependencySHA,
+							Version:    "1.2.3",
+							FileCount:  4,
+							TargetPath: target,
+						}, "some-cnb-path",
+							layerPath,
+							platformPath,
+						)
+						if err != nil {
+							return err
+						}
+					}
+
+					return nil
+				}
+			})
+
+			it("unpackages each dependency into its own subdirectory of the layer", func() {
+				Expect(deliver()).To(Succeed())
+
+				files, err := filepath.Glob(fmt.Sprintf("%s/*", layerPath))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(files).To(ConsistOf([]string{
+					filepath.Join(layerPath, "first-dependency"),
+					filepath.Join(layerPath, "nested"),
+				}))
+
+				for _, target := range []string{"first-dependency", filepath.Join("nested", "second-dependency")} {
+					Expect(filepath.Join(layerPath, target, "first")).To(BeARegularFile())
+					Expect(filepath.Join(layerPath, target, "some-dir", "some-file")).To(BeARegularFile())
+				}
+			})
+
+			context("failure cases", func() {
+				for _, target := range []string{"../outside", "some-dir/../../outside", "/some/absolute/path"} {
+					target := target
+
+					context(fmt.Sprintf("when the target path is %q", target), func() {
+						it("returns an error without fetching the dependency", func() {
+							err := service.Deliver(postal.Dependency{
+								ID:         "some-entry",
+								Stacks:     []string{"some-stack"},
+								URI:        "some-entry.tgz",
+								SHA256:     dependencySHA,
+								Version:    "1.2.3",
+								TargetPath: target,
+							}, "some-cnb-path",
+								layerPath,
+								platformPath,
+							)
+							Expect(err).To(MatchError(fmt.Sprintf("failed to deliver dependency: target path %q is outside of the layer", target)))
+							Expect(transport.DropCall.CallCount).To(Equal(0))
+						})
+					})
+				}
+			})
+		})
+
+		context("when there are several binding roots", func() {
+			var searched []string
+
+			it.Before(func() {
+				searched = nil
+				mappingResolver.FindDependencyMappingCall.Stub = func(sha256, bindingPath string) (string, error) {
+					searched = append(searched, bindingPath)
+					if bindingPath == filepath.Join(platformPath, "bindings") {
+						return "dependency-mapping-entry.tgz", nil
+					}
+
+					return "", nil
+				}
+
+				Expect(os.Setenv("SERVICE_BINDING_ROOT", "/some/binding/root")).To(Succeed())
+			})
+
+			it.After(func() {
+				Expect(os.Unsetenv("SERVICE_BINDING_ROOT")).To(Succeed())
+			})
+
+			it("searches $SERVICE_BINDING_ROOT before the platform bindings", func() {
+				err := deliver()
 				Expect(err).NotTo(HaveOccurred())
 
-				buffer := bytes.NewBuffer(nil)
-				zw := gzip.NewWriter(buffer)
-				tw := tar.NewWriter(zw)
+				Expect(searched).To(Equal([]string{
+					"/some/binding/root",
+					filepath.Join(platformPath, "bindings"),
+				}))
+				Expect(transport.DropCall.Receives.Uri).To(Equal("dependency-mapping-entry.tgz"))
+			})
 
-				Expect(tw.WriteHeader(&tar.Header{Name: "some-dir", Mode: 0755, Typeflag: tar.TypeDir})).To(Succeed())
-				_, err = tw.Write(nil)
+			context("when the binding roots are configured", func() {
+				it.Before(func() {
+					service = service.WithBindingRoots("/first/root", filepath.Join(platformPath, "bindings"), "/last/root")
+				})
+
+				it("searches the configured roots until a mapping is found", func() {
+					err := deliver()
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(searched).To(Equal([]string{
+						"/first/root",
+						filepath.Join(platformPath, "bindings"),
+					}))
+					Expect(transport.DropCall.Receives.Uri).To(Equal("dependency-mapping-entry.tgz"))
+				})
+			})
+		})
+
+		context("when the dependency has a checksum uri instead of a sha256", func() {
+			var (
+				checksumFile string
+				archive      []byte
+			)
+
+			it.Before(func() {
+				var err error
+				archive, err = io.ReadAll(transport.DropCall.Returns.ReadCloser)
 				Expect(err).NotTo(HaveOccurred())
 
-				nestedFile := "some-dir/some-file"
-				Expect(tw.WriteHeader(&tar.Header{Name: nestedFile, Mode: 0755, Size: int64(len(nestedFile))})).To(Succeed())
-				_, err = tw.Write([]byte(nestedFile))
+				checksumFile = dependencySHA
+
+				transport.DropCall.Stub = func(root, uri string) (io.ReadCloser, error) {
+					if uri == "some-entry.tgz.sha256" {
+						return io.NopCloser(strings.NewReader(checksumFile)), nil
+					}
+
+					return io.NopCloser(bytes.NewReader(archive)), nil
+				}
+
+				deliver = func() error {
+					return service.Deliver(postal.Dependency{
+						ID:          "some-entry",
+						Stacks:      []string{"some-stack"},
+						URI:         "some-entry.tgz",
+						ChecksumURI: "some-entry.tgz.sha256",
+						Version:     "1.2.3",
+					}, "some-cnb-path",
+						layerPath,
+						platformPath,
+					)
+				}
+			})
+
+			it("validates the dependency against the fetched checksum", func() {
+				err := deliver()
 				Expect(err).NotTo(HaveOccurred())
 
-				for _, file := range []string{"some-dir/first", "some-dir/second", "some-dir/third"} {
-					Expect(tw.WriteHeader(&tar.Header{Name: file, Mode: 0755, Size: int64(len(file))})).To(Succeed())
-					_, err = tw.Write([]byte(file))
+				Expect(mappingResolver.FindDependencyMappingCall.Receives.SHA256).To(Equal(dependencySHA))
+				Expect(filepath.Join(layerPath, "first")).To(BeARegularFile())
+			})
+
+			context("when the checksum file includes a filename", func() {
+				it.Before(func() {
+					checksumFile = fmt.Sprintf("%s  some-entry.tgz\n", strings.ToUpper(dependencySHA))
+				})
+
+				it("validates the dependency against the fetched checksum", func() {
+					err := deliver()
 					Expect(err).NotTo(HaveOccurred())
-				}
 
-				linkName := "some-dir/symlink"
-				linkDest := "./first"
-				Expect(tw.WriteHeader(&tar.Header{Name: linkName, Mode: 0777, Size: int64(0), Typeflag: tar.TypeSymlink, Linkname: linkDest})).To(Succeed())
-				_, err = tw.Write([]byte{})
+					Expect(mappingResolver.FindDependencyMappingCall.Receives.SHA256).To(Equal(dependencySHA))
+					Expect(filepath.Join(layerPath, "first")).To(BeARegularFile())
+				})
+			})
+
+			context("failure cases", func() {
+				context("when the checksum file cannot be fetched", func() {
+					it.Before(func() {
+						transport.DropCall.Stub = func(root, uri string) (io.ReadCloser, error) {
+							return nil, errors.New("there was an error")
+						}
+					})
+
+					it("returns an error", func() {
+						err := deliver()
+						Expect(err).To(MatchError("failed to fetch dependency checksum: there was an error"))
+					})
+				})
+
+				context("when the checksum file is empty", func() {
+					it.Before(func() {
+						checksumFile = "\n"
+					})
+
+					it("returns an error", func() {
+						err := deliver()
+						Expect(err).To(MatchError("failed to parse dependency checksum: some-entry.tgz.sha256 is empty"))
+					})
+				})
+
+				context("when the checksum file does not contain a sha256 checksum", func() {
+					it.Before(func() {
+						checksumFile = "some-checksum  some-entry.tgz"
+					})
+
+					it("returns an error", func() {
+						err := deliver()
+						Expect(err).To(MatchError(`failed to parse dependency checksum: "some-checksum" is not a sha256 checksum`))
+					})
+				})
+
+				context("when the fetched checksum does not match the dependency", func() {
+					it.Before(func() {
+						checksumFile = strings.Repeat("a", 64)
+					})
+
+					it("returns an error", func() {
+						err := deliver()
+						Expect(err).To(MatchError(ContainSubstring("checksum does not match")))
+					})
+				})
+			})
+		})
+
+		context("when the dependency has a format", func() {
+			var (
+				tarball       []byte
+				deliverFormat func(format string, content []byte) error
+			)
+
+			it.Before(func() {
+				buffer := bytes.NewBuffer(nil)
+				tw := tar.NewWriter(buffer)
+
+				Expect(tw.WriteHeader(&tar.Header{Name: "some-file", Mode: 0644, Size: int64(len("some content"))})).To(Succeed())
+				_, err := tw.Write([]byte("some content"))
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(tw.Close()).To(Succeed())
-				Expect(zw.Close()).To(Succeed())
-
-				sum := sha256.Sum256(buffer.Bytes())
-				dependencySHA = hex.EncodeToString(sum[:])
+				tarball = buffer.Bytes()
 
-				transport.DropCall.Returns.ReadCloser = io.NopCloser(buffer)
+				deliverFormat = func(format string, content []byte) error {
+					transport.DropCall.Returns.ReadCloser = io.NopCloser(bytes.NewReader(content))
 
-				deliver = func() error {
+					sum := sha256.Sum256(content)
 					return service.Deliver(postal.Dependency{
-						ID:              "some-entry",
-						Stacks:          []string{"some-stack"},
-						URI:             "some-entry.tgz",
-						SHA256:          dependencySHA,
-						Version:         "1.2.3",
-						StripComponents: 1,
+						ID:      "some-entry",
+						Stacks:  []string{"some-stack"},
+						URI:     fmt.Sprintf("some-entry.%s", format),
+						SHA256:  hex.EncodeToString(sum[:]),
+						Version: "1.2.3",
+						Format:  format,
 					}, "some-cnb-path",
 						layerPath,
 						platformPath,
@@ -459,17 +1644,171 @@ version = "this is super not semver"
 				}
 			})
 
-			it.After(func() {
-				Expect(os.RemoveAll(layerPath)).To(Succeed())
+			compress := func(newWriter func(io.Writer) io.WriteCloser) func([]byte) []byte {
+				return func(content []byte) []byte {
+					buffer := bytes.NewBuffer(nil)
+					w := newWriter(buffer)
+
+					_, err := w.Write(content)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(w.Close()).To(Succeed())
+
+					return buffer.Bytes()
+				}
+			}
+
+			for _, tt := range []struct {
+				format  string
+				archive func([]byte) []byte
+			}{
+				{"tar", func(content []byte) []byte { return content }},
+				{"tgz", compress(func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) })},
+				{"txz", compress(func(w io.Writer) io.WriteCloser {
+					xw, err := xz.NewWriter(w)
+					Expect(err).NotTo(HaveOccurred())
+					return xw
+				})},
+				{"tbz2", compress(func(w io.Writer) io.WriteCloser {
+					bw, err := dsnetBzip2.NewWriter(w, nil)
+					Expect(err).NotTo(HaveOccurred())
+					return bw
+				})},
+				{"tlz4", compress(func(w io.Writer) io.WriteCloser { return lz4.NewWriter(w) })},
+			} {
+				tt := tt
+
+				it(fmt.Sprintf("decompresses a %s dependency", tt.format), func() {
+					err := deliverFormat(tt.format, tt.archive(tarball))
+					Expect(err).NotTo(HaveOccurred())
+
+					content, err := os.ReadFile(filepath.Join(layerPath, "some-file"))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(content)).To(Equal("some content"))
+				})
+			}
+
+			it("decompresses a zip dependency", func() {
+				buffer := bytes.NewBuffer(nil)
+				zw := zip.NewWriter(buffer)
+
+				f, err := zw.Create("some-file")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = f.Write([]byte("some content"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(zw.Close()).To(Succeed())
+
+				err = deliverFormat("zip", buffer.Bytes())
+				Expect(err).NotTo(HaveOccurred())
+
+				content, err := os.ReadFile(filepath.Join(layerPath, "some-file"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(Equal("some content"))
 			})
 
-			it("downloads the dependency, strips given number of componenets and unpackages it into the path", func() {
-				err := deliver()
+			it("decompresses a single xz compressed file dependency", func() {
+				archive := compress(func(w io.Writer) io.WriteCloser {
+					xw, err := xz.NewWriter(w)
+					Expect(err).NotTo(HaveOccurred())
+					return xw
+				})([]byte("some content"))
 
+				err := deliverFormat("xz", archive)
 				Expect(err).NotTo(HaveOccurred())
 
-				Expect(transport.DropCall.Receives.Root).To(Equal("some-cnb-path"))
-				Expect(transport.DropCall.Receives.Uri).To(Equal("some-entry.tgz"))
+				content, err := os.ReadFile(filepath.Join(layerPath, "some-entry"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(Equal("some content"))
+			})
+
+			context("when the format is detected", func() {
+				it("writes a single xz compressed file without its extension", func() {
+					archive := compress(func(w io.Writer) io.WriteCloser {
+						xw, err := xz.NewWriter(w)
+						Expect(err).NotTo(HaveOccurred())
+						return xw
+					})([]byte("some content"))
+
+					transport.DropCall.Returns.ReadCloser = io.NopCloser(bytes.NewReader(archive))
+
+					sum := sha256.Sum256(archive)
+					err := service.Deliver(postal.Dependency{
+						ID:      "some-entry",
+						Stacks:  []string{"some-stack"},
+						URI:     "some-tool.xz",
+						SHA256:  hex.EncodeToString(sum[:]),
+						Version: "1.2.3",
+					}, "some-cnb-path",
+						layerPath,
+						platformPath,
+					)
+					Expect(err).NotTo(HaveOccurred())
+
+					files, err := filepath.Glob(filepath.Join(layerPath, "*"))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(files).To(ConsistOf(filepath.Join(layerPath, "some-tool")))
+
+					content, err := os.ReadFile(filepath.Join(layerPath, "some-tool"))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(content)).To(Equal("some content"))
+				})
+			})
+
+			it("copies a raw dependency without decompressing it", func() {
+				err := deliverFormat("raw", tarball)
+				Expect(err).NotTo(HaveOccurred())
+
+				content, err := os.ReadFile(filepath.Join(layerPath, "some-entry.raw"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(content).To(Equal(tarball))
+			})
+
+			context("failure cases", func() {
+				context("when the dependency does not match the format", func() {
+					it("returns an error", func() {
+						err := deliverFormat("zip", tarball)
+						Expect(err).To(MatchError(ContainSubstring("failed to create zip reader")))
+					})
+				})
+
+				context("when a zip dependency strips components", func() {
+					it("returns an error", func() {
+						transport.DropCall.Returns.ReadCloser = io.NopCloser(bytes.NewReader(tarball))
+
+						sum := sha256.Sum256(tarball)
+						err := service.Deliver(postal.Dependency{
+							ID:              "some-entry",
+							Stacks:          []string{"some-stack"},
+							URI:             "some-entry.zip",
+							SHA256:          hex.EncodeToString(sum[:]),
+							Version:         "1.2.3",
+							Format:          "zip",
+							StripComponents: 1,
+						}, "some-cnb-path",
+							layerPath,
+							platformPath,
+						)
+						Expect(err).To(MatchError(`failed to deliver dependency: format "zip" does not support strip-components`))
+					})
+				})
+
+				context("when the format is not supported", func() {
+					it("returns an error", func() {
+						err := deliverFormat("zst", tarball)
+						Expect(err).To(MatchError(`failed to deliver dependency: unsupported format "zst"`))
+					})
+				})
+			})
+		})
+
+		context("when the dependency is pre-validated", func() {
+			it.Before(func() {
+				service = service.WithPreValidate(true)
+			})
+
+			it("validates the download and then unpackages it into the path", func() {
+				err := deliver()
+				Expect(err).NotTo(HaveOccurred())
 
 				files, err := filepath.Glob(fmt.Sprintf("%s/*", layerPath))
 				Expect(err).NotTo(HaveOccurred())
@@ -477,35 +1816,139 @@ version = "this is super not semver"
 					filepath.Join(layerPath, "first"),
 					filepath.Join(layerPath, "second"),
 					filepath.Join(layerPath, "third"),
+					filepath.Join(layerPath, "some-dir"),
 					filepath.Join(layerPath, "symlink"),
-					filepath.Join(layerPath, "some-file"),
 				}))
 
-				info, err := os.Stat(filepath.Join(layerPath, "first"))
+				content, err := os.ReadFile(filepath.Join(layerPath, "some-dir", "some-file"))
 				Expect(err).NotTo(HaveOccurred())
-				Expect(info.Mode()).To(Equal(os.FileMode(0755)))
+				Expect(string(content)).To(Equal("./some-dir/some-file"))
 			})
 		})
 
-		context("when the dependency should be a named file", func() {
+		context("when the dependency checksum does not match", func() {
 			it.Before(func() {
-				var err error
-				layerPath, err = os.MkdirTemp("", "path")
+				dependencySHA = "some-other-sha"
+			})
+
+			it("fails to deliver the dependency", func() {
+				err := deliver()
+				Expect(err).To(MatchError(ContainSubstring("checksum does not match")))
+			})
+
+			context("when the dependency is pre-validated", func() {
+				it.Before(func() {
+					service = service.WithPreValidate(true)
+				})
+
+				it("fails without writing any files into the layer", func() {
+					err := deliver()
+					Expect(err).To(MatchError(postal.ErrChecksumMismatch{
+						ID:      "some-entry",
+						Version: "1.2.3",
+						SHA256:  "some-other-sha",
+					}))
+
+					files, err := filepath.Glob(fmt.Sprintf("%s/*", layerPath))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(files).To(BeEmpty())
+				})
+			})
+
+			context("when checksum validation is skipped", func() {
+				var logger *bytes.Buffer
+
+				it.Before(func() {
+					logger = bytes.NewBuffer(nil)
+					service = service.WithInsecureSkipChecksum(true).WithLogger(logger)
+				})
+
+				it("extracts the dependency and warns that it was not verified", func() {
+					err := deliver()
+					Expect(err).NotTo(HaveOccurred())
+
+					content, err := os.ReadFile(filepath.Join(layerPath, "first"))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(content)).To(Equal("./first"))
+
+					Expect(logger.String()).To(ContainSubstring(`Warning: checksum validation is disabled, "some-entry" version 1.2.3 will not be verified`))
+				})
+			})
+
+			context("when checksum validation is explicitly not skipped", func() {
+				it.Before(func() {
+					service = service.WithInsecureSkipChecksum(false)
+				})
+
+				it("fails to deliver the dependency", func() {
+					err := deliver()
+					Expect(err).To(MatchError(ContainSubstring("checksum does not match")))
+				})
+			})
+		})
+
+		context("when a uri rewriter is set", func() {
+			it.Before(func() {
+				deliver = func() error {
+					return service.WithURIRewriter(func(uri string) string {
+						return strings.Replace(uri, "https://public.example.com/", "https://mirror.internal/", 1)
+					}).Deliver(postal.Dependency{
+						ID:      "some-entry",
+						Stacks:  []string{"some-stack"},
+						URI:     "https://public.example.com/some-entry.tgz",
+						SHA256:  dependencySHA,
+						Version: "1.2.3",
+					}, "some-cnb-path",
+						layerPath,
+						platformPath,
+					)
+				}
+			})
+
+			it("downloads the dependency from the rewritten uri and validates it", func() {
+				err := deliver()
+
 				Expect(err).NotTo(HaveOccurred())
 
-				buffer := bytes.NewBuffer(nil)
-				buffer.WriteString("some-file-contents")
+				Expect(transport.DropCall.Receives.Uri).To(Equal("https://mirror.internal/some-entry.tgz"))
 
-				sum := sha256.Sum256(buffer.Bytes())
-				dependencySHA = hex.EncodeToString(sum[:])
+				files, err := filepath.Glob(fmt.Sprintf("%s/*", layerPath))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(files).To(ConsistOf([]string{
+					filepath.Join(layerPath, "first"),
+					filepath.Join(layerPath, "second"),
+					filepath.Join(layerPath, "third"),
+					filepath.Join(layerPath, "some-dir"),
+					filepath.Join(layerPath, "symlink"),
+				}))
+			})
+
+			context("when there is also a dependency mapping via binding", func() {
+				it.Before(func() {
+					mappingResolver.FindDependencyMappingCall.Returns.String = "https://public.example.com/dependency-mapping-entry.tgz"
+				})
+
+				it("rewrites the mapped uri", func() {
+					err := deliver()
+
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(transport.DropCall.Receives.Uri).To(Equal("https://mirror.internal/dependency-mapping-entry.tgz"))
+				})
+			})
+		})
 
-				transport.DropCall.Returns.ReadCloser = io.NopCloser(buffer)
+		context("when allowed hosts are set", func() {
+			var uri string
+
+			it.Before(func() {
+				uri = "https://approved.example.com/some-entry.tgz"
 
 				deliver = func() error {
-					return service.Deliver(postal.Dependency{
+					return service.WithAllowedHosts([]string{"approved.example.com", "mirror.internal"}).Deliver(postal.Dependency{
 						ID:      "some-entry",
 						Stacks:  []string{"some-stack"},
-						URI:     "https://dependencies.example.com/dependencies/some-file-name.txt",
+						URI:     uri,
 						SHA256:  dependencySHA,
 						Version: "1.2.3",
 					}, "some-cnb-path",
@@ -515,59 +1958,226 @@ version = "this is super not semver"
 				}
 			})
 
-			it.After(func() {
-				Expect(os.RemoveAll(layerPath)).To(Succeed())
-			})
-
-			it("downloads the dependency and copies it into the path with the given name", func() {
+			it("downloads a dependency from an allowed host", func() {
 				err := deliver()
 				Expect(err).NotTo(HaveOccurred())
 
-				Expect(transport.DropCall.Receives.Root).To(Equal("some-cnb-path"))
-				Expect(transport.DropCall.Receives.Uri).To(Equal("https://dependencies.example.com/dependencies/some-file-name.txt"))
+				Expect(transport.DropCall.Receives.Uri).To(Equal("https://approved.example.com/some-entry.tgz"))
+				Expect(filepath.Join(layerPath, "first")).To(BeARegularFile())
+			})
 
-				files, err := filepath.Glob(fmt.Sprintf("%s/*", layerPath))
-				Expect(err).NotTo(HaveOccurred())
-				Expect(files).To(ConsistOf([]string{filepath.Join(layerPath, "some-file-name.txt")}))
+			context("when the host differs only by case and port", func() {
+				it.Before(func() {
+					uri = "https://Approved.Example.com:8443/some-entry.tgz"
+				})
 
-				content, err := os.ReadFile(filepath.Join(layerPath, "some-file-name.txt"))
-				Expect(err).NotTo(HaveOccurred())
-				Expect(string(content)).To(Equal("some-file-contents"))
+				it("downloads the dependency", func() {
+					err := deliver()
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(transport.DropCall.CallCount).To(Equal(1))
+				})
+			})
+
+			context("when the uri has no host", func() {
+				it.Before(func() {
+					uri = "some-entry.tgz"
+				})
+
+				it("downloads the dependency", func() {
+					err := deliver()
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(transport.DropCall.Receives.Uri).To(Equal("some-entry.tgz"))
+				})
+			})
+
+			context("failure cases", func() {
+				context("when the dependency is hosted on a host that is not allowed", func() {
+					it.Before(func() {
+						uri = "https://untrusted.example.com/some-entry.tgz"
+					})
+
+					it("returns an error naming the host without fetching the dependency", func() {
+						err := deliver()
+						Expect(err).To(MatchError(`failed to fetch dependency: host "untrusted.example.com" is not allowed`))
+
+						Expect(transport.DropCall.CallCount).To(Equal(0))
+					})
+				})
+
+				context("when a dependency mapping redirects to a host that is not allowed", func() {
+					it.Before(func() {
+						mappingResolver.FindDependencyMappingCall.Returns.String = "http://untrusted.example.com/dependency-mapping-entry.tgz"
+					})
+
+					it("returns an error naming the host without fetching the dependency", func() {
+						err := deliver()
+						Expect(err).To(MatchError(`failed to fetch dependency: host "untrusted.example.com" is not allowed`))
+
+						Expect(transport.DropCall.CallCount).To(Equal(0))
+					})
+				})
+
+				context("when the checksum uri is on a host that is not allowed", func() {
+					it.Before(func() {
+						deliver = func() error {
+							return service.WithAllowedHosts([]string{"approved.example.com"}).Deliver(postal.Dependency{
+								ID:          "some-entry",
+								Stacks:      []string{"some-stack"},
+								URI:         uri,
+								ChecksumURI: "https://checksums.example.com/some-entry.tgz.sha256",
+								Version:     "1.2.3",
+							}, "some-cnb-path",
+								layerPath,
+								platformPath,
+							)
+						}
+					})
+
+					it("returns an error naming the host without fetching the checksum", func() {
+						err := deliver()
+						Expect(err).To(MatchError(`failed to fetch dependency: host "checksums.example.com" is not allowed`))
+
+						Expect(transport.DropCall.CallCount).To(Equal(0))
+					})
+				})
+
+				context("when the uri cannot be parsed", func() {
+					it.Before(func() {
+						uri = "https://approved.example.com/%zz"
+					})
+
+					it("returns an error", func() {
+						err := deliver()
+						Expect(err).To(MatchError(ContainSubstring("failed to parse dependency uri")))
+					})
+				})
 			})
 		})
 
-		context("when there is a dependency mapping via binding", func() {
+		context("when a deprecation policy is set", func() {
+			var (
+				logger     *bytes.Buffer
+				dependency postal.Dependency
+			)
+
 			it.Before(func() {
-				mappingResolver.FindDependencyMappingCall.Returns.String = "dependency-mapping-entry.tgz"
+				logger = bytes.NewBuffer(nil)
+				service = service.WithLogger(logger)
+
+				dependency = postal.Dependency{
+					ID:              "some-entry",
+					Stacks:          []string{"some-stack"},
+					URI:             "some-entry.tgz",
+					SHA256:          dependencySHA,
+					Version:         "1.2.3",
+					DeprecationDate: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+				}
 			})
 
-			it("looks up the dependency from the platform binding and downloads that instead", func() {
-				err := deliver()
+			context("when the policy is Ignore", func() {
+				it.Before(func() {
+					service = service.WithDeprecationPolicy(postal.Ignore)
+				})
 
-				Expect(err).NotTo(HaveOccurred())
+				it("delivers the deprecated dependency without a warning", func() {
+					err := service.Deliver(dependency, "some-cnb-path", layerPath, platformPath)
+					Expect(err).NotTo(HaveOccurred())
 
-				Expect(mappingResolver.FindDependencyMappingCall.Receives.SHA256).To(Equal(dependencySHA))
-				Expect(mappingResolver.FindDependencyMappingCall.Receives.BindingPath).To(Equal(filepath.Join(platformPath, "bindings")))
-				Expect(transport.DropCall.Receives.Root).To(Equal("some-cnb-path"))
-				Expect(transport.DropCall.Receives.Uri).To(Equal("dependency-mapping-entry.tgz"))
+					Expect(filepath.Join(layerPath, "first")).To(BeARegularFile())
+					Expect(logger.String()).To(BeEmpty())
+				})
+			})
 
-				files, err := filepath.Glob(fmt.Sprintf("%s/*", layerPath))
-				Expect(err).NotTo(HaveOccurred())
-				Expect(files).To(ConsistOf([]string{
-					filepath.Join(layerPath, "first"),
-					filepath.Join(layerPath, "second"),
-					filepath.Join(layerPath, "third"),
-					filepath.Join(layerPath, "some-dir"),
-					filepath.Join(layerPath, "symlink"),
-				}))
+			context("when the policy is WarnAfter", func() {
+				it.Before(func() {
+					service = service.WithDeprecationPolicy(postal.WarnAfter)
+				})
 
-				info, err := os.Stat(filepath.Join(layerPath, "first"))
-				Expect(err).NotTo(HaveOccurred())
-				Expect(info.Mode()).To(Equal(os.FileMode(0755)))
+				it("delivers the deprecated dependency and warns about it", func() {
+					err := service.Deliver(dependency, "some-cnb-path", layerPath, platformPath)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(filepath.Join(layerPath, "first")).To(BeARegularFile())
+					Expect(logger.String()).To(Equal("Warning: \"some-entry\" version 1.2.3 was deprecated on 2000-01-01\n"))
+				})
+
+				context("when the deprecation date is in the future", func() {
+					it.Before(func() {
+						dependency.DeprecationDate = time.Now().Add(24 * time.Hour)
+					})
+
+					it("delivers the dependency without a warning", func() {
+						err := service.Deliver(dependency, "some-cnb-path", layerPath, platformPath)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(filepath.Join(layerPath, "first")).To(BeARegularFile())
+						Expect(logger.String()).To(BeEmpty())
+					})
+				})
+			})
+
+			context("when the policy is FailAfter", func() {
+				it.Before(func() {
+					service = service.WithDeprecationPolicy(postal.FailAfter)
+				})
+
+				it("refuses to deliver the deprecated dependency", func() {
+					err := service.Deliver(dependency, "some-cnb-path", layerPath, platformPath)
+					Expect(err).To(MatchError(`failed to deliver dependency: "some-entry" version 1.2.3 was deprecated on 2000-01-01`))
+
+					Expect(transport.DropCall.CallCount).To(Equal(0))
+					Expect(filepath.Join(layerPath, "first")).NotTo(BeAnExistingFile())
+				})
+
+				context("when the deprecation date is in the future", func() {
+					it.Before(func() {
+						dependency.DeprecationDate = time.Now().Add(24 * time.Hour)
+					})
+
+					it("delivers the dependency", func() {
+						err := service.Deliver(dependency, "some-cnb-path", layerPath, platformPath)
+						Expect(err).NotTo(HaveOccurred())
+
+						Expect(filepath.Join(layerPath, "first")).To(BeARegularFile())
+					})
+				})
 			})
 		})
 
 		context("failure cases", func() {
+			context("when the dependency mapping bindings cannot be read", func() {
+				var mappingErr error
+
+				it.Before(func() {
+					mappingErr = errors.New("couldn't read binding type: permission denied")
+					mappingResolver.FindDependencyMappingCall.Returns.Error = mappingErr
+				})
+
+				it("returns an error that preserves the cause", func() {
+					err := deliver()
+
+					Expect(err).To(MatchError("failed to resolve dependency mapping: couldn't read binding type: permission denied"))
+					Expect(errors.Is(err, mappingErr)).To(BeTrue())
+				})
+			})
+
+			context("when a dependency mapping binding is malformed", func() {
+				it.Before(func() {
+					Expect(os.MkdirAll(filepath.Join(platformPath, "bindings", "some-binding"), os.ModePerm)).To(Succeed())
+
+					service = postal.NewService(transport)
+				})
+
+				it("returns an error that describes the binding", func() {
+					err := deliver()
+
+					Expect(err).To(MatchError(ContainSubstring("failed to resolve dependency mapping: couldn't read binding type:")))
+					Expect(errors.Is(err, os.ErrNotExist)).To(BeTrue())
+				})
+			})
+
 			context("when the transport cannot fetch a dependency", func() {
 				it.Before(func() {
 					transport.DropCall.Returns.Error = errors.New("there was an error")
@@ -577,6 +2187,11 @@ version = "this is super not semver"
 					err := deliver()
 
 					Expect(err).To(MatchError("failed to fetch dependency: there was an error"))
+
+					var fetchFailed postal.ErrFetchFailed
+					Expect(errors.As(err, &fetchFailed)).To(BeTrue())
+					Expect(fetchFailed.URI).To(Equal("some-entry.tgz"))
+					Expect(fetchFailed.Err).To(MatchError("there was an error"))
 				})
 			})
 
@@ -616,7 +2231,29 @@ version = "this is super not semver"
 				it("fails to create a tar reader", func() {
 					err := deliver()
 
-					Expect(err).To(MatchError(ContainSubstring("failed to read tar response")))
+					Expect(err).To(MatchError(ContainSubstring("failed to extract dependency: failed to read tar response")))
+					Expect(err).NotTo(MatchError(ContainSubstring("checksum does not match")))
+				})
+			})
+
+			context("when the download is corrupt partway through the archive", func() {
+				it.Before(func() {
+					content, err := io.ReadAll(transport.DropCall.Returns.ReadCloser)
+					Expect(err).NotTo(HaveOccurred())
+
+					transport.DropCall.Returns.ReadCloser = io.NopCloser(bytes.NewReader(content[:len(content)/2]))
+				})
+
+				it("reports the checksum failure rather than the extraction failure", func() {
+					err := deliver()
+
+					Expect(err).To(MatchError(ContainSubstring("checksum does not match: dependency download is corrupt")))
+
+					var checksumMismatch postal.ErrChecksumMismatch
+					Expect(errors.As(err, &checksumMismatch)).To(BeTrue())
+					Expect(checksumMismatch.ID).To(Equal("some-entry"))
+					Expect(checksumMismatch.SHA256).To(Equal(dependencySHA))
+					Expect(checksumMismatch.Err).To(HaveOccurred())
 				})
 			})
 
@@ -634,6 +2271,13 @@ version = "this is super not semver"
 					)
 
 					Expect(err).To(MatchError(ContainSubstring("checksum does not match")))
+					Expect(errors.Is(err, cargo.ChecksumValidationError)).To(BeTrue())
+
+					var checksumMismatch postal.ErrChecksumMismatch
+					Expect(errors.As(err, &checksumMismatch)).To(BeTrue())
+					Expect(checksumMismatch.ID).To(Equal("some-entry"))
+					Expect(checksumMismatch.Version).To(Equal("1.2.3"))
+					Expect(checksumMismatch.SHA256).To(Equal("this is not a valid checksum"))
 				})
 			})
 
@@ -704,6 +2348,95 @@ version = "this is super not semver"
 		})
 	})
 
+	context("ResolveAndDeliver", func() {
+		var (
+			layerPath    string
+			platformPath string
+		)
+
+		it.Before(func() {
+			var err error
+			layerPath, err = os.MkdirTemp("", "layer")
+			Expect(err).NotTo(HaveOccurred())
+
+			platformPath, err = os.MkdirTemp("", "platform")
+			Expect(err).NotTo(HaveOccurred())
+
+			buffer := bytes.NewBuffer(nil)
+			zw := gzip.NewWriter(buffer)
+			tw := tar.NewWriter(zw)
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "./some-file", Mode: 0755, Size: int64(len("some-content"))})).To(Succeed())
+			_, err = tw.Write([]byte("some-content"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tw.Close()).To(Succeed())
+			Expect(zw.Close()).To(Succeed())
+
+			sum := sha256.Sum256(buffer.Bytes())
+
+			err = os.WriteFile(path, []byte(fmt.Sprintf(`
+[[metadata.dependencies]]
+id = "some-entry"
+name = "Some Entry"
+sha256 = "some-other-sha"
+stacks = ["some-stack"]
+uri = "some-entry-1.2.3.tgz"
+version = "1.2.3"
+
+[[metadata.dependencies]]
+id = "some-entry"
+name = "Some Entry"
+sha256 = %q
+stacks = ["some-stack"]
+uri = "some-entry-4.5.6.tgz"
+version = "4.5.6"
+`, hex.EncodeToString(sum[:]))), 0600)
+			Expect(err).NotTo(HaveOccurred())
+
+			transport.DropCall.Returns.ReadCloser = io.NopCloser(buffer)
+		})
+
+		it.After(func() {
+			Expect(os.RemoveAll(layerPath)).To(Succeed())
+			Expect(os.RemoveAll(platformPath)).To(Succeed())
+		})
+
+		it("installs the resolved version and returns its metadata", func() {
+			dependency, err := service.ResolveAndDeliver(path, "some-entry", "4.*", "some-stack", "some-cnb-path", layerPath, platformPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dependency.ID).To(Equal("some-entry"))
+			Expect(dependency.Name).To(Equal("Some Entry"))
+			Expect(dependency.Version).To(Equal("4.5.6"))
+			Expect(dependency.URI).To(Equal("some-entry-4.5.6.tgz"))
+
+			Expect(transport.DropCall.Receives.Root).To(Equal("some-cnb-path"))
+			Expect(transport.DropCall.Receives.Uri).To(Equal("some-entry-4.5.6.tgz"))
+
+			content, err := os.ReadFile(filepath.Join(layerPath, "some-file"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("some-content"))
+		})
+
+		context("failure cases", func() {
+			context("when the dependency cannot be resolved", func() {
+				it("returns an error without delivering anything", func() {
+					_, err := service.ResolveAndDeliver(path, "some-entry", "9.*", "some-stack", "some-cnb-path", layerPath, platformPath)
+					Expect(err).To(MatchError(ContainSubstring("no compatible versions")))
+
+					Expect(transport.DropCall.CallCount).To(Equal(0))
+				})
+			})
+
+			context("when the dependency cannot be delivered", func() {
+				it("returns an error", func() {
+					_, err := service.ResolveAndDeliver(path, "some-entry", "1.2.3", "some-stack", "some-cnb-path", layerPath, platformPath)
+					Expect(err).To(MatchError(ContainSubstring("checksum does not match")))
+				})
+			})
+		})
+	})
+
 	context("Install", func() {
 		var (
 			dependencySHA string
@@ -820,6 +2553,45 @@ version = "this is super not semver"
 			})
 		})
 
+		context("when /platform/bindings does not exist", func() {
+			var platformPath string
+
+			it.Before(func() {
+				var err error
+				platformPath, err = os.MkdirTemp("", "platform")
+				Expect(err).NotTo(HaveOccurred())
+
+				mappingResolver.FindDependencyMappingCall.Returns.Error = errors.New("failed to read bindings")
+
+				install = func() error {
+					return service.InstallWithPlatformPath(postal.Dependency{
+						ID:      "some-entry",
+						Stacks:  []string{"some-stack"},
+						URI:     "some-entry.tgz",
+						SHA256:  dependencySHA,
+						Version: "1.2.3",
+					}, "some-cnb-path",
+						layerPath,
+						platformPath,
+					)
+				}
+			})
+
+			it.After(func() {
+				Expect(os.RemoveAll(platformPath)).To(Succeed())
+			})
+
+			it("ignores the failed dependency mapping lookup and installs the dependency", func() {
+				err := install()
+
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(mappingResolver.FindDependencyMappingCall.Receives.BindingPath).To(Equal(filepath.Join(platformPath, "bindings")))
+				Expect(transport.DropCall.Receives.Uri).To(Equal("some-entry.tgz"))
+				Expect(filepath.Join(layerPath, "first")).To(BeARegularFile())
+			})
+		})
+
 		context("failure cases", func() {
 			context("when the transport cannot fetch a dependency", func() {
 				it.Before(func() {
@@ -956,6 +2728,58 @@ version = "this is super not semver"
 		})
 	})
 
+	context("VerifyLayer", func() {
+		var (
+			layerPath string
+			expected  string
+		)
+
+		it.Before(func() {
+			var err error
+			layerPath, err = os.MkdirTemp("", "layer")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(os.MkdirAll(filepath.Join(layerPath, "bin"), os.ModePerm)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(layerPath, "bin", "some-binary"), []byte("some-binary-content"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(layerPath, "some-file"), []byte("some-content"), 0644)).To(Succeed())
+
+			expected, err = fs.NewChecksumCalculator().Sum(layerPath)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			Expect(os.RemoveAll(layerPath)).To(Succeed())
+		})
+
+		it("reports that an unchanged layer matches its checksum", func() {
+			ok, err := service.VerifyLayer(layerPath, expected)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+		})
+
+		context("when a file in the layer has been modified", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(layerPath, "bin", "some-binary"), []byte("corrupted-content"), 0755)).To(Succeed())
+			})
+
+			it("reports that the layer does not match its checksum", func() {
+				ok, err := service.VerifyLayer(layerPath, expected)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ok).To(BeFalse())
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the layer does not exist", func() {
+				it("returns an error", func() {
+					_, err := service.VerifyLayer(filepath.Join(layerPath, "no-such-layer"), expected)
+					Expect(err).To(MatchError(ContainSubstring("failed to verify layer:")))
+					Expect(err).To(MatchError(ContainSubstring("no such file or directory")))
+				})
+			})
+		})
+	})
+
 	context("GenerateBillOfMaterials", func() {
 		var deprecationDate time.Time
 
@@ -978,13 +2802,14 @@ version = "this is super not semver"
 					Version:         "1.2.3",
 				},
 				postal.Dependency{
-					ID:      "other-entry",
-					Name:    "Other Entry",
-					SHA256:  "other-sha",
-					Source:  "other-source",
-					Stacks:  []string{"other-stack"},
-					URI:     "other-uri",
-					Version: "4.5.6",
+					ID:       "other-entry",
+					Licenses: []postal.License{{Type: "MIT"}},
+					Name:     "Other Entry",
+					SHA256:   "other-sha",
+					Source:   "other-source",
+					Stacks:   []string{"other-stack"},
+					URI:      "other-uri",
+					Version:  "4.5.6",
 				},
 			)
 			Expect(entries).To(Equal([]packit.BOMEntry{
@@ -1001,10 +2826,11 @@ version = "this is super not semver"
 				{
 					Name: "Other Entry",
 					Metadata: map[string]interface{}{
-						"sha256":  "other-sha",
-						"stacks":  []string{"other-stack"},
-						"uri":     "other-uri",
-						"version": "4.5.6",
+						"licenses": []postal.License{{Type: "MIT"}},
+						"sha256":   "other-sha",
+						"stacks":   []string{"other-stack"},
+						"uri":      "other-uri",
+						"version":  "4.5.6",
 					},
 				},
 			}))
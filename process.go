@@ -19,4 +19,9 @@ type Process struct {
 
 	// Direct indicates whether the process should bypass the shell when invoked.
 	Direct bool `toml:"direct"`
+
+	// Default indicates whether the process should be the default process
+	// that is run at launch. This field is supported by Buildpack API 0.6 and
+	// above.
+	Default bool `toml:"default,omitempty"`
 }
@@ -1,6 +1,9 @@
 package scribe
 
 import (
+	"io"
+	"os"
+	"regexp"
 	"strconv"
 )
 
@@ -16,6 +19,10 @@ var (
 	GrayColor    = NewColor(false, 244, -1)
 )
 
+// colorCodes matches the escape codes written by the functions that NewColor
+// returns.
+var colorCodes = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
 type Color func(message string) string
 
 func NewColor(bold bool, fg, bg int) Color {
@@ -41,3 +48,25 @@ func NewColor(bold bool, fg, bg int) Color {
 		return prefix + message + suffix
 	}
 }
+
+// colorEnabled reports whether output written to the given writer is colored
+// by default. Color is only enabled when the writer is a terminal, so that
+// escape codes do not end up in CI logs or files, and the NO_COLOR environment
+// variable is unset or empty, as described at https://no-color.org.
+func colorEnabled(writer io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	file, ok := writer.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
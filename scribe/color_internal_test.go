@@ -0,0 +1,86 @@
+package scribe
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestUnitScribeInternal(t *testing.T) {
+	suite := spec.New("packit/scribe/internal", spec.Report(report.Terminal{}))
+	suite("colorEnabled", testColorEnabled)
+	suite.Run(t)
+}
+
+func testColorEnabled(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		device *os.File
+	)
+
+	it.Before(func() {
+		var err error
+		device, err = os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.Unsetenv("NO_COLOR")).To(Succeed())
+	})
+
+	it.After(func() {
+		Expect(device.Close()).To(Succeed())
+		Expect(os.Unsetenv("NO_COLOR")).To(Succeed())
+	})
+
+	it("enables color when the writer is a character device, such as a terminal", func() {
+		Expect(colorEnabled(device)).To(BeTrue())
+	})
+
+	it("disables color when the writer is not a file", func() {
+		Expect(colorEnabled(bytes.NewBuffer(nil))).To(BeFalse())
+	})
+
+	context("when the writer is a regular file", func() {
+		var file *os.File
+
+		it.Before(func() {
+			var err error
+			file, err = os.CreateTemp("", "output")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			Expect(file.Close()).To(Succeed())
+			Expect(os.Remove(file.Name())).To(Succeed())
+		})
+
+		it("disables color", func() {
+			Expect(colorEnabled(file)).To(BeFalse())
+		})
+	})
+
+	context("when NO_COLOR is set", func() {
+		it.Before(func() {
+			Expect(os.Setenv("NO_COLOR", "1")).To(Succeed())
+		})
+
+		it("disables color", func() {
+			Expect(colorEnabled(device)).To(BeFalse())
+		})
+	})
+
+	context("when NO_COLOR is set to an empty string", func() {
+		it.Before(func() {
+			Expect(os.Setenv("NO_COLOR", "")).To(Succeed())
+		})
+
+		it("enables color", func() {
+			Expect(colorEnabled(device)).To(BeTrue())
+		})
+	})
+}
@@ -17,36 +17,56 @@ type Emitter struct {
 	Logger
 }
 
+// boldColor emphasizes the heading printed by each of the Emitter methods.
+var boldColor = NewColor(true, -1, -1)
+
 func NewEmitter(output io.Writer) Emitter {
 	return Emitter{
 		Logger: NewLogger(output),
 	}
 }
 
+// WithColor returns a copy of the Emitter that colors its output when enabled
+// is true and never colors it otherwise. By default, output is only colored
+// when it is written to a terminal and the NO_COLOR environment variable is
+// not set.
+func (e Emitter) WithColor(enabled bool) Emitter {
+	e.Logger = e.Logger.WithColor(enabled)
+	return e
+}
+
+// SelectedDependency prints the name, version, and version source of the
+// dependency that was resolved for the given plan entry. The dependency id is
+// used when it has no name. A deprecation notice is included when the
+// dependency is deprecated or will be within 30 days of now.
 func (e Emitter) SelectedDependency(entry packit.BuildpackPlanEntry, dependency postal.Dependency, now time.Time) {
 	source, ok := entry.Metadata["version-source"].(string)
 	if !ok {
 		source = "<unknown>"
 	}
 
-	e.Subprocess("Selected %s version (using %s): %s", dependency.Name, source, dependency.Version)
+	if dependency.Name == "" {
+		dependency.Name = dependency.ID
+	}
+
+	e.Subprocess("Selected %s version (using %s): %s", dependency.Name, source, e.colorize(GreenColor, dependency.Version))
 
 	if (dependency.DeprecationDate != time.Time{}) {
 		deprecationDate := dependency.DeprecationDate
 		switch {
 		case (deprecationDate.Add(-30*24*time.Hour).Before(now) && deprecationDate.After(now)):
-			e.Action("Version %s of %s will be deprecated after %s.", dependency.Version, dependency.Name, dependency.DeprecationDate.Format("2006-01-02"))
-			e.Action("Migrate your application to a supported version of %s before this time.", dependency.Name)
+			e.Action("%s", e.colorize(YellowColor, fmt.Sprintf("Version %s of %s will be deprecated after %s.", dependency.Version, dependency.Name, dependency.DeprecationDate.Format("2006-01-02"))))
+			e.Action("%s", e.colorize(YellowColor, fmt.Sprintf("Migrate your application to a supported version of %s before this time.", dependency.Name)))
 		case (deprecationDate == now || deprecationDate.Before(now)):
-			e.Action("Version %s of %s is deprecated.", dependency.Version, dependency.Name)
-			e.Action("Migrate your application to a supported version of %s.", dependency.Name)
+			e.Action("%s", e.colorize(YellowColor, fmt.Sprintf("Version %s of %s is deprecated.", dependency.Version, dependency.Name)))
+			e.Action("%s", e.colorize(YellowColor, fmt.Sprintf("Migrate your application to a supported version of %s.", dependency.Name)))
 		}
 	}
 	e.Break()
 }
 
 func (e Emitter) Candidates(entries []packit.BuildpackPlanEntry) {
-	e.Subprocess("Candidate version sources (in priority order):")
+	e.Subprocess("%s", e.colorize(boldColor, "Candidate version sources (in priority order):"))
 
 	var (
 		sources [][2]string
@@ -87,7 +107,7 @@ Entries:
 }
 
 func (e Emitter) LaunchProcesses(processes []packit.Process, processEnvs ...map[string]packit.Environment) {
-	e.Process("Assigning launch processes:")
+	e.Process("%s", e.colorize(boldColor, "Assigning launch processes:"))
 
 	for _, process := range processes {
 		p := fmt.Sprintf("%s: %s", process.Type, process.Command)
@@ -117,6 +137,95 @@ func (e Emitter) LaunchProcesses(processes []packit.Process, processEnvs ...map[
 	e.Break()
 }
 
+// Processes prints a table listing the type, command and arguments, and direct
+// flag of each of the given processes. The default process is marked with
+// "(default)".
+func (e Emitter) Processes(processes []packit.Process) {
+	e.Process("%s", e.colorize(boldColor, "Launch processes:"))
+
+	var (
+		rows                [][3]string
+		typeLen, commandLen = len("TYPE"), len("COMMAND")
+	)
+
+	for _, process := range processes {
+		command := strings.Join(append([]string{process.Command}, process.Args...), " ")
+
+		if len(process.Type) > typeLen {
+			typeLen = len(process.Type)
+		}
+
+		if len(command) > commandLen {
+			commandLen = len(command)
+		}
+
+		direct := strconv.FormatBool(process.Direct)
+		if process.Default {
+			direct = fmt.Sprintf("%s (default)", direct)
+		}
+
+		rows = append(rows, [3]string{process.Type, command, direct})
+	}
+
+	format := "%-" + strconv.Itoa(typeLen) + "s  %-" + strconv.Itoa(commandLen) + "s  %s"
+
+	e.Subprocess("%s", e.colorize(GrayColor, fmt.Sprintf(format, "TYPE", "COMMAND", "DIRECT")))
+	for _, row := range rows {
+		e.Subprocess(format, row[0], row[1], row[2])
+	}
+
+	e.Break()
+}
+
+// DependencyResolution prints a table listing the id, version, and status of
+// each of the candidates that were considered when resolving a dependency.
+// The status is "selected" for the version that was resolved, and otherwise
+// indicates whether the version satisfies the constraint. Emitter implements
+// postal.ResolutionLogger so that it can be given to
+// postal.Service.WithResolutionLog.
+func (e Emitter) DependencyResolution(resolution postal.Resolution) {
+	id := resolution.ID
+	if resolution.IDs != nil {
+		id = strings.Join(resolution.IDs, ", ")
+	}
+
+	e.Process("%s", e.colorize(boldColor, fmt.Sprintf("Resolving %s (using constraint %s):", id, resolution.Constraint)))
+
+	var (
+		rows              [][3]string
+		idLen, versionLen = len("ID"), len("VERSION")
+	)
+
+	for _, candidate := range resolution.Candidates {
+		if len(candidate.ID) > idLen {
+			idLen = len(candidate.ID)
+		}
+
+		if len(candidate.Version) > versionLen {
+			versionLen = len(candidate.Version)
+		}
+
+		status := "incompatible"
+		switch {
+		case candidate.Selected:
+			status = e.colorize(GreenColor, "selected")
+		case candidate.Compatible:
+			status = "compatible"
+		}
+
+		rows = append(rows, [3]string{candidate.ID, candidate.Version, status})
+	}
+
+	format := "%-" + strconv.Itoa(idLen) + "s  %-" + strconv.Itoa(versionLen) + "s  %s"
+
+	e.Subprocess("%s", e.colorize(GrayColor, fmt.Sprintf(format, "ID", "VERSION", "STATUS")))
+	for _, row := range rows {
+		e.Subprocess(format, row[0], row[1], row[2])
+	}
+
+	e.Break()
+}
+
 func (e Emitter) EnvironmentVariables(layer packit.Layer) {
 	buildEnv := packit.Environment{}
 	launchEnv := packit.Environment{}
@@ -138,13 +247,13 @@ func (e Emitter) EnvironmentVariables(layer packit.Layer) {
 	}
 
 	if len(buildEnv) != 0 {
-		e.Process("Configuring build environment")
+		e.Process("%s", e.colorize(boldColor, "Configuring build environment"))
 		e.Subprocess("%s", NewFormattedMapFromEnvironment(buildEnv))
 		e.Break()
 	}
 
 	if len(launchEnv) != 0 {
-		e.Process("Configuring launch environment")
+		e.Process("%s", e.colorize(boldColor, "Configuring launch environment"))
 		e.Subprocess("%s", NewFormattedMapFromEnvironment(launchEnv))
 		e.Break()
 	}
@@ -2,6 +2,7 @@ package scribe_test
 
 import (
 	"bytes"
+	"os"
 	"testing"
 	"time"
 
@@ -66,6 +67,16 @@ func testEmitter(t *testing.T, context spec.G, it spec.S) {
 			})
 		})
 
+		context("when the dependency has no name", func() {
+			it("prints the dependency id instead", func() {
+				emitter.SelectedDependency(entry, postal.Dependency{ID: "some-dependency", Version: "some-version"}, now)
+				Expect(buffer.String()).To(ContainLines(
+					"    Selected some-dependency version (using some-source): some-version",
+					"",
+				))
+			})
+		})
+
 		context("when it is within 30 days of the deprecation date", func() {
 			it.Before(func() {
 				deprecationDate, err := time.Parse(time.RFC3339, "2021-04-01T00:00:00Z")
@@ -291,6 +302,78 @@ func testEmitter(t *testing.T, context spec.G, it spec.S) {
 		})
 	})
 
+	context("Processes", func() {
+		it("prints a table of the launch processes", func() {
+			emitter.Processes([]packit.Process{
+				{
+					Type:    "web",
+					Command: "some-command",
+					Args:    []string{"--some-arg", "some-value"},
+					Default: true,
+				},
+				{
+					Type:    "worker",
+					Command: "other-command",
+					Direct:  true,
+				},
+				{
+					Type:    "some-other-type",
+					Command: "another-command",
+				},
+			})
+
+			Expect(buffer.String()).To(Equal(`  Launch processes:
+    TYPE             COMMAND                             DIRECT
+    web              some-command --some-arg some-value  false (default)
+    worker           other-command                       true
+    some-other-type  another-command                     false
+
+`))
+		})
+	})
+
+	context("DependencyResolution", func() {
+		it("prints a table of the candidates and marks the selected version", func() {
+			emitter.DependencyResolution(postal.Resolution{
+				ID:         "some-entry",
+				Constraint: "1.2.*",
+				Candidates: []postal.ResolutionCandidate{
+					{ID: "some-entry", Version: "10.0.0"},
+					{ID: "some-entry", Version: "1.2.4", Compatible: true, Selected: true},
+					{ID: "some-entry", Version: "1.2.3", Compatible: true},
+				},
+			})
+
+			Expect(buffer.String()).To(Equal(`  Resolving some-entry (using constraint 1.2.*):
+    ID          VERSION  STATUS
+    some-entry  10.0.0   incompatible
+    some-entry  1.2.4    selected
+    some-entry  1.2.3    compatible
+
+`))
+		})
+
+		context("when the resolution considered several ids", func() {
+			it("lists every id in the heading", func() {
+				emitter.DependencyResolution(postal.Resolution{
+					IDs:        []string{"some-entry", "other-entry"},
+					Constraint: "*",
+					Candidates: []postal.ResolutionCandidate{
+						{ID: "other-entry", Version: "2.0.0", Compatible: true, Selected: true},
+						{ID: "some-entry", Version: "1.2.3", Compatible: true},
+					},
+				})
+
+				Expect(buffer.String()).To(Equal(`  Resolving some-entry, other-entry (using constraint *):
+    ID           VERSION  STATUS
+    other-entry  2.0.0    selected
+    some-entry   1.2.3    compatible
+
+`))
+			})
+		})
+	})
+
 	context("EnvironmentVariables", func() {
 		it("prints a list of environment variables available during launch and build", func() {
 			emitter.EnvironmentVariables(packit.Layer{
@@ -367,4 +450,55 @@ func testEmitter(t *testing.T, context spec.G, it spec.S) {
 			})
 		})
 	})
+	context("WithColor", func() {
+		it.Before(func() {
+			Expect(os.Setenv("NO_COLOR", "1")).To(Succeed())
+		})
+
+		it.After(func() {
+			Expect(os.Unsetenv("NO_COLOR")).To(Succeed())
+		})
+
+		it("does not color the output by default", func() {
+			emitter.SelectedDependency(packit.BuildpackPlanEntry{}, postal.Dependency{Name: "Some Dependency", Version: "some-version"}, time.Now())
+			Expect(buffer.String()).NotTo(ContainSubstring("\x1b["))
+		})
+
+		context("when color is forced", func() {
+			it.Before(func() {
+				emitter = scribe.NewEmitter(buffer).WithColor(true)
+			})
+
+			it("colors the output even when NO_COLOR is set", func() {
+				now := time.Now()
+				emitter.SelectedDependency(packit.BuildpackPlanEntry{}, postal.Dependency{
+					Name:            "Some Dependency",
+					Version:         "some-version",
+					DeprecationDate: now.Add(-24 * time.Hour),
+				}, now)
+				emitter.Processes([]packit.Process{{Type: "web", Command: "some-command"}})
+
+				Expect(buffer.String()).To(ContainLines(
+					"    Selected Some Dependency version (using <unknown>): \x1b[0;38;5;2msome-version\x1b[0m",
+					"      \x1b[0;38;5;3mVersion some-version of Some Dependency is deprecated.\x1b[0m",
+					"      \x1b[0;38;5;3mMigrate your application to a supported version of Some Dependency.\x1b[0m",
+					"",
+					"  \x1b[1mLaunch processes:\x1b[0m",
+					"    \x1b[0;38;5;244mTYPE  COMMAND       DIRECT\x1b[0m",
+					"    web   some-command  false",
+				))
+			})
+		})
+
+		context("when color is disabled", func() {
+			it.Before(func() {
+				emitter = scribe.NewEmitter(buffer).WithColor(false)
+			})
+
+			it("does not color the output", func() {
+				emitter.Processes([]packit.Process{{Type: "web", Command: "some-command"}})
+				Expect(buffer.String()).NotTo(ContainSubstring("\x1b["))
+			})
+		})
+	})
 }
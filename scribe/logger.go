@@ -3,7 +3,11 @@ package scribe
 import (
 	"fmt"
 	"io"
+	"os"
 	"strings"
+	"time"
+
+	"github.com/paketo-buildpacks/packit/chronos"
 )
 
 type Logger struct {
@@ -13,9 +17,73 @@ type Logger struct {
 	action     io.Writer
 	detail     io.Writer
 	subdetail  io.Writer
+	clock      chronos.Clock
+	color      bool
+	files      []*os.File
+}
+
+// LoggerOption declares a function signature that can be used to define
+// optional modifications to the writer that a Logger created by NewLogger
+// writes to.
+type LoggerOption func(writer io.Writer) io.Writer
+
+// WithFileTee is a LoggerOption that writes everything the Logger prints to
+// the file at the given path as well as to the console writer. The file is
+// created if it does not exist and appended to if it does, and each line is
+// written to it as soon as it is printed. If the file cannot be opened, a
+// warning is printed to the console writer and the Logger writes only to the
+// console. Color codes are removed from the output written to the file, so
+// that it stays readable when the Logger colors the console output. The file
+// stays open until the Close method of the Logger is called.
+func WithFileTee(path string) LoggerOption {
+	return func(writer io.Writer) io.Writer {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			fmt.Fprintf(writer, "Warning: failed to open log file: %s\n", err)
+			return writer
+		}
+
+		return fileTee{Writer: io.MultiWriter(writer, uncoloredWriter{file}), file: file}
+	}
+}
+
+// fileTee is the writer returned by WithFileTee. It keeps a handle on the file
+// so that NewLogger can give the Logger responsibility for closing it.
+type fileTee struct {
+	io.Writer
+	file *os.File
+}
+
+// uncoloredWriter removes color codes from everything written to it before
+// passing it on. The Writers of a Logger write every color code in full, so a
+// code is never split across two writes.
+type uncoloredWriter struct {
+	writer io.Writer
+}
+
+func (w uncoloredWriter) Write(b []byte) (int, error) {
+	_, err := w.writer.Write(colorCodes.ReplaceAll(b, nil))
+	if err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
 }
 
-func NewLogger(writer io.Writer) Logger {
+func NewLogger(writer io.Writer, options ...LoggerOption) Logger {
+	// Whether to color the output is decided by the console writer, regardless
+	// of any files that the options tee the output to.
+	color := colorEnabled(writer)
+
+	var files []*os.File
+	for _, option := range options {
+		writer = option(writer)
+
+		if tee, ok := writer.(fileTee); ok {
+			files = append(files, tee.file)
+		}
+	}
+
 	return Logger{
 		title:      NewWriter(writer),
 		process:    NewWriter(writer, WithIndent(1)),
@@ -23,9 +91,43 @@ func NewLogger(writer io.Writer) Logger {
 		action:     NewWriter(writer, WithIndent(3)),
 		detail:     NewWriter(writer, WithIndent(4)),
 		subdetail:  NewWriter(writer, WithIndent(5)),
+		clock:      chronos.DefaultClock,
+		color:      color,
+		files:      files,
 	}
 }
 
+// Close closes the files opened by any WithFileTee options given to
+// NewLogger. The Logger must not be used to print once it has been closed.
+// Close does nothing for a Logger that has no files.
+func (l Logger) Close() error {
+	var closeErr error
+	for _, file := range l.files {
+		err := file.Close()
+		if err != nil && closeErr == nil {
+			closeErr = fmt.Errorf("failed to close log file: %w", err)
+		}
+	}
+
+	return closeErr
+}
+
+// WithClock returns a copy of the Logger that uses the given clock to measure
+// the durations reported by Timed.
+func (l Logger) WithClock(clock chronos.Clock) Logger {
+	l.clock = clock
+	return l
+}
+
+// WithColor returns a copy of the Logger that colors the output of the
+// Emitter methods when enabled is true and never colors it otherwise. By
+// default, output is only colored when it is written to a terminal and the
+// NO_COLOR environment variable is not set.
+func (l Logger) WithColor(enabled bool) Logger {
+	l.color = enabled
+	return l
+}
+
 func (l Logger) Title(format string, v ...interface{}) {
 	l.printf(l.title, format, v...)
 }
@@ -54,9 +156,40 @@ func (l Logger) Break() {
 	l.printf(l.title, "\n")
 }
 
+// Timed prints the given action as a process, runs fn, and then reports how
+// long fn took to run, rounded to the millisecond, followed by a break. If fn
+// returns an error, the duration is reported as a failure and the error is
+// returned.
+func (l Logger) Timed(action string, fn func() error) error {
+	l.Process(action)
+
+	duration, err := l.clock.Measure(fn)
+	duration = duration.Round(time.Millisecond)
+	if err != nil {
+		l.Action("Failed after %s", duration)
+		l.Break()
+		return err
+	}
+
+	l.Action("Completed in %s", duration)
+	l.Break()
+
+	return nil
+}
+
 func (l Logger) printf(writer io.Writer, format string, v ...interface{}) {
 	if !strings.HasSuffix(format, "\n") {
 		format = format + "\n"
 	}
 	fmt.Fprintf(writer, format, v...)
 }
+
+// colorize wraps the message in the given color, or returns it unchanged if
+// color is disabled for the Logger.
+func (l Logger) colorize(color Color, message string) string {
+	if !l.color {
+		return message
+	}
+
+	return color(message)
+}
@@ -2,8 +2,15 @@ package scribe_test
 
 import (
 	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/paketo-buildpacks/packit"
+	"github.com/paketo-buildpacks/packit/chronos"
 	"github.com/paketo-buildpacks/packit/scribe"
 	"github.com/sclevine/spec"
 
@@ -70,5 +77,172 @@ func testLogger(t *testing.T, context spec.G, it spec.S) {
 			logger.Break()
 			Expect(buffer.String()).To(Equal("\n"))
 		})
+
+		it("separates phases with a title that resets the indentation", func() {
+			logger.Title("Resolving")
+			logger.Process("some-process")
+			logger.Detail("some-detail")
+			logger.Break()
+			logger.Title("Installing")
+			logger.Process("other-process")
+
+			Expect(buffer.String()).To(Equal(strings.Join([]string{
+				"Resolving",
+				"  some-process",
+				"        some-detail",
+				"",
+				"Installing",
+				"  other-process",
+				"",
+			}, "\n")))
+		})
+	})
+
+	context("Timed", func() {
+		var calls int
+
+		it.Before(func() {
+			calls = 0
+			now := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+			logger = logger.WithClock(chronos.NewClock(func() time.Time {
+				now = now.Add(1234567 * time.Microsecond)
+				return now
+			}))
+		})
+
+		it("runs the function and prints how long it took", func() {
+			err := logger.Timed("Executing build process", func() error {
+				calls++
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(calls).To(Equal(1))
+
+			Expect(buffer.String()).To(Equal(strings.Join([]string{
+				"  Executing build process",
+				"      Completed in 1.235s",
+				"",
+				"",
+			}, "\n")))
+		})
+
+		context("when the function fails", func() {
+			it("prints how long it ran and returns the error", func() {
+				err := logger.Timed("Executing build process", func() error {
+					return errors.New("failed to build")
+				})
+				Expect(err).To(MatchError("failed to build"))
+
+				Expect(buffer.String()).To(Equal(strings.Join([]string{
+					"  Executing build process",
+					"      Failed after 1.235s",
+					"",
+					"",
+				}, "\n")))
+			})
+		})
+	})
+
+	context("WithFileTee", func() {
+		var tempDir string
+
+		it.Before(func() {
+			var err error
+			tempDir, err = os.MkdirTemp("", "logs")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		it.After(func() {
+			Expect(os.RemoveAll(tempDir)).To(Succeed())
+		})
+
+		it("writes the same output to the console and the file", func() {
+			path := filepath.Join(tempDir, "build.log")
+			logger = scribe.NewLogger(buffer, scribe.WithFileTee(path))
+
+			logger.Title("some-%s", "title")
+			logger.Process("some-process")
+
+			content, err := os.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("some-title\n  some-process\n"))
+
+			logger.Action("some-action")
+
+			content, err = os.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("some-title\n  some-process\n      some-action\n"))
+			Expect(string(content)).To(Equal(buffer.String()))
+		})
+
+		it("closes the file when the logger is closed", func() {
+			path := filepath.Join(tempDir, "build.log")
+			logger = scribe.NewLogger(buffer, scribe.WithFileTee(path))
+			logger.Title("some-title")
+
+			Expect(logger.Close()).To(Succeed())
+
+			err := logger.Close()
+			Expect(err).To(MatchError(ContainSubstring("failed to close log file:")))
+			Expect(errors.Is(err, os.ErrClosed)).To(BeTrue())
+
+			content, err := os.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("some-title\n"))
+		})
+
+		it("writes the output to the file without color codes", func() {
+			path := filepath.Join(tempDir, "build.log")
+			emitter := scribe.Emitter{Logger: scribe.NewLogger(buffer, scribe.WithFileTee(path))}.WithColor(true)
+
+			emitter.Processes([]packit.Process{{Type: "web", Command: "some-command"}})
+			Expect(emitter.Close()).To(Succeed())
+
+			Expect(buffer.String()).To(ContainSubstring("\x1b[1mLaunch processes:\x1b[0m"))
+
+			content, err := os.ReadFile(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal(`  Launch processes:
+    TYPE  COMMAND       DIRECT
+    web   some-command  false
+
+`))
+		})
+
+		it("does nothing when closing a logger without a file", func() {
+			Expect(scribe.NewLogger(buffer).Close()).To(Succeed())
+		})
+
+		context("when the file already exists", func() {
+			it.Before(func() {
+				Expect(os.WriteFile(filepath.Join(tempDir, "build.log"), []byte("previous-output\n"), 0644)).To(Succeed())
+			})
+
+			it("appends to the file", func() {
+				logger = scribe.NewLogger(buffer, scribe.WithFileTee(filepath.Join(tempDir, "build.log")))
+				logger.Title("some-title")
+
+				content, err := os.ReadFile(filepath.Join(tempDir, "build.log"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(Equal("previous-output\nsome-title\n"))
+			})
+		})
+
+		context("failure cases", func() {
+			context("when the file cannot be opened", func() {
+				it("warns on the console and continues writing to it", func() {
+					path := filepath.Join(tempDir, "missing", "build.log")
+					logger = scribe.NewLogger(buffer, scribe.WithFileTee(path))
+
+					Expect(buffer.String()).To(ContainSubstring("Warning: failed to open log file:"))
+					Expect(buffer.String()).To(ContainSubstring(path))
+
+					buffer.Reset()
+					logger.Title("some-title")
+					Expect(buffer.String()).To(Equal("some-title\n"))
+					Expect(path).NotTo(BeAnExistingFile())
+				})
+			})
+		})
 	})
 }
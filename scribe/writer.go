@@ -3,6 +3,7 @@ package scribe
 import (
 	"bytes"
 	"io"
+	"sync"
 )
 
 type Option func(Writer) Writer
@@ -21,10 +22,30 @@ func WithIndent(indent int) Option {
 	}
 }
 
+// WithPrefix prepends the given prefix to every line written, after any
+// indentation. This is useful to distinguish the output of a subprocess from
+// the messages of the buildpack itself.
+func WithPrefix(prefix string) Option {
+	return func(l Writer) Writer {
+		l.prefix = []byte(prefix)
+		return l
+	}
+}
+
 type Writer struct {
 	writer io.Writer
 	color  Color
 	indent int
+	prefix []byte
+	state  *writerState
+}
+
+// writerState tracks whether the next byte written will start a new line so
+// that a line split across several writes is only prefixed once. Only writers
+// with a prefix track it; every write to other writers starts a new line.
+type writerState struct {
+	sync.Mutex
+	atLineStart bool
 }
 
 func NewWriter(writer io.Writer, options ...Option) Writer {
@@ -33,6 +54,10 @@ func NewWriter(writer io.Writer, options ...Option) Writer {
 		w = option(w)
 	}
 
+	if w.prefix != nil {
+		w.state = &writerState{atLineStart: true}
+	}
+
 	return w
 }
 
@@ -54,12 +79,25 @@ func (w Writer) Write(b []byte) (int, error) {
 		suffix = newline
 	}
 
+	atLineStart := true
+	if w.state != nil {
+		w.state.Lock()
+		defer w.state.Unlock()
+
+		atLineStart = w.state.atLineStart || prefix != nil
+		w.state.atLineStart = suffix != nil
+	}
+
 	lines := bytes.Split(b, newline)
 
 	var indentedLines [][]byte
-	for _, line := range lines {
-		for i := 0; i < w.indent; i++ {
-			line = append([]byte("  "), line...)
+	for i, line := range lines {
+		if i > 0 || atLineStart {
+			line = append(append([]byte(nil), w.prefix...), line...)
+
+			for i := 0; i < w.indent; i++ {
+				line = append([]byte("  "), line...)
+			}
 		}
 		indentedLines = append(indentedLines, line)
 	}
@@ -55,6 +55,59 @@ func testWriter(t *testing.T, context spec.G, it spec.S) {
 				})
 			})
 
+			context("when the writer has a prefix", func() {
+				it.Before(func() {
+					writer = scribe.NewWriter(buffer, scribe.WithIndent(2), scribe.WithPrefix("| "))
+				})
+
+				it("prints to the writer with the prefix on every line", func() {
+					_, err := writer.Write([]byte("some-text\nother-text\n"))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(buffer.String()).To(Equal("    | some-text\n    | other-text\n"))
+				})
+			})
+
+			context("when a line is split across multiple writes", func() {
+				it.Before(func() {
+					writer = scribe.NewWriter(buffer, scribe.WithIndent(2), scribe.WithPrefix("| "))
+				})
+
+				it("prefixes each line only once", func() {
+					for _, chunk := range []string{"some-", "text\nother", "-text", "\n", "last-text\n"} {
+						_, err := writer.Write([]byte(chunk))
+						Expect(err).NotTo(HaveOccurred())
+					}
+
+					Expect(buffer.String()).To(Equal("    | some-text\n    | other-text\n    | last-text\n"))
+				})
+
+				context("when the line is rewritten with a return prefix", func() {
+					it("prefixes the rewritten line", func() {
+						for _, chunk := range []string{"some-text", "\rother-text\n"} {
+							_, err := writer.Write([]byte(chunk))
+							Expect(err).NotTo(HaveOccurred())
+						}
+
+						Expect(buffer.String()).To(Equal("    | some-text\r    | other-text\n"))
+					})
+				})
+			})
+
+			context("when a line is split across multiple writes without a prefix", func() {
+				it.Before(func() {
+					writer = scribe.NewWriter(buffer, scribe.WithIndent(2))
+				})
+
+				it("indents every write", func() {
+					for _, chunk := range []string{"some-", "text\n"} {
+						_, err := writer.Write([]byte(chunk))
+						Expect(err).NotTo(HaveOccurred())
+					}
+
+					Expect(buffer.String()).To(Equal("    some-    text\n"))
+				})
+			})
+
 			context("when the writer has a return prefix", func() {
 				it.Before(func() {
 					writer = scribe.NewWriter(buffer, scribe.WithColor(scribe.RedColor), scribe.WithIndent(2))
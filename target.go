@@ -0,0 +1,49 @@
+package packit
+
+import "os"
+
+// Target contains the details of the platform the lifecycle selected for the
+// build, as described by the $CNB_TARGET_* environment variables in the
+// specification:
+// https://github.com/buildpacks/spec/blob/main/buildpack.md#provided-by-the-platform
+type Target struct {
+	// OS is the operating system of the target, such as "linux". This value is
+	// populated from the $CNB_TARGET_OS environment variable.
+	OS string
+
+	// Arch is the CPU architecture of the target, such as "amd64" or "arm64".
+	// This value is populated from the $CNB_TARGET_ARCH environment variable.
+	Arch string
+
+	// ArchVariant is the variant of the CPU architecture of the target, such as
+	// "v8". This value is populated from the $CNB_TARGET_ARCH_VARIANT
+	// environment variable.
+	ArchVariant string
+
+	// Distro describes the operating system distribution of the target.
+	Distro TargetDistro
+}
+
+// TargetDistro contains the details of the operating system distribution of a
+// Target.
+type TargetDistro struct {
+	// Name is the name of the distribution, such as "ubuntu". This value is
+	// populated from the $CNB_TARGET_DISTRO_NAME environment variable.
+	Name string
+
+	// Version is the version of the distribution, such as "22.04". This value
+	// is populated from the $CNB_TARGET_DISTRO_VERSION environment variable.
+	Version string
+}
+
+func targetFromEnvironment() Target {
+	return Target{
+		OS:          os.Getenv("CNB_TARGET_OS"),
+		Arch:        os.Getenv("CNB_TARGET_ARCH"),
+		ArchVariant: os.Getenv("CNB_TARGET_ARCH_VARIANT"),
+		Distro: TargetDistro{
+			Name:    os.Getenv("CNB_TARGET_DISTRO_NAME"),
+			Version: os.Getenv("CNB_TARGET_DISTRO_VERSION"),
+		},
+	}
+}
@@ -2,77 +2,191 @@ package vacation
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
 
-	"github.com/gabriel-vasile/mimetype"
+	"github.com/ulikunitz/xz"
 )
 
 type Decompressor interface {
 	Decompress(destination string) error
 }
 
-// An Archive decompresses tar, gzip, xz, and bzip2 compressed tar, and zip files from
-// an input stream.
+type archive interface {
+	Decompressor
+	List() ([]Entry, error)
+	Validate() error
+}
+
+// An Archive decompresses tar, gzip, xz, bzip2, and lz4 compressed tar, zip,
+// and single xz compressed files from an input stream.
 type Archive struct {
 	reader     io.Reader
+	path       string
 	components int
 	name       string
+	archiveOptions
 }
 
 // NewArchive returns a new Archive that reads from inputReader.
 func NewArchive(inputReader io.Reader) Archive {
 	return Archive{
-		reader: inputReader,
-		name:   "artifact",
+		reader:         inputReader,
+		name:           "artifact",
+		archiveOptions: archiveOptions{mode: os.ModePerm},
 	}
 }
 
+// NewArchiveFromFile returns a new Archive that reads from the file at the
+// given path. The file is opened each time the archive is read and is closed
+// once reading completes.
+func NewArchiveFromFile(path string) Archive {
+	a := NewArchive(nil)
+	a.path = path
+	return a
+}
+
 // Decompress reads from Archive, determines the archive type of the input
 // stream, and writes files into the destination specified.
 //
 // Archive decompression will also handle files that are types "text/plain;
 // charset=utf-8" and write the contents of the input stream to a file name
 // specified by the `Archive.WithName()` option (or defaults to "artifact")
-// in the destination directory.
+// in the destination directory. The same naming applies to xz compressed
+// files that do not contain a tar archive.
 func (a Archive) Decompress(destination string) error {
-	// Convert reader into a buffered read so that the header can be peeked to
-	// determine the type.
-	bufferedReader := bufio.NewReader(a.reader)
-
-	// The number 3072 is lifted from the mimetype library and the definition of
-	// the constant at the time of writing this functionality is listed below.
-	// https://github.com/gabriel-vasile/mimetype/blob/c64c025a7c2d8d45ba57d3cebb50a1dbedb3ed7e/internal/matchers/matchers.go#L6
-	header, err := bufferedReader.Peek(3072)
-	if err != nil && err != io.EOF {
+	source, closeSource, err := openSource(a.reader, a.path)
+	if err != nil {
+		return err
+	}
+	defer closeSource()
+	a.reader = source
+
+	decompressor, single, err := a.detect()
+	if err != nil {
 		return err
 	}
 
-	mime := mimetype.Detect(header)
+	target := destination
+	if single {
+		target = filepath.Join(destination, a.name)
+	}
+
+	err = decompressor.Decompress(target)
+	if err != nil {
+		return err
+	}
+
+	return chmodRoot(destination, a.rootMode)
+}
+
+// List reads from Archive, determines the archive type of the input stream,
+// and returns the entries it contains without writing anything to disk. Input
+// streams that are a single file are reported as one entry using the name
+// specified by the `Archive.WithName()` option.
+func (a Archive) List() ([]Entry, error) {
+	source, closeSource, err := openSource(a.reader, a.path)
+	if err != nil {
+		return nil, err
+	}
+	defer closeSource()
+	a.reader = source
+
+	lister, single, err := a.detect()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := lister.List()
+	if err != nil {
+		return nil, err
+	}
+
+	if single {
+		for i := range entries {
+			entries[i].Name = a.name
+		}
+	}
+
+	return entries, nil
+}
+
+// detect determines the archive type of the input stream and returns the
+// archive that handles it, along with whether the stream is a single file
+// rather than a collection of files.
+func (a Archive) detect() (archive, bool, error) {
+	archiveType, mime, bufferedReader, err := sniff(a.reader)
+	if err != nil {
+		return nil, false, err
+	}
 
 	// This switch case is reponsible for determining what the decompression
 	// strategy should be.
-	var decompressor Decompressor
-	switch mime.String() {
-	case "application/x-tar":
-		decompressor = NewTarArchive(bufferedReader).StripComponents(a.components)
-	case "application/gzip":
-		decompressor = NewTarGzipArchive(bufferedReader).StripComponents(a.components)
-	case "application/x-xz":
-		decompressor = NewTarXZArchive(bufferedReader).StripComponents(a.components)
-	case "application/x-bzip2":
-		decompressor = NewTarBzip2Archive(bufferedReader).StripComponents(a.components)
-	case "application/zip":
-		decompressor = NewZipArchive(bufferedReader)
-	case "text/plain; charset=utf-8", "application/jar":
-		destination = filepath.Join(destination, a.name)
-		decompressor = NewNopArchive(bufferedReader)
+	switch archiveType {
+	case ArchiveTypeTar:
+		return NewTarArchive(bufferedReader).StripComponents(a.components).withOptions(a.archiveOptions), false, nil
+	case ArchiveTypeGzip:
+		return NewTarGzipArchive(bufferedReader).StripComponents(a.components).withOptions(a.archiveOptions), false, nil
+	case ArchiveTypeXZ:
+		// An xz stream may wrap either a tar archive or a single file, so the
+		// decompressed header is checked for the ustar magic to tell them apart.
+		xzr, err := xz.NewReader(bufferedReader)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to create xz reader: %w", err)
+		}
+
+		decompressedReader := bufio.NewReader(xzr)
+		header, err := decompressedReader.Peek(262)
+		if err != nil && err != io.EOF {
+			return nil, false, fmt.Errorf("failed to read xz header: %w", err)
+		}
+
+		if len(header) == 262 && bytes.HasPrefix(header[257:], []byte("ustar")) {
+			return NewTarArchive(decompressedReader).StripComponents(a.components).withOptions(a.archiveOptions), false, nil
+		}
+
+		return NewNopArchive(decompressedReader), true, nil
+	case ArchiveTypeBzip2:
+		return NewTarBzip2Archive(bufferedReader).StripComponents(a.components).withOptions(a.archiveOptions), false, nil
+	case ArchiveTypeLZ4:
+		return NewTarLZ4Archive(bufferedReader).StripComponents(a.components).withOptions(a.archiveOptions), false, nil
+	case ArchiveTypeZip:
+		// A zip archive on disk can be read directly rather than being buffered
+		// to a temporary file.
+		if a.path != "" {
+			return NewZipArchiveFromFile(a.path).withOptions(a.archiveOptions), false, nil
+		}
+
+		return NewZipArchive(bufferedReader).withOptions(a.archiveOptions), false, nil
+	case ArchiveTypeText, ArchiveTypeJar:
+		return NewNopArchive(bufferedReader), true, nil
 	default:
-		return fmt.Errorf("unsupported archive type: %s", mime.String())
+		return nil, false, fmt.Errorf("unsupported archive type: %s", mime)
 	}
+}
 
-	return decompressor.Decompress(destination)
+// Validate reads from Archive, determines the archive type of the input
+// stream, and reads every entry it contains without writing anything to disk.
+// It returns an error if the stream is truncated or malformed, if an entry
+// would be extracted outside of the destination directory, or if a file
+// appears more than once.
+func (a Archive) Validate() error {
+	source, closeSource, err := openSource(a.reader, a.path)
+	if err != nil {
+		return err
+	}
+	defer closeSource()
+	a.reader = source
+
+	validator, _, err := a.detect()
+	if err != nil {
+		return err
+	}
+
+	return validator.Validate()
 }
 
 // StripComponents behaves like the --strip-components flag on tar command
@@ -84,9 +198,84 @@ func (a Archive) StripComponents(components int) Archive {
 	return a
 }
 
+// WithDestinationMode sets the permissions used for directories that are
+// created implicitly because a file in the archive is nested inside of them.
+// Defaults to os.ModePerm.
+func (a Archive) WithDestinationMode(mode os.FileMode) Archive {
+	a.mode = mode
+	return a
+}
+
 // WithName provides a way of overriding the name of the file
 // that the decompressed file will be copied into.
 func (a Archive) WithName(name string) Archive {
 	a.name = name
 	return a
 }
+
+// WithFlatten discards the directory structure of the archive when enabled,
+// extracting every file directly into the destination. Decompression fails if
+// two files in the archive share the same name. Setting this is a no-op for
+// input streams that are a single file.
+func (a Archive) WithFlatten(flatten bool) Archive {
+	a.flatten = flatten
+	return a
+}
+
+// WithXattrs applies the extended attributes recorded in the PAX headers of
+// tar archives to the extracted files when enabled. Setting this is a no-op
+// for other input streams and on platforms that do not support extended
+// attributes.
+func (a Archive) WithXattrs(xattrs bool) Archive {
+	a.xattrs = xattrs
+	return a
+}
+
+// WithConcurrencyLimiter bounds the number of extractions that write to disk
+// at the same time. Each extraction of a tar or zip archive holds a slot on
+// the given channel while it writes files, so the capacity of the channel is
+// the maximum number of concurrent extractions among all archives sharing it.
+// Setting this is a no-op for input streams that are a single file. A nil
+// channel imposes no limit.
+func (a Archive) WithConcurrencyLimiter(limiter chan struct{}) Archive {
+	a.limiter = limiter
+	return a
+}
+
+// WithStrictEntryTypes causes decompression of tar archives to fail when the
+// archive contains an entry whose type is not supported, such as a hard link
+// or device node, rather than silently skipping it. Setting this is a no-op
+// for other input streams.
+func (a Archive) WithStrictEntryTypes(strict bool) Archive {
+	a.strict = strict
+	return a
+}
+
+// WithRootMode sets the permissions of the destination directory once
+// decompression has completed, regardless of the permissions recorded in the
+// archive. For input streams that are a single file, the mode is applied to
+// the directory that contains the file.
+func (a Archive) WithRootMode(mode os.FileMode) Archive {
+	a.rootMode = mode
+	return a
+}
+
+// WithNormalizedModes sets the permissions of every extracted regular file to
+// fileMode and of every extracted directory to dirMode once decompression has
+// completed, regardless of the permissions recorded in the archive or the
+// umask, so that the extracted files are reproducible. A mode of zero leaves
+// the permissions of that kind of entry unchanged. Setting this is a no-op for
+// input streams that are a single file.
+func (a Archive) WithNormalizedModes(fileMode, dirMode os.FileMode) Archive {
+	a.modes.file = fileMode
+	a.modes.dir = dirMode
+	return a
+}
+
+// WithPreservedExecutables keeps files that are executable in the archive
+// executable when their modes are normalized with WithNormalizedModes, by
+// adding an execute bit for each read bit of the normalized file mode.
+func (a Archive) WithPreservedExecutables(preserve bool) Archive {
+	a.modes.executables = preserve
+	return a
+}
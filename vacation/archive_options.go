@@ -0,0 +1,17 @@
+package vacation
+
+import "os"
+
+// archiveOptions holds the extraction options that are shared by the archive
+// types, so that an archive that hands decompression off to another archive
+// can forward them all at once. Each archive type only applies the options
+// that are meaningful for it.
+type archiveOptions struct {
+	mode     os.FileMode
+	flatten  bool
+	xattrs   bool
+	limiter  chan struct{}
+	strict   bool
+	rootMode os.FileMode
+	modes    normalizedModes
+}
@@ -11,6 +11,7 @@ import (
 
 	dsnetBzip2 "github.com/dsnet/compress/bzip2"
 	"github.com/paketo-buildpacks/packit/vacation"
+	"github.com/pierrec/lz4/v4"
 	"github.com/sclevine/spec"
 	"github.com/ulikunitz/xz"
 
@@ -81,6 +82,64 @@ func testArchive(t *testing.T, context spec.G, it spec.S) {
 					filepath.Join(tempDir, "some-nested-file"),
 				}))
 			})
+
+			it("sets the mode of the destination when given a root mode", func() {
+				Expect(os.Chmod(tempDir, 0755)).To(Succeed())
+
+				err := archive.WithRootMode(0750).Decompress(tempDir)
+				Expect(err).NotTo(HaveOccurred())
+
+				info, err := os.Stat(tempDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Mode().Perm()).To(Equal(os.FileMode(0750)))
+			})
+
+			it("normalizes the modes of the unpackaged files when given normalized modes", func() {
+				err := archive.WithNormalizedModes(0600, 0700).Decompress(tempDir)
+				Expect(err).NotTo(HaveOccurred())
+
+				info, err := os.Stat(filepath.Join(tempDir, "some-dir"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Mode().Perm()).To(Equal(os.FileMode(0700)))
+
+				for _, path := range []string{"some-file", filepath.Join("some-dir", "some-nested-file")} {
+					info, err := os.Stat(filepath.Join(tempDir, path))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)), path)
+				}
+			})
+
+			it("waits for a slot on the concurrency limiter before unpackaging", func() {
+				withT := NewWithT(t)
+
+				limiter := make(chan struct{}, 1)
+				limiter <- struct{}{}
+
+				done := make(chan error)
+				go func() {
+					done <- archive.WithConcurrencyLimiter(limiter).Decompress(tempDir)
+				}()
+
+				withT.Consistently(done, "100ms").ShouldNot(Receive())
+
+				files, err := filepath.Glob(filepath.Join(tempDir, "*"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(files).To(BeEmpty())
+
+				<-limiter
+
+				var decompressErr error
+				withT.Eventually(done).Should(Receive(&decompressErr))
+				Expect(decompressErr).NotTo(HaveOccurred())
+
+				files, err = filepath.Glob(filepath.Join(tempDir, "*"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(files).To(ConsistOf([]string{
+					filepath.Join(tempDir, "some-dir"),
+					filepath.Join(tempDir, "some-file"),
+				}))
+				Expect(limiter).To(BeEmpty())
+			})
 		})
 
 		context("when passed the reader of a tar gzip file", func() {
@@ -209,6 +268,120 @@ func testArchive(t *testing.T, context spec.G, it spec.S) {
 			})
 		})
 
+		context("when passed the reader of a single xz file", func() {
+			var (
+				archive vacation.Archive
+				tempDir string
+			)
+
+			it.Before(func() {
+				var err error
+				tempDir, err = os.MkdirTemp("", "vacation")
+				Expect(err).NotTo(HaveOccurred())
+
+				buffer := bytes.NewBuffer(nil)
+				xzw, err := xz.NewWriter(buffer)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = xzw.Write([]byte("some-binary-contents"))
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(xzw.Close()).To(Succeed())
+
+				archive = vacation.NewArchive(buffer)
+			})
+
+			it.After(func() {
+				Expect(os.RemoveAll(tempDir)).To(Succeed())
+			})
+
+			it("writes the decompressed file onto the path", func() {
+				err := archive.Decompress(tempDir)
+				Expect(err).NotTo(HaveOccurred())
+
+				content, err := os.ReadFile(filepath.Join(tempDir, "artifact"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(content).To(Equal([]byte("some-binary-contents")))
+			})
+
+			context("when given a name", func() {
+				it.Before(func() {
+					archive = archive.WithName("some-binary")
+				})
+
+				it("writes the decompressed file onto the path with that name", func() {
+					err := archive.Decompress(tempDir)
+					Expect(err).NotTo(HaveOccurred())
+
+					content, err := os.ReadFile(filepath.Join(tempDir, "some-binary"))
+					Expect(err).NotTo(HaveOccurred())
+					Expect(content).To(Equal([]byte("some-binary-contents")))
+				})
+			})
+		})
+
+		context("when passed the reader of a tar lz4 file", func() {
+			var (
+				archive vacation.Archive
+				tempDir string
+			)
+
+			it.Before(func() {
+				var err error
+				tempDir, err = os.MkdirTemp("", "vacation")
+				Expect(err).NotTo(HaveOccurred())
+
+				buffer := bytes.NewBuffer(nil)
+				lzw := lz4.NewWriter(buffer)
+				tw := tar.NewWriter(lzw)
+
+				Expect(tw.WriteHeader(&tar.Header{Name: "some-dir", Mode: 0755, Typeflag: tar.TypeDir})).To(Succeed())
+				_, err = tw.Write(nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				nestedFile := filepath.Join("some-dir", "some-nested-file")
+				Expect(tw.WriteHeader(&tar.Header{Name: nestedFile, Mode: 0755, Size: int64(len(nestedFile))})).To(Succeed())
+				_, err = tw.Write([]byte(nestedFile))
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(tw.WriteHeader(&tar.Header{Name: "some-file", Mode: 0755, Size: int64(len("some-file"))})).To(Succeed())
+				_, err = tw.Write([]byte("some-file"))
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(tw.Close()).To(Succeed())
+				Expect(lzw.Close()).To(Succeed())
+
+				archive = vacation.NewArchive(buffer)
+			})
+
+			it.After(func() {
+				Expect(os.RemoveAll(tempDir)).To(Succeed())
+			})
+
+			it("unpackages the archive into the path", func() {
+				err := archive.Decompress(tempDir)
+				Expect(err).NotTo(HaveOccurred())
+
+				files, err := filepath.Glob(filepath.Join(tempDir, "*"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(files).To(ConsistOf([]string{
+					filepath.Join(tempDir, "some-dir"),
+					filepath.Join(tempDir, "some-file"),
+				}))
+			})
+
+			it("unpackages the archive into the path but also strips the first component", func() {
+				err := archive.StripComponents(1).Decompress(tempDir)
+				Expect(err).NotTo(HaveOccurred())
+
+				files, err := filepath.Glob(filepath.Join(tempDir, "*"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(files).To(ConsistOf([]string{
+					filepath.Join(tempDir, "some-nested-file"),
+				}))
+			})
+		})
+
 		context("when passed the reader of a bzip2 file", func() {
 			var (
 				archive vacation.Archive
@@ -440,4 +613,85 @@ func testArchive(t *testing.T, context spec.G, it spec.S) {
 			})
 		})
 	})
+	context("Validate", func() {
+		var buffer *bytes.Buffer
+
+		it.Before(func() {
+			buffer = bytes.NewBuffer(nil)
+			gw := gzip.NewWriter(buffer)
+			tw := tar.NewWriter(gw)
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "some-file", Mode: 0644, Size: int64(len("some-content"))})).To(Succeed())
+			_, err := tw.Write([]byte("some-content"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tw.Close()).To(Succeed())
+			Expect(gw.Close()).To(Succeed())
+		})
+
+		it("validates the detected archive type", func() {
+			Expect(vacation.NewArchive(bytes.NewReader(buffer.Bytes())).Validate()).To(Succeed())
+		})
+
+		context("failure cases", func() {
+			context("when the compressed stream is truncated", func() {
+				it("returns an error", func() {
+					truncated := buffer.Bytes()[:buffer.Len()-4]
+
+					err := vacation.NewArchive(bytes.NewReader(truncated)).Validate()
+					Expect(err).To(MatchError(ContainSubstring("unexpected EOF")))
+				})
+			})
+		})
+	})
+
+	context("List", func() {
+		context("when passed the reader of a tar gzip file", func() {
+			it("lists the entries in the archive", func() {
+				buffer := bytes.NewBuffer(nil)
+				gw := gzip.NewWriter(buffer)
+				tw := tar.NewWriter(gw)
+
+				Expect(tw.WriteHeader(&tar.Header{Name: "some-dir/", Mode: 0755, Typeflag: tar.TypeDir})).To(Succeed())
+				_, err := tw.Write(nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(tw.WriteHeader(&tar.Header{Name: "some-dir/some-file", Mode: 0644, Size: int64(len("some-file"))})).To(Succeed())
+				_, err = tw.Write([]byte("some-file"))
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(tw.Close()).To(Succeed())
+				Expect(gw.Close()).To(Succeed())
+
+				entries, err := vacation.NewArchive(buffer).List()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(entries).To(Equal([]vacation.Entry{
+					{Name: "some-dir/", Mode: os.ModeDir | 0755, Typeflag: tar.TypeDir},
+					{Name: "some-dir/some-file", Size: 9, Mode: 0644, Typeflag: tar.TypeReg},
+				}))
+			})
+		})
+
+		context("when passed the reader of a text file", func() {
+			it("lists the contents as a single named file", func() {
+				entries, err := vacation.NewArchive(bytes.NewBuffer([]byte(`some contents`))).WithName("some-text-file").List()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(entries).To(Equal([]vacation.Entry{
+					{Name: "some-text-file", Size: 13, Typeflag: tar.TypeReg},
+				}))
+			})
+		})
+
+		context("failure cases", func() {
+			context("the buffer passed is of are unknown type", func() {
+				it("returns an error", func() {
+					// This is a FLAC header
+					buffer := bytes.NewBuffer([]byte("\x66\x4C\x61\x43\x00\x00\x00\x22"))
+
+					_, err := vacation.NewArchive(buffer).List()
+					Expect(err).To(MatchError(ContainSubstring("unsupported archive type:")))
+				})
+			})
+		})
+	})
 }
@@ -0,0 +1,12 @@
+package vacation
+
+// acquire blocks until a slot is available on the given limiter and returns a
+// function that releases that slot. A nil limiter imposes no limit.
+func acquire(limiter chan struct{}) func() {
+	if limiter == nil {
+		return func() {}
+	}
+
+	limiter <- struct{}{}
+	return func() { <-limiter }
+}
@@ -0,0 +1,14 @@
+package vacation
+
+import "os"
+
+// An Entry describes a single file, directory, or symlink contained in an
+// archive. The Typeflag uses the values defined in the archive/tar package,
+// regardless of the archive format the entry was read from.
+type Entry struct {
+	Name     string
+	Size     int64
+	Mode     os.FileMode
+	Typeflag byte
+	Linkname string
+}
@@ -12,6 +12,7 @@ import (
 
 	dsnetBzip2 "github.com/dsnet/compress/bzip2"
 	"github.com/paketo-buildpacks/packit/vacation"
+	"github.com/pierrec/lz4/v4"
 	"github.com/ulikunitz/xz"
 )
 
@@ -568,6 +569,69 @@ func ExampleTarXZArchive_StripComponents() {
 	// some-other-dir/some-file
 }
 
+func ExampleTarLZ4Archive() {
+	buffer := bytes.NewBuffer(nil)
+	lw := lz4.NewWriter(buffer)
+	tw := tar.NewWriter(lw)
+
+	files := []ArchiveFile{
+		{Name: "some-dir/"},
+		{Name: "some-dir/some-other-dir/"},
+		{Name: "some-dir/some-other-dir/some-file", Content: []byte("some-dir/some-other-dir/some-file")},
+		{Name: "first", Content: []byte("first")},
+		{Name: "second", Content: []byte("second")},
+		{Name: "third", Content: []byte("third")},
+	}
+
+	for _, file := range files {
+		err := tw.WriteHeader(&tar.Header{Name: file.Name, Mode: 0755, Size: int64(len(file.Content))})
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		_, err = tw.Write(file.Content)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	tw.Close()
+	lw.Close()
+
+	destination, err := os.MkdirTemp("", "destination")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(destination)
+
+	archive := vacation.NewTarLZ4Archive(bytes.NewReader(buffer.Bytes()))
+	if err := archive.Decompress(destination); err != nil {
+		log.Fatal(err)
+	}
+
+	err = filepath.Walk(destination, func(path string, info os.FileInfo, err error) error {
+		if !info.IsDir() {
+			rel, err := filepath.Rel(destination, path)
+			if err != nil {
+				log.Fatal(err)
+			}
+
+			fmt.Printf("%s\n", rel)
+			return nil
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Output:
+	// first
+	// second
+	// some-dir/some-other-dir/some-file
+	// third
+}
+
 func ExampleTarBzip2Archive() {
 	buffer := bytes.NewBuffer(nil)
 
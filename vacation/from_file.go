@@ -0,0 +1,24 @@
+package vacation
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// openSource returns the file at path when a path is given, or the given reader
+// otherwise, along with a function that closes anything that was opened. It
+// allows the archives returned by the FromFile constructors to open their file
+// only for as long as it is being read.
+func openSource(reader io.Reader, path string) (io.Reader, func(), error) {
+	if path == "" {
+		return reader, func() {}, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+
+	return file, func() { file.Close() }, nil
+}
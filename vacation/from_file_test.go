@@ -0,0 +1,254 @@
+package vacation_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	dsnetBzip2 "github.com/dsnet/compress/bzip2"
+	"github.com/paketo-buildpacks/packit/vacation"
+	"github.com/pierrec/lz4/v4"
+	"github.com/sclevine/spec"
+	"github.com/ulikunitz/xz"
+
+	. "github.com/onsi/gomega"
+)
+
+func testFromFile(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		sourceDir string
+		tempDir   string
+	)
+
+	it.Before(func() {
+		var err error
+		sourceDir, err = os.MkdirTemp("", "source")
+		Expect(err).NotTo(HaveOccurred())
+
+		tempDir, err = os.MkdirTemp("", "vacation")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(sourceDir)).To(Succeed())
+		Expect(os.RemoveAll(tempDir)).To(Succeed())
+	})
+
+	// writeArchive creates a file at path and writes the archive produced by
+	// the given function into it.
+	writeArchive := func(path string, write func(w io.Writer) error) {
+		file, err := os.Create(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(write(file)).To(Succeed())
+		Expect(file.Close()).To(Succeed())
+	}
+
+	writeTar := func(w io.Writer) error {
+		tw := tar.NewWriter(w)
+
+		err := tw.WriteHeader(&tar.Header{Name: "some-dir", Mode: 0755, Typeflag: tar.TypeDir})
+		if err != nil {
+			return err
+		}
+
+		err = tw.WriteHeader(&tar.Header{Name: "some-dir/some-file", Mode: 0644, Size: int64(len("some-content"))})
+		if err != nil {
+			return err
+		}
+
+		_, err = tw.Write([]byte("some-content"))
+		if err != nil {
+			return err
+		}
+
+		return tw.Close()
+	}
+
+	// compressed returns a function that writes a tar archive through the
+	// compressing writer returned by newWriter.
+	compressed := func(newWriter func(w io.Writer) (io.WriteCloser, error)) func(w io.Writer) error {
+		return func(w io.Writer) error {
+			cw, err := newWriter(w)
+			if err != nil {
+				return err
+			}
+
+			err = writeTar(cw)
+			if err != nil {
+				return err
+			}
+
+			return cw.Close()
+		}
+	}
+
+	writeZip := func(w io.Writer) error {
+		zw := zip.NewWriter(w)
+
+		_, err := zw.Create("some-dir/")
+		if err != nil {
+			return err
+		}
+
+		fw, err := zw.Create("some-dir/some-file")
+		if err != nil {
+			return err
+		}
+
+		_, err = fw.Write([]byte("some-content"))
+		if err != nil {
+			return err
+		}
+
+		return zw.Close()
+	}
+
+	writeGzip := compressed(func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil })
+
+	for _, tt := range []struct {
+		format  string
+		write   func(w io.Writer) error
+		archive func(path string) vacation.Decompressor
+	}{
+		{
+			format:  "tar",
+			write:   writeTar,
+			archive: func(path string) vacation.Decompressor { return vacation.NewTarArchiveFromFile(path) },
+		},
+		{
+			format:  "tgz",
+			write:   writeGzip,
+			archive: func(path string) vacation.Decompressor { return vacation.NewTarGzipArchiveFromFile(path) },
+		},
+		{
+			format: "tbz2",
+			write: compressed(func(w io.Writer) (io.WriteCloser, error) {
+				return dsnetBzip2.NewWriter(w, nil)
+			}),
+			archive: func(path string) vacation.Decompressor { return vacation.NewTarBzip2ArchiveFromFile(path) },
+		},
+		{
+			format: "txz",
+			write: compressed(func(w io.Writer) (io.WriteCloser, error) {
+				return xz.NewWriter(w)
+			}),
+			archive: func(path string) vacation.Decompressor { return vacation.NewTarXZArchiveFromFile(path) },
+		},
+		{
+			format:  "tlz4",
+			write:   compressed(func(w io.Writer) (io.WriteCloser, error) { return lz4.NewWriter(w), nil }),
+			archive: func(path string) vacation.Decompressor { return vacation.NewTarLZ4ArchiveFromFile(path) },
+		},
+		{
+			format:  "zip",
+			write:   writeZip,
+			archive: func(path string) vacation.Decompressor { return vacation.NewZipArchiveFromFile(path) },
+		},
+		{
+			format:  "detected tgz",
+			write:   writeGzip,
+			archive: func(path string) vacation.Decompressor { return vacation.NewArchiveFromFile(path) },
+		},
+		{
+			format:  "detected zip",
+			write:   writeZip,
+			archive: func(path string) vacation.Decompressor { return vacation.NewArchiveFromFile(path) },
+		},
+	} {
+		tt := tt
+
+		context("when decompressing a "+tt.format+" archive from a file", func() {
+			it("unpackages the archive into the path", func() {
+				path := filepath.Join(sourceDir, "archive")
+				writeArchive(path, tt.write)
+
+				err := tt.archive(path).Decompress(tempDir)
+				Expect(err).NotTo(HaveOccurred())
+
+				content, err := os.ReadFile(filepath.Join(tempDir, "some-dir", "some-file"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(Equal("some-content"))
+			})
+		})
+	}
+
+	context("when decompressing an xz file from a file", func() {
+		it("writes the decompressed file into the path", func() {
+			path := filepath.Join(sourceDir, "archive.xz")
+			writeArchive(path, func(w io.Writer) error {
+				xw, err := xz.NewWriter(w)
+				if err != nil {
+					return err
+				}
+
+				_, err = xw.Write([]byte("some-content"))
+				if err != nil {
+					return err
+				}
+
+				return xw.Close()
+			})
+
+			err := vacation.NewXZArchiveFromFile(path).WithName("some-file").Decompress(tempDir)
+			Expect(err).NotTo(HaveOccurred())
+
+			content, err := os.ReadFile(filepath.Join(tempDir, "some-file"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("some-content"))
+		})
+	})
+
+	context("when copying a file with a NopArchive", func() {
+		it("copies the file to the destination", func() {
+			path := filepath.Join(sourceDir, "some-file")
+			Expect(os.WriteFile(path, []byte("some-content"), 0644)).To(Succeed())
+
+			err := vacation.NewNopArchiveFromFile(path).Decompress(filepath.Join(tempDir, "some-file"))
+			Expect(err).NotTo(HaveOccurred())
+
+			content, err := os.ReadFile(filepath.Join(tempDir, "some-file"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("some-content"))
+		})
+	})
+
+	context("when the same archive is read more than once", func() {
+		it("opens the file again for each read", func() {
+			path := filepath.Join(sourceDir, "archive.tgz")
+			writeArchive(path, writeGzip)
+
+			archive := vacation.NewTarGzipArchiveFromFile(path)
+
+			entries, err := archive.List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(HaveLen(2))
+
+			Expect(archive.Validate()).To(Succeed())
+			Expect(archive.Decompress(tempDir)).To(Succeed())
+			Expect(filepath.Join(tempDir, "some-dir", "some-file")).To(BeARegularFile())
+		})
+	})
+
+	context("failure cases", func() {
+		context("when the file does not exist", func() {
+			it("returns an error", func() {
+				path := filepath.Join(sourceDir, "missing")
+
+				err := vacation.NewTarArchiveFromFile(path).Decompress(tempDir)
+				Expect(err).To(MatchError(ContainSubstring("failed to open archive")))
+
+				err = vacation.NewZipArchiveFromFile(path).Decompress(tempDir)
+				Expect(err).To(MatchError(ContainSubstring("failed to open archive")))
+
+				err = vacation.NewArchiveFromFile(path).Decompress(tempDir)
+				Expect(err).To(MatchError(ContainSubstring("failed to open archive")))
+			})
+		})
+	})
+}
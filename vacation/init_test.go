@@ -10,12 +10,16 @@ import (
 func TestVacation(t *testing.T) {
 	suite := spec.New("vacation", spec.Report(report.Terminal{}))
 	suite("Archive", testArchive)
+	suite("FromFile", testFromFile)
 	suite("NopArchive", testNopArchive)
+	suite("Sniff", testSniff)
 	suite("SymlinkSorting", testSymlinkSorting)
 	suite("TarArchive", testTarArchive)
 	suite("TarBzip2Archive", testTarBzip2Archive)
 	suite("TarGzipArchive", testTarGzipArchive)
+	suite("TarLZ4Archive", testTarLZ4Archive)
 	suite("TarXZArchive", testTarXZArchive)
+	suite("XZArchive", testXZArchive)
 	suite("ZipArchive", testZipArchive)
 	suite.Run(t)
 }
@@ -1,6 +1,7 @@
 package vacation
 
 import (
+	"archive/tar"
 	"io"
 	"os"
 )
@@ -9,6 +10,7 @@ import (
 // simply copying the reader to the destination.
 type NopArchive struct {
 	reader io.Reader
+	path   string
 }
 
 // NewNopArchive returns a new NopArchive
@@ -16,8 +18,24 @@ func NewNopArchive(r io.Reader) NopArchive {
 	return NopArchive{reader: r}
 }
 
+// NewNopArchiveFromFile returns a new NopArchive that reads from the file at
+// the given path. The file is opened each time the archive is read and is
+// closed once reading completes.
+func NewNopArchiveFromFile(path string) NopArchive {
+	archive := NewNopArchive(nil)
+	archive.path = path
+	return archive
+}
+
 // Decompress copies the reader contents into the destination specified.
 func (na NopArchive) Decompress(destination string) error {
+	source, closeSource, err := openSource(na.reader, na.path)
+	if err != nil {
+		return err
+	}
+	defer closeSource()
+	na.reader = source
+
 	file, err := os.Create(destination)
 	if err != nil {
 		return err
@@ -31,3 +49,35 @@ func (na NopArchive) Decompress(destination string) error {
 
 	return nil
 }
+
+// List reads the contents of the reader and reports them as a single regular
+// file entry with no name.
+func (na NopArchive) List() ([]Entry, error) {
+	source, closeSource, err := openSource(na.reader, na.path)
+	if err != nil {
+		return nil, err
+	}
+	defer closeSource()
+	na.reader = source
+
+	size, err := io.Copy(io.Discard, na.reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Entry{{Size: size, Typeflag: tar.TypeReg}}, nil
+}
+
+// Validate reads the contents of the reader without writing anything to disk,
+// returning any error encountered while reading.
+func (na NopArchive) Validate() error {
+	source, closeSource, err := openSource(na.reader, na.path)
+	if err != nil {
+		return err
+	}
+	defer closeSource()
+	na.reader = source
+
+	_, err = io.Copy(io.Discard, na.reader)
+	return err
+}
@@ -1,6 +1,7 @@
 package vacation_test
 
 import (
+	"archive/tar"
 	"bytes"
 	"os"
 	"path/filepath"
@@ -53,4 +54,13 @@ func testNopArchive(t *testing.T, context spec.G, it spec.S) {
 			})
 		})
 	})
+	context("List", func() {
+		it("reports the contents of the reader as a single file", func() {
+			entries, err := vacation.NewNopArchive(bytes.NewBuffer([]byte(`some contents`))).List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(Equal([]vacation.Entry{
+				{Size: 13, Typeflag: tar.TypeReg},
+			}))
+		})
+	})
 }
@@ -0,0 +1,87 @@
+package vacation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// normalizedModes holds the permissions that extracted files and directories
+// are given in place of those recorded in the archive. A mode of zero leaves
+// the permissions of that kind of entry unchanged.
+type normalizedModes struct {
+	file        os.FileMode
+	dir         os.FileMode
+	executables bool
+}
+
+// apply sets the permissions of the extracted files, which are given along
+// with the modes recorded for them in the archive, and of the extracted
+// directories. Directories that were created implicitly between an entry and
+// the destination are included, but the destination itself is not.
+func (n normalizedModes) apply(destination string, files map[string]os.FileMode, dirs []string) error {
+	if n.file == 0 && n.dir == 0 {
+		return nil
+	}
+
+	if n.file != 0 {
+		for path, archived := range files {
+			mode := n.file
+			if n.executables && archived&0111 != 0 {
+				mode |= (n.file & 0444) >> 2
+			}
+
+			err := os.Chmod(path, mode)
+			if err != nil {
+				return fmt.Errorf("failed to normalize file mode: %w", err)
+			}
+		}
+	}
+
+	if n.dir == 0 {
+		return nil
+	}
+
+	root := filepath.Clean(destination)
+	seen := map[string]bool{}
+	var all []string
+	collect := func(dir string) {
+		for dir != root && strings.HasPrefix(dir, root) && !seen[dir] {
+			seen[dir] = true
+			all = append(all, dir)
+			dir = filepath.Dir(dir)
+		}
+	}
+
+	for path := range files {
+		collect(filepath.Dir(path))
+	}
+
+	for _, dir := range dirs {
+		collect(dir)
+	}
+
+	// Apply the directory modes from the deepest directory to the shallowest so
+	// that a restrictive mode on a parent does not prevent the modes of its
+	// children from being set.
+	sort.Slice(all, func(i, j int) bool {
+		iDepth := strings.Count(all[i], string(filepath.Separator))
+		jDepth := strings.Count(all[j], string(filepath.Separator))
+		if iDepth != jDepth {
+			return iDepth > jDepth
+		}
+
+		return all[i] < all[j]
+	})
+
+	for _, dir := range all {
+		err := os.Chmod(dir, n.dir)
+		if err != nil {
+			return fmt.Errorf("failed to normalize directory mode: %w", err)
+		}
+	}
+
+	return nil
+}
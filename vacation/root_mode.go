@@ -0,0 +1,21 @@
+package vacation
+
+import (
+	"fmt"
+	"os"
+)
+
+// chmodRoot sets the permissions of the destination directory to the given
+// mode. A mode of zero leaves the permissions unchanged.
+func chmodRoot(destination string, mode os.FileMode) error {
+	if mode == 0 {
+		return nil
+	}
+
+	err := os.Chmod(destination, mode)
+	if err != nil {
+		return fmt.Errorf("failed to set destination mode: %w", err)
+	}
+
+	return nil
+}
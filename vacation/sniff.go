@@ -0,0 +1,133 @@
+package vacation
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+// ArchiveType identifies the format of an input stream as determined by
+// Sniff.
+type ArchiveType int
+
+const (
+	// ArchiveTypeUnknown is returned for input streams whose format is not
+	// recognized.
+	ArchiveTypeUnknown ArchiveType = iota
+
+	// ArchiveTypeTar is an uncompressed tar archive.
+	ArchiveTypeTar
+
+	// ArchiveTypeGzip is a gzip compressed stream.
+	ArchiveTypeGzip
+
+	// ArchiveTypeXZ is an xz compressed stream, which may contain either a tar
+	// archive or a single file.
+	ArchiveTypeXZ
+
+	// ArchiveTypeBzip2 is a bzip2 compressed stream.
+	ArchiveTypeBzip2
+
+	// ArchiveTypeLZ4 is an LZ4 compressed stream.
+	ArchiveTypeLZ4
+
+	// ArchiveTypeZstd is a Zstandard compressed stream. Archive does not
+	// support decompressing this format.
+	ArchiveTypeZstd
+
+	// ArchiveTypeZip is a zip archive.
+	ArchiveTypeZip
+
+	// ArchiveTypeJar is a Java archive, which Archive treats as a single file.
+	ArchiveTypeJar
+
+	// ArchiveTypeText is a plain text file, which Archive treats as a single
+	// file.
+	ArchiveTypeText
+)
+
+// String returns a short name for the archive type, such as "gzip".
+func (t ArchiveType) String() string {
+	switch t {
+	case ArchiveTypeTar:
+		return "tar"
+	case ArchiveTypeGzip:
+		return "gzip"
+	case ArchiveTypeXZ:
+		return "xz"
+	case ArchiveTypeBzip2:
+		return "bzip2"
+	case ArchiveTypeLZ4:
+		return "lz4"
+	case ArchiveTypeZstd:
+		return "zstd"
+	case ArchiveTypeZip:
+		return "zip"
+	case ArchiveTypeJar:
+		return "jar"
+	case ArchiveTypeText:
+		return "text"
+	default:
+		return "unknown"
+	}
+}
+
+// Sniff peeks at the header of the given reader to determine its archive
+// type without decompressing or extracting anything. Because the header is
+// consumed from the given reader, callers must continue reading from the
+// returned reader, which replays the header before the rest of the stream.
+func Sniff(r io.Reader) (ArchiveType, io.Reader, error) {
+	archiveType, _, bufferedReader, err := sniff(r)
+	if err != nil {
+		return ArchiveTypeUnknown, nil, err
+	}
+
+	return archiveType, bufferedReader, nil
+}
+
+// sniff determines the archive type of the input stream, also returning the
+// detected mimetype so that unsupported formats can be reported by name.
+func sniff(r io.Reader) (ArchiveType, string, *bufio.Reader, error) {
+	// Convert reader into a buffered read so that the header can be peeked to
+	// determine the type.
+	bufferedReader := bufio.NewReader(r)
+
+	// The number 3072 is lifted from the mimetype library and the definition of
+	// the constant at the time of writing this functionality is listed below.
+	// https://github.com/gabriel-vasile/mimetype/blob/c64c025a7c2d8d45ba57d3cebb50a1dbedb3ed7e/internal/matchers/matchers.go#L6
+	header, err := bufferedReader.Peek(3072)
+	if err != nil && err != io.EOF {
+		return ArchiveTypeUnknown, "", nil, err
+	}
+
+	// The mimetype library does not recognize LZ4 frames, so they are
+	// identified by the frame magic number instead.
+	if bytes.HasPrefix(header, []byte{0x04, 0x22, 0x4D, 0x18}) {
+		return ArchiveTypeLZ4, "application/x-lz4", bufferedReader, nil
+	}
+
+	mime := mimetype.Detect(header).String()
+
+	switch mime {
+	case "application/x-tar":
+		return ArchiveTypeTar, mime, bufferedReader, nil
+	case "application/gzip":
+		return ArchiveTypeGzip, mime, bufferedReader, nil
+	case "application/x-xz":
+		return ArchiveTypeXZ, mime, bufferedReader, nil
+	case "application/x-bzip2":
+		return ArchiveTypeBzip2, mime, bufferedReader, nil
+	case "application/zstd":
+		return ArchiveTypeZstd, mime, bufferedReader, nil
+	case "application/zip":
+		return ArchiveTypeZip, mime, bufferedReader, nil
+	case "application/jar":
+		return ArchiveTypeJar, mime, bufferedReader, nil
+	case "text/plain; charset=utf-8":
+		return ArchiveTypeText, mime, bufferedReader, nil
+	default:
+		return ArchiveTypeUnknown, mime, bufferedReader, nil
+	}
+}
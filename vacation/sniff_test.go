@@ -0,0 +1,156 @@
+package vacation_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	dsnetBzip2 "github.com/dsnet/compress/bzip2"
+	"github.com/paketo-buildpacks/packit/vacation"
+	"github.com/pierrec/lz4/v4"
+	"github.com/sclevine/spec"
+	"github.com/ulikunitz/xz"
+
+	. "github.com/onsi/gomega"
+)
+
+func testSniff(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	tarball := func() []byte {
+		buffer := bytes.NewBuffer(nil)
+		tw := tar.NewWriter(buffer)
+
+		Expect(tw.WriteHeader(&tar.Header{Name: "some-file", Mode: 0644, Size: int64(len("some-content"))})).To(Succeed())
+		_, err := tw.Write([]byte("some-content"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tw.Close()).To(Succeed())
+
+		return buffer.Bytes()
+	}
+
+	// compress returns the tarball written through the compressing writer
+	// returned by newWriter.
+	compress := func(newWriter func(w io.Writer) (io.WriteCloser, error)) []byte {
+		buffer := bytes.NewBuffer(nil)
+		cw, err := newWriter(buffer)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = cw.Write(tarball())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cw.Close()).To(Succeed())
+
+		return buffer.Bytes()
+	}
+
+	zipball := func() []byte {
+		buffer := bytes.NewBuffer(nil)
+		zw := zip.NewWriter(buffer)
+
+		fw, err := zw.Create("some-file")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = fw.Write([]byte("some-content"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(zw.Close()).To(Succeed())
+
+		return buffer.Bytes()
+	}
+
+	for _, tt := range []struct {
+		name        string
+		content     func() []byte
+		archiveType vacation.ArchiveType
+	}{
+		{
+			name:        "tar",
+			content:     tarball,
+			archiveType: vacation.ArchiveTypeTar,
+		},
+		{
+			name: "gzip",
+			content: func() []byte {
+				return compress(func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil })
+			},
+			archiveType: vacation.ArchiveTypeGzip,
+		},
+		{
+			name: "xz",
+			content: func() []byte {
+				return compress(func(w io.Writer) (io.WriteCloser, error) { return xz.NewWriter(w) })
+			},
+			archiveType: vacation.ArchiveTypeXZ,
+		},
+		{
+			name: "bzip2",
+			content: func() []byte {
+				return compress(func(w io.Writer) (io.WriteCloser, error) { return dsnetBzip2.NewWriter(w, nil) })
+			},
+			archiveType: vacation.ArchiveTypeBzip2,
+		},
+		{
+			name: "lz4",
+			content: func() []byte {
+				return compress(func(w io.Writer) (io.WriteCloser, error) { return lz4.NewWriter(w), nil })
+			},
+			archiveType: vacation.ArchiveTypeLZ4,
+		},
+		{
+			name: "zstd",
+			content: func() []byte {
+				// A Zstandard frame header followed by an empty raw block.
+				return []byte{0x28, 0xB5, 0x2F, 0xFD, 0x00, 0x00, 0x01, 0x00, 0x00}
+			},
+			archiveType: vacation.ArchiveTypeZstd,
+		},
+		{
+			name:        "zip",
+			content:     zipball,
+			archiveType: vacation.ArchiveTypeZip,
+		},
+		{
+			name: "text",
+			content: func() []byte {
+				return []byte("some-content")
+			},
+			archiveType: vacation.ArchiveTypeText,
+		},
+		{
+			name: "unknown",
+			content: func() []byte {
+				return []byte("\x89PNG\r\n\x1a\n")
+			},
+			archiveType: vacation.ArchiveTypeUnknown,
+		},
+	} {
+		tt := tt
+
+		context("when the input stream is "+tt.name, func() {
+			it("returns the archive type and a reader for the whole stream", func() {
+				content := tt.content()
+
+				archiveType, reader, err := vacation.Sniff(bytes.NewReader(content))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(archiveType).To(Equal(tt.archiveType))
+				Expect(archiveType.String()).To(Equal(tt.name))
+
+				replayed, err := io.ReadAll(reader)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(replayed).To(Equal(content))
+			})
+		})
+	}
+
+	context("failure cases", func() {
+		context("when the input stream cannot be read", func() {
+			it("returns an error", func() {
+				_, _, err := vacation.Sniff(iotest.ErrReader(errors.New("failed to read")))
+				Expect(err).To(MatchError("failed to read"))
+			})
+		})
+	})
+}
@@ -2,6 +2,7 @@ package vacation
 
 import (
 	"archive/tar"
+	"bytes"
 	"fmt"
 	"io"
 	"os"
@@ -13,17 +14,45 @@ import (
 // A TarArchive decompresses tar files from an input stream.
 type TarArchive struct {
 	reader     io.Reader
+	path       string
 	components int
+	archiveOptions
 }
 
 // NewTarArchive returns a new TarArchive that reads from inputReader.
 func NewTarArchive(inputReader io.Reader) TarArchive {
-	return TarArchive{reader: inputReader}
+	return TarArchive{
+		reader:         inputReader,
+		archiveOptions: archiveOptions{mode: os.ModePerm},
+	}
+}
+
+// NewTarArchiveFromFile returns a new TarArchive that reads from the file at
+// the given path. The file is opened each time the archive is read and is
+// closed once reading completes.
+func NewTarArchiveFromFile(path string) TarArchive {
+	archive := NewTarArchive(nil)
+	archive.path = path
+	return archive
 }
 
 // Decompress reads from TarArchive and writes files into the
 // destination specified.
 func (ta TarArchive) Decompress(destination string) error {
+	return ta.DecompressTo(OSFileSystem{}, destination)
+}
+
+// DecompressTo reads from TarArchive and writes files into the destination
+// specified on the given file system. Extended attributes and the root mode
+// are only applied when the file system is an OSFileSystem.
+func (ta TarArchive) DecompressTo(fsys WritableFS, destination string) error {
+	source, closeSource, err := openSource(ta.reader, ta.path)
+	if err != nil {
+		return err
+	}
+	defer closeSource()
+	ta.reader = source
+
 	// This map keeps track of what directories have been made already so that we
 	// only attempt to make them once for a cleaner interaction.  This map is
 	// only necessary in cases where there are no directory headers in the
@@ -41,6 +70,18 @@ func (ta TarArchive) Decompress(destination string) error {
 
 	var symlinkHeaders []header
 
+	// This map keeps track of the entry that was extracted to each path when
+	// flattening so that name collisions can be reported.
+	flattened := map[string]string{}
+
+	// These collect the regular files, along with their archived modes, and the
+	// directories that were extracted so that their modes can be normalized.
+	extractedFiles := map[string]os.FileMode{}
+	var extractedDirs []string
+
+	release := acquire(ta.limiter)
+	defer release()
+
 	tarReader := tar.NewReader(ta.reader)
 	for {
 		hdr, err := tarReader.Next()
@@ -71,25 +112,49 @@ func (ta TarArchive) Decompress(destination string) error {
 			continue
 		}
 
+		if ta.strict && !supportedTypeflag(hdr.Typeflag) {
+			return fmt.Errorf("failed to extract %s: unsupported entry type %s", name, typeflagName(hdr.Typeflag))
+		}
+
 		// Constructs the path that conforms to the stripped components.
 		path := filepath.Join(append([]string{destination}, fileNames[ta.components:]...)...)
 
+		if ta.flatten {
+			if hdr.Typeflag == tar.TypeDir {
+				continue
+			}
+
+			path = filepath.Join(destination, filepath.Base(path))
+			if previous, ok := flattened[path]; ok {
+				return fmt.Errorf("failed to flatten archive: %s and %s both extract to %s", previous, name, path)
+			}
+			flattened[path] = name
+		}
+
 		// This switch case handles all cases for creating the directory structure
 		// this logic is needed to handle tarballs with no directory headers.
 		switch hdr.Typeflag {
 		case tar.TypeDir:
-			err = os.MkdirAll(path, os.ModePerm)
+			err = fsys.MkdirAll(path, os.ModePerm)
 			if err != nil {
 				return fmt.Errorf("failed to create archived directory: %s", err)
 			}
 
 			directories[path] = nil
+			extractedDirs = append(extractedDirs, path)
+
+			if ta.applyXattrs(fsys) {
+				err = setXattrs(path, hdr)
+				if err != nil {
+					return err
+				}
+			}
 
 		default:
 			dir := filepath.Dir(path)
 			_, ok := directories[dir]
 			if !ok {
-				err = os.MkdirAll(dir, os.ModePerm)
+				err = fsys.MkdirAll(dir, ta.mode)
 				if err != nil {
 					return fmt.Errorf("failed to create archived directory from file path: %s", err)
 				}
@@ -99,13 +164,17 @@ func (ta TarArchive) Decompress(destination string) error {
 
 		// This switch case handles the creation of files during the untaring process.
 		switch hdr.Typeflag {
-		case tar.TypeReg:
-			file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, hdr.FileInfo().Mode())
+		case tar.TypeReg, tar.TypeGNUSparse:
+			file, err := fsys.Create(path, hdr.FileInfo().Mode())
 			if err != nil {
 				return fmt.Errorf("failed to create archived file: %s", err)
 			}
 
-			_, err = io.Copy(file, tarReader)
+			if sparseFile, ok := file.(sparseWriter); ok && isSparse(hdr) {
+				err = writeSparse(sparseFile, tarReader, hdr.Size)
+			} else {
+				_, err = io.Copy(file, tarReader)
+			}
 			if err != nil {
 				return err
 			}
@@ -115,6 +184,15 @@ func (ta TarArchive) Decompress(destination string) error {
 				return err
 			}
 
+			extractedFiles[path] = hdr.FileInfo().Mode()
+
+			if ta.applyXattrs(fsys) {
+				err = setXattrs(path, hdr)
+				if err != nil {
+					return err
+				}
+			}
+
 		case tar.TypeSymlink:
 			// Collect all of the headers for symlinks so that they can be verified
 			// after all other files are written
@@ -153,24 +231,277 @@ func (ta TarArchive) Decompress(destination string) error {
 	})
 
 	for _, h := range symlinkHeaders {
-		// Check to see if the file that will be linked to is valid for symlinking
-		_, err := filepath.EvalSymlinks(linknameFullPath(h.path, h.linkname))
+		err := fsys.Symlink(h.linkname, h.path)
 		if err != nil {
-			return fmt.Errorf("failed to evaluate symlink %s: %w", h.path, err)
+			return fmt.Errorf("failed to extract symlink: %w", err)
 		}
+	}
 
-		err = os.Symlink(h.linkname, h.path)
+	if _, ok := fsys.(OSFileSystem); ok {
+		err = ta.modes.apply(destination, extractedFiles, extractedDirs)
 		if err != nil {
-			return fmt.Errorf("failed to extract symlink: %s", err)
+			return err
 		}
+
+		return chmodRoot(destination, ta.rootMode)
 	}
 
 	return nil
 }
 
+// applyXattrs reports whether extended attributes should be applied to the
+// files written to the given file system.
+func (ta TarArchive) applyXattrs(fsys WritableFS) bool {
+	_, ok := fsys.(OSFileSystem)
+	return ta.xattrs && ok
+}
+
+// Validate reads every entry of the TarArchive without writing anything to
+// disk. It returns an error if the stream is truncated or malformed, if an
+// entry would be extracted outside of the destination directory, or if a file
+// appears more than once.
+func (ta TarArchive) Validate() error {
+	source, closeSource, err := openSource(ta.reader, ta.path)
+	if err != nil {
+		return err
+	}
+	defer closeSource()
+	ta.reader = source
+
+	validator := entryValidator{}
+
+	tarReader := tar.NewReader(ta.reader)
+	for {
+		hdr, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar response: %s", err)
+		}
+
+		err = validator.check(hdr.Name, hdr.Typeflag == tar.TypeDir)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(io.Discard, tarReader)
+		if err != nil {
+			return fmt.Errorf("failed to read tar entry %q: %w", hdr.Name, err)
+		}
+	}
+
+	// Drain the remainder of the stream so that compressed streams verify their
+	// trailing checksums.
+	_, err = io.Copy(io.Discard, ta.reader)
+	if err != nil {
+		return fmt.Errorf("failed to read tar response: %s", err)
+	}
+
+	return nil
+}
+
+// List reads from TarArchive and returns the entries it contains without
+// writing anything to disk.
+func (ta TarArchive) List() ([]Entry, error) {
+	source, closeSource, err := openSource(ta.reader, ta.path)
+	if err != nil {
+		return nil, err
+	}
+	defer closeSource()
+	ta.reader = source
+
+	var entries []Entry
+
+	tarReader := tar.NewReader(ta.reader)
+	for {
+		hdr, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tar response: %s", err)
+		}
+
+		entries = append(entries, Entry{
+			Name:     hdr.Name,
+			Size:     hdr.Size,
+			Mode:     hdr.FileInfo().Mode(),
+			Typeflag: hdr.Typeflag,
+			Linkname: hdr.Linkname,
+		})
+	}
+
+	return entries, nil
+}
+
+// isSparse reports whether the header describes a GNU sparse file, in either
+// the old GNU format or the PAX format.
+func isSparse(hdr *tar.Header) bool {
+	if hdr.Typeflag == tar.TypeGNUSparse {
+		return true
+	}
+
+	for key := range hdr.PAXRecords {
+		if strings.HasPrefix(key, "GNU.sparse.") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sparseWriter is implemented by files that can have holes seeked over and
+// be truncated to their final size, such as *os.File.
+type sparseWriter interface {
+	io.WriteSeeker
+	Truncate(size int64) error
+}
+
+// writeSparse copies the contents of the reader into the file, seeking over
+// blocks that are entirely zero rather than writing them so that the file
+// system can record them as holes. The file is truncated to the given size
+// afterwards so that a trailing hole is kept. On file systems that do not
+// support sparse files, the holes are filled with zeros.
+func writeSparse(file sparseWriter, reader io.Reader, size int64) error {
+	buffer := make([]byte, 32*1024)
+	for {
+		n, err := io.ReadFull(reader, buffer)
+		if n > 0 {
+			var writeErr error
+			if bytes.Count(buffer[:n], []byte{0}) == n {
+				_, writeErr = file.Seek(int64(n), io.SeekCurrent)
+			} else {
+				_, writeErr = file.Write(buffer[:n])
+			}
+
+			if writeErr != nil {
+				return writeErr
+			}
+		}
+
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return file.Truncate(size)
+}
+
 // StripComponents behaves like the --strip-components flag on tar command
 // removing the first n levels from the final decompression destination.
 func (ta TarArchive) StripComponents(components int) TarArchive {
 	ta.components = components
 	return ta
 }
+
+// withOptions replaces the shared extraction options with the given options.
+func (ta TarArchive) withOptions(options archiveOptions) TarArchive {
+	ta.archiveOptions = options
+	return ta
+}
+
+// WithDestinationMode sets the permissions used for directories that are
+// created implicitly because a file in the archive is nested inside of them.
+// Defaults to os.ModePerm.
+func (ta TarArchive) WithDestinationMode(mode os.FileMode) TarArchive {
+	ta.mode = mode
+	return ta
+}
+
+// WithFlatten discards the directory structure of the archive when enabled,
+// extracting every file directly into the destination. Decompression fails if
+// two files in the archive share the same name.
+func (ta TarArchive) WithFlatten(flatten bool) TarArchive {
+	ta.flatten = flatten
+	return ta
+}
+
+// WithXattrs applies the extended attributes recorded in the PAX headers of
+// the archive, as SCHILY.xattr.* records, to the extracted files and
+// directories when enabled. This is a no-op on platforms that do not support
+// extended attributes.
+func (ta TarArchive) WithXattrs(xattrs bool) TarArchive {
+	ta.xattrs = xattrs
+	return ta
+}
+
+// WithConcurrencyLimiter bounds the number of extractions that write to disk
+// at the same time. Each extraction holds a slot on the given channel while it
+// writes files, so the capacity of the channel is the maximum number of
+// concurrent extractions among all archives sharing it. A nil channel imposes
+// no limit.
+func (ta TarArchive) WithConcurrencyLimiter(limiter chan struct{}) TarArchive {
+	ta.limiter = limiter
+	return ta
+}
+
+// WithStrictEntryTypes causes decompression to fail when the archive contains
+// an entry whose type is not supported, such as a hard link or device node,
+// rather than silently skipping it.
+func (ta TarArchive) WithStrictEntryTypes(strict bool) TarArchive {
+	ta.strict = strict
+	return ta
+}
+
+// supportedTypeflag reports whether entries of the given type are extracted
+// by DecompressTo.
+func supportedTypeflag(typeflag byte) bool {
+	switch typeflag {
+	case tar.TypeReg, tar.TypeGNUSparse, tar.TypeDir, tar.TypeSymlink:
+		return true
+	default:
+		return false
+	}
+}
+
+// typeflagName returns a human readable name for the given tar entry type.
+func typeflagName(typeflag byte) string {
+	switch typeflag {
+	case tar.TypeLink:
+		return "hard link"
+	case tar.TypeChar:
+		return "character device"
+	case tar.TypeBlock:
+		return "block device"
+	case tar.TypeFifo:
+		return "fifo"
+	case tar.TypeCont:
+		return "contiguous file"
+	default:
+		return fmt.Sprintf("%q", typeflag)
+	}
+}
+
+// WithRootMode sets the permissions of the destination directory once
+// decompression has completed, regardless of the permissions recorded in the
+// archive. It is only applied when decompressing onto the host file system.
+func (ta TarArchive) WithRootMode(mode os.FileMode) TarArchive {
+	ta.rootMode = mode
+	return ta
+}
+
+// WithNormalizedModes sets the permissions of every extracted regular file to
+// fileMode and of every extracted directory to dirMode once decompression has
+// completed, regardless of the permissions recorded in the archive or the
+// umask, so that the extracted files are reproducible. A mode of zero leaves
+// the permissions of that kind of entry unchanged. It is only applied when
+// decompressing onto the host file system.
+func (ta TarArchive) WithNormalizedModes(fileMode, dirMode os.FileMode) TarArchive {
+	ta.modes.file = fileMode
+	ta.modes.dir = dirMode
+	return ta
+}
+
+// WithPreservedExecutables keeps files that are executable in the archive
+// executable when their modes are normalized with WithNormalizedModes, by
+// adding an execute bit for each read bit of the normalized file mode. For
+// example, a normalized file mode of 0644 is applied as 0755 to executables.
+func (ta TarArchive) WithPreservedExecutables(preserve bool) TarArchive {
+	ta.modes.executables = preserve
+	return ta
+}
@@ -3,10 +3,14 @@ package vacation_test
 import (
 	"archive/tar"
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/paketo-buildpacks/packit/vacation"
 	"github.com/sclevine/spec"
@@ -98,6 +102,99 @@ func testTarArchive(t *testing.T, context spec.G, it spec.S) {
 			Expect(data).To(Equal([]byte(`first`)))
 		})
 
+		context("when given a root mode", func() {
+			it("sets the mode of the destination after unpackaging the archive", func() {
+				err := tarArchive.WithRootMode(0700).Decompress(tempDir)
+				Expect(err).NotTo(HaveOccurred())
+
+				info, err := os.Stat(tempDir)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Mode().Perm()).To(Equal(os.FileMode(0700)))
+
+				Expect(filepath.Join(tempDir, "some-dir", "some-other-dir", "some-file")).To(BeARegularFile())
+			})
+		})
+
+		context("when given normalized modes", func() {
+			var modes map[string]os.FileMode
+
+			it.Before(func() {
+				buffer := bytes.NewBuffer(nil)
+				tw := tar.NewWriter(buffer)
+
+				Expect(tw.WriteHeader(&tar.Header{Name: "some-dir", Mode: 0700, Typeflag: tar.TypeDir})).To(Succeed())
+				Expect(tw.WriteHeader(&tar.Header{Name: "some-dir/some-other-dir", Mode: 0777, Typeflag: tar.TypeDir})).To(Succeed())
+
+				for name, mode := range map[string]int64{
+					"some-dir/some-other-dir/some-executable": 0750,
+					"some-dir/some-file":                      0600,
+					"implicit-dir/nested-dir/some-file":       0664,
+				} {
+					Expect(tw.WriteHeader(&tar.Header{Name: name, Mode: mode, Size: int64(len(name))})).To(Succeed())
+					_, err := tw.Write([]byte(name))
+					Expect(err).NotTo(HaveOccurred())
+				}
+
+				Expect(tw.WriteHeader(&tar.Header{Name: "symlink", Mode: 0777, Typeflag: tar.TypeSymlink, Linkname: "some-dir/some-file"})).To(Succeed())
+				Expect(tw.Close()).To(Succeed())
+
+				tarArchive = vacation.NewTarArchive(bytes.NewReader(buffer.Bytes()))
+
+				modes = map[string]os.FileMode{}
+			})
+
+			// collectModes records the permissions of every file and directory that
+			// was extracted into the destination, skipping symlinks.
+			collectModes := func() {
+				err := filepath.Walk(tempDir, func(path string, info os.FileInfo, err error) error {
+					if err != nil {
+						return err
+					}
+
+					if path == tempDir || info.Mode()&os.ModeSymlink != 0 {
+						return nil
+					}
+
+					rel, err := filepath.Rel(tempDir, path)
+					if err != nil {
+						return err
+					}
+
+					modes[rel] = info.Mode()
+					return nil
+				})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			it("gives every extracted file and directory the configured modes", func() {
+				err := tarArchive.WithNormalizedModes(0644, 0755).Decompress(tempDir)
+				Expect(err).NotTo(HaveOccurred())
+
+				collectModes()
+				Expect(modes).To(Equal(map[string]os.FileMode{
+					"some-dir":                os.ModeDir | 0755,
+					"some-dir/some-other-dir": os.ModeDir | 0755,
+					"some-dir/some-other-dir/some-executable": 0644,
+					"some-dir/some-file":                      0644,
+					"implicit-dir":                            os.ModeDir | 0755,
+					"implicit-dir/nested-dir":                 os.ModeDir | 0755,
+					"implicit-dir/nested-dir/some-file":       0644,
+				}))
+			})
+
+			context("when executables are preserved", func() {
+				it("keeps the executable files executable", func() {
+					err := tarArchive.WithNormalizedModes(0644, 0755).WithPreservedExecutables(true).Decompress(tempDir)
+					Expect(err).NotTo(HaveOccurred())
+
+					collectModes()
+					Expect(modes).To(HaveKeyWithValue("some-dir/some-other-dir/some-executable", os.FileMode(0755)))
+					Expect(modes).To(HaveKeyWithValue("some-dir/some-file", os.FileMode(0644)))
+					Expect(modes).To(HaveKeyWithValue("implicit-dir/nested-dir/some-file", os.FileMode(0644)))
+				})
+			})
+		})
+
 		it("unpackages the archive into the path but also strips the first component", func() {
 			var err error
 			err = tarArchive.StripComponents(1).Decompress(tempDir)
@@ -114,6 +211,25 @@ func testTarArchive(t *testing.T, context spec.G, it spec.S) {
 
 		})
 
+		it("unpackages the archive into the path but also flattens the directory structure", func() {
+			err := tarArchive.WithFlatten(true).Decompress(tempDir)
+			Expect(err).ToNot(HaveOccurred())
+
+			files, err := filepath.Glob(fmt.Sprintf("%s/*", tempDir))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(ConsistOf([]string{
+				filepath.Join(tempDir, "first"),
+				filepath.Join(tempDir, "second"),
+				filepath.Join(tempDir, "third"),
+				filepath.Join(tempDir, "some-file"),
+				filepath.Join(tempDir, "symlink"),
+			}))
+
+			data, err := os.ReadFile(filepath.Join(tempDir, "some-file"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(Equal(filepath.Join("some-dir", "some-other-dir", "some-file")))
+		})
+
 		context("there is no directory metadata", func() {
 			it.Before(func() {
 				var err error
@@ -148,6 +264,63 @@ func testTarArchive(t *testing.T, context spec.G, it spec.S) {
 			})
 		})
 
+		context("when the archive contains a GNU sparse file", func() {
+			it.Before(func() {
+				tarArchive = vacation.NewTarArchive(bytes.NewReader(gnuSparseArchive("sparse-file", 2*1024*1024, []sparseChunk{
+					{offset: 0, data: "some-data"},
+					{offset: 1024 * 1024, data: "other-data"},
+				})))
+			})
+
+			it("writes the file with its holes filled with zeros", func() {
+				err := tarArchive.Decompress(tempDir)
+				Expect(err).ToNot(HaveOccurred())
+
+				content, err := os.ReadFile(filepath.Join(tempDir, "sparse-file"))
+				Expect(err).NotTo(HaveOccurred())
+
+				expected := make([]byte, 2*1024*1024)
+				copy(expected, "some-data")
+				copy(expected[1024*1024:], "other-data")
+				Expect(content).To(Equal(expected))
+
+				info, err := os.Stat(filepath.Join(tempDir, "sparse-file"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Mode()).To(Equal(os.FileMode(0644)))
+			})
+		})
+
+		context("when given a destination mode", func() {
+			it.Before(func() {
+				var err error
+
+				buffer := bytes.NewBuffer(nil)
+				tw := tar.NewWriter(buffer)
+
+				nestedFile := filepath.Join("some-dir", "some-other-dir", "some-file")
+				Expect(tw.WriteHeader(&tar.Header{Name: nestedFile, Mode: 0755, Size: int64(len(nestedFile))})).To(Succeed())
+				_, err = tw.Write([]byte(nestedFile))
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(tw.Close()).To(Succeed())
+
+				tarArchive = vacation.NewTarArchive(bytes.NewReader(buffer.Bytes())).WithDestinationMode(0750)
+			})
+
+			it("creates the implied directories with that mode", func() {
+				err := tarArchive.Decompress(tempDir)
+				Expect(err).ToNot(HaveOccurred())
+
+				info, err := os.Stat(filepath.Join(tempDir, "some-dir", "some-other-dir"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Mode().Perm()).To(Equal(os.FileMode(0750)))
+
+				info, err = os.Stat(filepath.Join(tempDir, "some-dir"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Mode().Perm()).To(Equal(os.FileMode(0750)))
+			})
+		})
+
 		context("failure cases", func() {
 			context("when a file is not inside of the destination director (Zip Slip)", func() {
 				it.Before(func() {
@@ -172,6 +345,28 @@ func testTarArchive(t *testing.T, context spec.G, it spec.S) {
 				})
 			})
 
+			context("when flattening results in a name collision", func() {
+				it.Before(func() {
+					buffer := bytes.NewBuffer(nil)
+					tw := tar.NewWriter(buffer)
+
+					for _, file := range []string{filepath.Join("some-dir", "some-file"), filepath.Join("other-dir", "some-file")} {
+						Expect(tw.WriteHeader(&tar.Header{Name: file, Mode: 0644, Size: int64(len(file))})).To(Succeed())
+						_, err := tw.Write([]byte(file))
+						Expect(err).NotTo(HaveOccurred())
+					}
+
+					Expect(tw.Close()).To(Succeed())
+
+					tarArchive = vacation.NewTarArchive(bytes.NewReader(buffer.Bytes())).WithFlatten(true)
+				})
+
+				it("returns an error", func() {
+					err := tarArchive.Decompress(tempDir)
+					Expect(err).To(MatchError(fmt.Sprintf("failed to flatten archive: some-dir/some-file and other-dir/some-file both extract to %s", filepath.Join(tempDir, "some-file"))))
+				})
+			})
+
 			context("when it fails to read the tar response", func() {
 				it("returns an error", func() {
 					readyArchive := vacation.NewTarArchive(bytes.NewBuffer([]byte(`something`)))
@@ -290,4 +485,430 @@ func testTarArchive(t *testing.T, context spec.G, it spec.S) {
 			})
 		})
 	})
+	context("List", func() {
+		var tarArchive vacation.TarArchive
+
+		it.Before(func() {
+			var err error
+
+			buffer := bytes.NewBuffer(nil)
+			tw := tar.NewWriter(buffer)
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "some-dir/", Mode: 0755, Typeflag: tar.TypeDir})).To(Succeed())
+			_, err = tw.Write(nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "some-dir/some-file", Mode: 0644, Size: int64(len("some-file"))})).To(Succeed())
+			_, err = tw.Write([]byte("some-file"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "symlink", Mode: 0777, Typeflag: tar.TypeSymlink, Linkname: "some-dir/some-file"})).To(Succeed())
+			_, err = tw.Write(nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tw.Close()).To(Succeed())
+
+			tarArchive = vacation.NewTarArchive(bytes.NewReader(buffer.Bytes()))
+		})
+
+		it("lists the entries in the archive", func() {
+			entries, err := tarArchive.List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(Equal([]vacation.Entry{
+				{Name: "some-dir/", Mode: os.ModeDir | 0755, Typeflag: tar.TypeDir},
+				{Name: "some-dir/some-file", Size: 9, Mode: 0644, Typeflag: tar.TypeReg},
+				{Name: "symlink", Mode: os.ModeSymlink | 0777, Typeflag: tar.TypeSymlink, Linkname: "some-dir/some-file"},
+			}))
+		})
+
+		context("failure cases", func() {
+			context("when it fails to read the tar response", func() {
+				it("returns an error", func() {
+					_, err := vacation.NewTarArchive(bytes.NewBuffer([]byte(`something`))).List()
+					Expect(err).To(MatchError(ContainSubstring("failed to read tar response")))
+				})
+			})
+		})
+	})
+
+	context("DecompressTo", func() {
+		var (
+			fsys       *memoryFS
+			tarArchive vacation.TarArchive
+		)
+
+		it.Before(func() {
+			buffer := bytes.NewBuffer(nil)
+			tw := tar.NewWriter(buffer)
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "some-dir", Mode: 0755, Typeflag: tar.TypeDir})).To(Succeed())
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "some-dir/some-file", Mode: 0644, Size: int64(len("some-content"))})).To(Succeed())
+			_, err := tw.Write([]byte("some-content"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "other-dir/executable", Mode: 0755, Size: int64(len("executable-content"))})).To(Succeed())
+			_, err = tw.Write([]byte("executable-content"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "symlink", Mode: 0777, Typeflag: tar.TypeSymlink, Linkname: "some-dir/some-file"})).To(Succeed())
+
+			Expect(tw.Close()).To(Succeed())
+
+			tarArchive = vacation.NewTarArchive(bytes.NewReader(buffer.Bytes()))
+			fsys = newMemoryFS()
+		})
+
+		it("writes the archive into the given file system", func() {
+			destination := filepath.Join("/", "no-such-destination")
+
+			err := tarArchive.DecompressTo(fsys, destination)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fsys.dirs).To(Equal(map[string]os.FileMode{
+				destination:                             os.ModePerm,
+				filepath.Join(destination, "some-dir"):  os.ModePerm,
+				filepath.Join(destination, "other-dir"): os.ModePerm,
+			}))
+
+			Expect(fsys.files).To(HaveLen(2))
+			Expect(fsys.files[filepath.Join(destination, "some-dir", "some-file")].String()).To(Equal("some-content"))
+			Expect(fsys.files[filepath.Join(destination, "some-dir", "some-file")].mode).To(Equal(os.FileMode(0644)))
+			Expect(fsys.files[filepath.Join(destination, "other-dir", "executable")].String()).To(Equal("executable-content"))
+			Expect(fsys.files[filepath.Join(destination, "other-dir", "executable")].mode).To(Equal(os.FileMode(0755)))
+
+			Expect(fsys.links).To(Equal(map[string]string{
+				filepath.Join(destination, "symlink"): "some-dir/some-file",
+			}))
+
+			_, err = os.Stat(destination)
+			Expect(err).To(MatchError(os.ErrNotExist))
+		})
+
+		context("failure cases", func() {
+			context("when the file system cannot create a file", func() {
+				it.Before(func() {
+					fsys.createErr = errors.New("no space left")
+				})
+
+				it("returns an error", func() {
+					err := tarArchive.DecompressTo(fsys, "/some-destination")
+					Expect(err).To(MatchError("failed to create archived file: no space left"))
+				})
+			})
+		})
+	})
+
+	context("Validate", func() {
+		var buffer *bytes.Buffer
+
+		it.Before(func() {
+			buffer = bytes.NewBuffer(nil)
+			tw := tar.NewWriter(buffer)
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "some-dir", Mode: 0755, Typeflag: tar.TypeDir})).To(Succeed())
+			Expect(tw.WriteHeader(&tar.Header{Name: "some-dir/", Mode: 0755, Typeflag: tar.TypeDir})).To(Succeed())
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "some-dir/some-file", Mode: 0644, Size: int64(len("some-content"))})).To(Succeed())
+			_, err := tw.Write([]byte("some-content"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "symlink", Mode: 0777, Typeflag: tar.TypeSymlink, Linkname: "some-dir/some-file"})).To(Succeed())
+
+			Expect(tw.Close()).To(Succeed())
+		})
+
+		it("succeeds for a well-formed archive", func() {
+			Expect(vacation.NewTarArchive(buffer).Validate()).To(Succeed())
+		})
+
+		context("failure cases", func() {
+			context("when an entry would be extracted outside of the destination", func() {
+				it.Before(func() {
+					buffer = bytes.NewBuffer(nil)
+					tw := tar.NewWriter(buffer)
+
+					Expect(tw.WriteHeader(&tar.Header{Name: "../some-file", Mode: 0644, Size: int64(len("some-content"))})).To(Succeed())
+					_, err := tw.Write([]byte("some-content"))
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(tw.Close()).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					err := vacation.NewTarArchive(buffer).Validate()
+					Expect(err).To(MatchError(`illegal file path "../some-file": the file path does not occur within the destination directory`))
+				})
+			})
+
+			context("when the archive is truncated", func() {
+				it("returns an error", func() {
+					truncated := buffer.Bytes()[:512+len("some-content")/2]
+
+					err := vacation.NewTarArchive(bytes.NewReader(truncated)).Validate()
+					Expect(err).To(MatchError(ContainSubstring("failed to read tar response")))
+					Expect(err).To(MatchError(ContainSubstring("unexpected EOF")))
+				})
+			})
+
+			context("when the archive is truncated in the middle of a file", func() {
+				it("returns an error", func() {
+					truncated := buffer.Bytes()[:3*512+len("some-content")/2]
+
+					err := vacation.NewTarArchive(bytes.NewReader(truncated)).Validate()
+					Expect(err).To(MatchError(`failed to read tar entry "some-dir/some-file": unexpected EOF`))
+				})
+			})
+
+			context("when a file appears more than once", func() {
+				it.Before(func() {
+					buffer = bytes.NewBuffer(nil)
+					tw := tar.NewWriter(buffer)
+
+					for _, name := range []string{"some-file", "./some-file"} {
+						Expect(tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len("some-content"))})).To(Succeed())
+						_, err := tw.Write([]byte("some-content"))
+						Expect(err).NotTo(HaveOccurred())
+					}
+
+					Expect(tw.Close()).To(Succeed())
+				})
+
+				it("returns an error", func() {
+					err := vacation.NewTarArchive(buffer).Validate()
+					Expect(err).To(MatchError(`duplicate entry "./some-file"`))
+				})
+			})
+		})
+	})
+
+	context("WithStrictEntryTypes", func() {
+		var (
+			fsys       *memoryFS
+			tarArchive vacation.TarArchive
+		)
+
+		it.Before(func() {
+			buffer := bytes.NewBuffer(nil)
+			tw := tar.NewWriter(buffer)
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "some-file", Mode: 0644, Size: int64(len("some-content"))})).To(Succeed())
+			_, err := tw.Write([]byte("some-content"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "dev/null", Mode: 0666, Typeflag: tar.TypeChar, Devmajor: 1, Devminor: 3})).To(Succeed())
+
+			Expect(tw.Close()).To(Succeed())
+
+			tarArchive = vacation.NewTarArchive(bytes.NewReader(buffer.Bytes()))
+			fsys = newMemoryFS()
+		})
+
+		it("skips unsupported entries by default", func() {
+			err := tarArchive.DecompressTo(fsys, "/some-destination")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fsys.files).To(HaveLen(1))
+			Expect(fsys.files).To(HaveKey(filepath.Join("/some-destination", "some-file")))
+		})
+
+		context("when strict entry types are enabled", func() {
+			it("returns an error naming the entry and its type", func() {
+				err := tarArchive.WithStrictEntryTypes(true).DecompressTo(fsys, "/some-destination")
+				Expect(err).To(MatchError("failed to extract dev/null: unsupported entry type character device"))
+			})
+		})
+	})
+
+	context("WithConcurrencyLimiter", func() {
+		var archive []byte
+
+		it.Before(func() {
+			buffer := bytes.NewBuffer(nil)
+			tw := tar.NewWriter(buffer)
+
+			for _, name := range []string{"first-file", "second-file", "third-file"} {
+				Expect(tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len("some-content"))})).To(Succeed())
+				_, err := tw.Write([]byte("some-content"))
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(tw.Close()).To(Succeed())
+
+			archive = buffer.Bytes()
+		})
+
+		it("bounds the number of extractions writing concurrently", func() {
+			limiter := make(chan struct{}, 2)
+			tracker := &concurrencyTracker{}
+
+			var wg sync.WaitGroup
+			errs := make(chan error, 6)
+			for i := 0; i < 6; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					fsys := trackingFS{memoryFS: newMemoryFS(), tracker: tracker}
+					errs <- vacation.NewTarArchive(bytes.NewReader(archive)).WithConcurrencyLimiter(limiter).DecompressTo(fsys, "/some-destination")
+				}()
+			}
+			wg.Wait()
+			close(errs)
+
+			for err := range errs {
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(tracker.max).To(BeNumerically(">", 0))
+			Expect(tracker.max).To(BeNumerically("<=", 2))
+			Expect(limiter).To(BeEmpty())
+		})
+	})
+}
+
+// A concurrencyTracker records the highest number of files that were being
+// written at the same time.
+type concurrencyTracker struct {
+	mutex  sync.Mutex
+	active int
+	max    int
+}
+
+func (c *concurrencyTracker) start() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.active++
+	if c.active > c.max {
+		c.max = c.active
+	}
+}
+
+func (c *concurrencyTracker) done() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.active--
+}
+
+// A trackingFS is a memoryFS that reports each file it creates to a shared
+// concurrencyTracker, holding the file open briefly so that overlapping
+// extractions are observable.
+type trackingFS struct {
+	*memoryFS
+	tracker *concurrencyTracker
+}
+
+func (t trackingFS) Create(path string, perm os.FileMode) (io.WriteCloser, error) {
+	file, err := t.memoryFS.Create(path, perm)
+	if err != nil {
+		return nil, err
+	}
+
+	t.tracker.start()
+	time.Sleep(10 * time.Millisecond)
+
+	return trackingFile{WriteCloser: file, tracker: t.tracker}, nil
+}
+
+type trackingFile struct {
+	io.WriteCloser
+	tracker *concurrencyTracker
+}
+
+func (f trackingFile) Close() error {
+	f.tracker.done()
+	return f.WriteCloser.Close()
+}
+
+type memoryFile struct {
+	bytes.Buffer
+	mode os.FileMode
+}
+
+func (f *memoryFile) Close() error {
+	return nil
+}
+
+type memoryFS struct {
+	dirs      map[string]os.FileMode
+	files     map[string]*memoryFile
+	links     map[string]string
+	createErr error
+}
+
+func newMemoryFS() *memoryFS {
+	return &memoryFS{
+		dirs:  map[string]os.FileMode{},
+		files: map[string]*memoryFile{},
+		links: map[string]string{},
+	}
+}
+
+func (m *memoryFS) MkdirAll(path string, perm os.FileMode) error {
+	m.dirs[path] = perm
+	return nil
+}
+
+func (m *memoryFS) Create(path string, perm os.FileMode) (io.WriteCloser, error) {
+	if m.createErr != nil {
+		return nil, m.createErr
+	}
+
+	file := &memoryFile{mode: perm}
+	m.files[path] = file
+
+	return file, nil
+}
+
+func (m *memoryFS) Symlink(oldname, newname string) error {
+	m.links[newname] = oldname
+	return nil
+}
+
+type sparseChunk struct {
+	offset int64
+	data   string
+}
+
+// gnuSparseArchive builds a tar archive containing a single file in the old
+// GNU sparse format, which archive/tar can read but not write. It supports up
+// to the four sparse map entries that fit in the header block.
+func gnuSparseArchive(name string, realSize int64, chunks []sparseChunk) []byte {
+	header := make([]byte, 512)
+
+	var data []byte
+	for _, chunk := range chunks {
+		data = append(data, chunk.data...)
+	}
+
+	copy(header[0:100], name)
+	copy(header[100:108], fmt.Sprintf("%07o\x00", 0644))
+	copy(header[108:116], fmt.Sprintf("%07o\x00", 0))
+	copy(header[116:124], fmt.Sprintf("%07o\x00", 0))
+	copy(header[124:136], fmt.Sprintf("%011o\x00", len(data)))
+	copy(header[136:148], fmt.Sprintf("%011o\x00", 0))
+	header[156] = tar.TypeGNUSparse
+	copy(header[257:265], "ustar  \x00")
+
+	for i, chunk := range chunks {
+		entry := 386 + i*24
+		copy(header[entry:entry+12], fmt.Sprintf("%011o\x00", chunk.offset))
+		copy(header[entry+12:entry+24], fmt.Sprintf("%011o\x00", len(chunk.data)))
+	}
+	copy(header[483:495], fmt.Sprintf("%011o\x00", realSize))
+
+	copy(header[148:156], "        ")
+	var checksum int
+	for _, b := range header {
+		checksum += int(b)
+	}
+	copy(header[148:156], fmt.Sprintf("%06o\x00 ", checksum))
+
+	content := make([]byte, (len(data)+511)/512*512)
+	copy(content, data)
+
+	archive := append(header, content...)
+	return append(archive, make([]byte, 1024)...)
 }
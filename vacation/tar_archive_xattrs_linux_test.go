@@ -0,0 +1,103 @@
+package vacation_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/vacation"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestVacationXattrs(t *testing.T) {
+	suite := spec.New("vacation/xattrs", spec.Report(report.Terminal{}))
+	suite("TarArchive", testTarArchiveXattrs)
+	suite.Run(t)
+}
+
+func testTarArchiveXattrs(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+
+		tempDir    string
+		tarArchive vacation.TarArchive
+	)
+
+	it.Before(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "vacation")
+		Expect(err).NotTo(HaveOccurred())
+
+		buffer := bytes.NewBuffer(nil)
+		tw := tar.NewWriter(buffer)
+
+		Expect(tw.WriteHeader(&tar.Header{
+			Name:     "some-dir",
+			Mode:     0755,
+			Typeflag: tar.TypeDir,
+			Format:   tar.FormatPAX,
+			PAXRecords: map[string]string{
+				"SCHILY.xattr.user.some-dir-attr": "some-dir-value",
+			},
+		})).To(Succeed())
+
+		Expect(tw.WriteHeader(&tar.Header{
+			Name:   "some-dir/some-file",
+			Mode:   0755,
+			Size:   int64(len("some-content")),
+			Format: tar.FormatPAX,
+			PAXRecords: map[string]string{
+				"SCHILY.xattr.user.some-attr": "some-value",
+			},
+		})).To(Succeed())
+		_, err = tw.Write([]byte("some-content"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(tw.Close()).To(Succeed())
+
+		tarArchive = vacation.NewTarArchive(bytes.NewReader(buffer.Bytes()))
+	})
+
+	it.After(func() {
+		Expect(os.RemoveAll(tempDir)).To(Succeed())
+	})
+
+	getxattr := func(path, attr string) (string, error) {
+		value := make([]byte, 256)
+		n, err := syscall.Getxattr(path, attr, value)
+		if err != nil {
+			return "", err
+		}
+
+		return string(value[:n]), nil
+	}
+
+	it("restores the extended attributes from the PAX records", func() {
+		err := tarArchive.WithXattrs(true).Decompress(tempDir)
+		Expect(err).NotTo(HaveOccurred())
+
+		value, err := getxattr(filepath.Join(tempDir, "some-dir", "some-file"), "user.some-attr")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal("some-value"))
+
+		value, err = getxattr(filepath.Join(tempDir, "some-dir"), "user.some-dir-attr")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal("some-dir-value"))
+	})
+
+	context("when xattrs are not enabled", func() {
+		it("does not restore the extended attributes", func() {
+			err := tarArchive.Decompress(tempDir)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = getxattr(filepath.Join(tempDir, "some-dir", "some-file"), "user.some-attr")
+			Expect(err).To(MatchError(syscall.ENODATA))
+		})
+	})
+}
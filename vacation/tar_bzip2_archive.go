@@ -3,23 +3,79 @@ package vacation
 import (
 	"compress/bzip2"
 	"io"
+	"os"
 )
 
 // A TarBzip2Archive decompresses bzip2 files from an input stream.
 type TarBzip2Archive struct {
 	reader     io.Reader
+	path       string
 	components int
+	archiveOptions
 }
 
 // NewTarBzip2Archive returns a new Bzip2Archive that reads from inputReader.
 func NewTarBzip2Archive(inputReader io.Reader) TarBzip2Archive {
-	return TarBzip2Archive{reader: inputReader}
+	return TarBzip2Archive{
+		reader:         inputReader,
+		archiveOptions: archiveOptions{mode: os.ModePerm},
+	}
+}
+
+// NewTarBzip2ArchiveFromFile returns a new TarBzip2Archive that reads from the
+// file at the given path. The file is opened each time the archive is read and
+// is closed once reading completes.
+func NewTarBzip2ArchiveFromFile(path string) TarBzip2Archive {
+	archive := NewTarBzip2Archive(nil)
+	archive.path = path
+	return archive
 }
 
 // Decompress reads from TarBzip2Archive and writes files into the destination
 // specified.
 func (tbz TarBzip2Archive) Decompress(destination string) error {
-	return NewTarArchive(bzip2.NewReader(tbz.reader)).StripComponents(tbz.components).Decompress(destination)
+	return tbz.DecompressTo(OSFileSystem{}, destination)
+}
+
+// DecompressTo reads from TarBzip2Archive and writes files into the destination
+// specified on the given file system.
+func (tbz TarBzip2Archive) DecompressTo(fsys WritableFS, destination string) error {
+	source, closeSource, err := openSource(tbz.reader, tbz.path)
+	if err != nil {
+		return err
+	}
+	defer closeSource()
+	tbz.reader = source
+
+	return NewTarArchive(bzip2.NewReader(tbz.reader)).StripComponents(tbz.components).withOptions(tbz.archiveOptions).DecompressTo(fsys, destination)
+}
+
+// List reads from TarBzip2Archive and returns the entries it contains without
+// writing anything to disk.
+func (tbz TarBzip2Archive) List() ([]Entry, error) {
+	source, closeSource, err := openSource(tbz.reader, tbz.path)
+	if err != nil {
+		return nil, err
+	}
+	defer closeSource()
+	tbz.reader = source
+
+	return NewTarArchive(bzip2.NewReader(tbz.reader)).List()
+}
+
+// Validate reads every entry of the TarBzip2Archive without writing anything to
+// disk. It returns an error if the stream is truncated or malformed, if an
+// entry would be extracted outside of the destination directory, or if a file
+// appears more than once.
+func (tbz TarBzip2Archive) Validate() error {
+	source, closeSource, err := openSource(tbz.reader, tbz.path)
+	if err != nil {
+		return err
+	}
+	defer closeSource()
+	tbz.reader = source
+
+	return NewTarArchive(bzip2.NewReader(tbz.reader)).Validate()
 }
 
 // StripComponents behaves like the --strip-components flag on tar command
@@ -28,3 +84,79 @@ func (tbz TarBzip2Archive) StripComponents(components int) TarBzip2Archive {
 	tbz.components = components
 	return tbz
 }
+
+// withOptions replaces the shared extraction options with the given options.
+func (tbz TarBzip2Archive) withOptions(options archiveOptions) TarBzip2Archive {
+	tbz.archiveOptions = options
+	return tbz
+}
+
+// WithDestinationMode sets the permissions used for directories that are
+// created implicitly because a file in the archive is nested inside of them.
+// Defaults to os.ModePerm.
+func (tbz TarBzip2Archive) WithDestinationMode(mode os.FileMode) TarBzip2Archive {
+	tbz.mode = mode
+	return tbz
+}
+
+// WithFlatten discards the directory structure of the archive when enabled,
+// extracting every file directly into the destination. Decompression fails if
+// two files in the archive share the same name.
+func (tbz TarBzip2Archive) WithFlatten(flatten bool) TarBzip2Archive {
+	tbz.flatten = flatten
+	return tbz
+}
+
+// WithXattrs applies the extended attributes recorded in the PAX headers of
+// the archive to the extracted files when enabled. This is a no-op on
+// platforms that do not support extended attributes.
+func (tbz TarBzip2Archive) WithXattrs(xattrs bool) TarBzip2Archive {
+	tbz.xattrs = xattrs
+	return tbz
+}
+
+// WithConcurrencyLimiter bounds the number of extractions that write to disk
+// at the same time. Each extraction holds a slot on the given channel while it
+// writes files, so the capacity of the channel is the maximum number of
+// concurrent extractions among all archives sharing it. A nil channel imposes
+// no limit.
+func (tbz TarBzip2Archive) WithConcurrencyLimiter(limiter chan struct{}) TarBzip2Archive {
+	tbz.limiter = limiter
+	return tbz
+}
+
+// WithStrictEntryTypes causes decompression to fail when the archive contains
+// an entry whose type is not supported, such as a hard link or device node,
+// rather than silently skipping it.
+func (tbz TarBzip2Archive) WithStrictEntryTypes(strict bool) TarBzip2Archive {
+	tbz.strict = strict
+	return tbz
+}
+
+// WithRootMode sets the permissions of the destination directory once
+// decompression has completed, regardless of the permissions recorded in the
+// archive. It is only applied when decompressing onto the host file system.
+func (tbz TarBzip2Archive) WithRootMode(mode os.FileMode) TarBzip2Archive {
+	tbz.rootMode = mode
+	return tbz
+}
+
+// WithNormalizedModes sets the permissions of every extracted regular file to
+// fileMode and of every extracted directory to dirMode once decompression has
+// completed, regardless of the permissions recorded in the archive or the
+// umask, so that the extracted files are reproducible. A mode of zero leaves
+// the permissions of that kind of entry unchanged. It is only applied when
+// decompressing onto the host file system.
+func (tbz TarBzip2Archive) WithNormalizedModes(fileMode, dirMode os.FileMode) TarBzip2Archive {
+	tbz.modes.file = fileMode
+	tbz.modes.dir = dirMode
+	return tbz
+}
+
+// WithPreservedExecutables keeps files that are executable in the archive
+// executable when their modes are normalized with WithNormalizedModes, by
+// adding an execute bit for each read bit of the normalized file mode.
+func (tbz TarBzip2Archive) WithPreservedExecutables(preserve bool) TarBzip2Archive {
+	tbz.modes.executables = preserve
+	return tbz
+}
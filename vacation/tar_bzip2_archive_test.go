@@ -117,4 +117,51 @@ func testTarBzip2Archive(t *testing.T, context spec.G, it spec.S) {
 			Expect(filepath.Join(tempDir, "some-other-dir", "some-file")).To(BeARegularFile())
 		})
 	})
+	context("List", func() {
+		var tarBzip2Archive vacation.TarBzip2Archive
+
+		it.Before(func() {
+			buffer := bytes.NewBuffer(nil)
+			bz, err := dsnetBzip2.NewWriter(buffer, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			tw := tar.NewWriter(bz)
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "some-dir/", Mode: 0755, Typeflag: tar.TypeDir})).To(Succeed())
+			_, err = tw.Write(nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "some-dir/some-file", Mode: 0644, Size: int64(len("some-file"))})).To(Succeed())
+			_, err = tw.Write([]byte("some-file"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "symlink", Mode: 0777, Typeflag: tar.TypeSymlink, Linkname: "some-dir/some-file"})).To(Succeed())
+			_, err = tw.Write(nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tw.Close()).To(Succeed())
+			Expect(bz.Close()).To(Succeed())
+
+			tarBzip2Archive = vacation.NewTarBzip2Archive(bytes.NewReader(buffer.Bytes()))
+		})
+
+		it("lists the entries in the archive", func() {
+			entries, err := tarBzip2Archive.List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(Equal([]vacation.Entry{
+				{Name: "some-dir/", Mode: os.ModeDir | 0755, Typeflag: tar.TypeDir},
+				{Name: "some-dir/some-file", Size: 9, Mode: 0644, Typeflag: tar.TypeReg},
+				{Name: "symlink", Mode: os.ModeSymlink | 0777, Typeflag: tar.TypeSymlink, Linkname: "some-dir/some-file"},
+			}))
+		})
+
+		context("failure cases", func() {
+			context("when the input is not a bzip2 stream", func() {
+				it("returns an error", func() {
+					_, err := vacation.NewTarBzip2Archive(bytes.NewBuffer([]byte(`something`))).List()
+					Expect(err).To(MatchError(ContainSubstring("failed to read tar response")))
+				})
+			})
+		})
+	})
 }
@@ -4,28 +4,120 @@ import (
 	"compress/gzip"
 	"fmt"
 	"io"
+	"os"
+	"sync"
 )
 
+// gzipReaders holds gzip readers for reuse so that decompressing many archives
+// does not allocate a new reader and its decompression window each time.
+var gzipReaders sync.Pool
+
 // A TarGzipArchive decompresses gziped tar files from an input stream.
 type TarGzipArchive struct {
 	reader     io.Reader
+	path       string
 	components int
+	archiveOptions
 }
 
 // NewTarGzipArchive returns a new TarGzipArchive that reads from inputReader.
 func NewTarGzipArchive(inputReader io.Reader) TarGzipArchive {
-	return TarGzipArchive{reader: inputReader}
+	return TarGzipArchive{
+		reader:         inputReader,
+		archiveOptions: archiveOptions{mode: os.ModePerm},
+	}
+}
+
+// NewTarGzipArchiveFromFile returns a new TarGzipArchive that reads from the
+// file at the given path. The file is opened each time the archive is read and
+// is closed once reading completes.
+func NewTarGzipArchiveFromFile(path string) TarGzipArchive {
+	archive := NewTarGzipArchive(nil)
+	archive.path = path
+	return archive
 }
 
 // Decompress reads from TarGzipArchive and writes files into the destination
 // specified.
 func (gz TarGzipArchive) Decompress(destination string) error {
-	gzr, err := gzip.NewReader(gz.reader)
+	return gz.DecompressTo(OSFileSystem{}, destination)
+}
+
+// DecompressTo reads from TarGzipArchive and writes files into the destination
+// specified on the given file system.
+func (gz TarGzipArchive) DecompressTo(fsys WritableFS, destination string) error {
+	source, closeSource, err := openSource(gz.reader, gz.path)
+	if err != nil {
+		return err
+	}
+	defer closeSource()
+	gz.reader = source
+
+	gzr, err := getGzipReader(gz.reader)
+	if err != nil {
+		return fmt.Errorf("failed to create gzip reader: %w", err)
+	}
+	defer gzipReaders.Put(gzr)
+
+	return NewTarArchive(gzr).StripComponents(gz.components).withOptions(gz.archiveOptions).DecompressTo(fsys, destination)
+}
+
+// List reads from TarGzipArchive and returns the entries it contains without
+// writing anything to disk.
+func (gz TarGzipArchive) List() ([]Entry, error) {
+	source, closeSource, err := openSource(gz.reader, gz.path)
+	if err != nil {
+		return nil, err
+	}
+	defer closeSource()
+	gz.reader = source
+
+	gzr, err := getGzipReader(gz.reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+	}
+	defer gzipReaders.Put(gzr)
+
+	return NewTarArchive(gzr).List()
+}
+
+// getGzipReader returns a gzip reader from the pool that has been reset to read
+// from the given reader, or a new gzip reader if the pool is empty. Callers
+// should return the reader to the pool once they are done with it.
+func getGzipReader(reader io.Reader) (*gzip.Reader, error) {
+	gzr, ok := gzipReaders.Get().(*gzip.Reader)
+	if !ok {
+		return gzip.NewReader(reader)
+	}
+
+	err := gzr.Reset(reader)
+	if err != nil {
+		gzipReaders.Put(gzr)
+		return nil, err
+	}
+
+	return gzr, nil
+}
+
+// Validate reads every entry of the TarGzipArchive without writing anything to
+// disk. It returns an error if the stream is truncated or malformed, if an
+// entry would be extracted outside of the destination directory, or if a file
+// appears more than once.
+func (gz TarGzipArchive) Validate() error {
+	source, closeSource, err := openSource(gz.reader, gz.path)
+	if err != nil {
+		return err
+	}
+	defer closeSource()
+	gz.reader = source
+
+	gzr, err := getGzipReader(gz.reader)
 	if err != nil {
 		return fmt.Errorf("failed to create gzip reader: %w", err)
 	}
+	defer gzipReaders.Put(gzr)
 
-	return NewTarArchive(gzr).StripComponents(gz.components).Decompress(destination)
+	return NewTarArchive(gzr).Validate()
 }
 
 // StripComponents behaves like the --strip-components flag on tar command
@@ -34,3 +126,79 @@ func (gz TarGzipArchive) StripComponents(components int) TarGzipArchive {
 	gz.components = components
 	return gz
 }
+
+// withOptions replaces the shared extraction options with the given options.
+func (gz TarGzipArchive) withOptions(options archiveOptions) TarGzipArchive {
+	gz.archiveOptions = options
+	return gz
+}
+
+// WithDestinationMode sets the permissions used for directories that are
+// created implicitly because a file in the archive is nested inside of them.
+// Defaults to os.ModePerm.
+func (gz TarGzipArchive) WithDestinationMode(mode os.FileMode) TarGzipArchive {
+	gz.mode = mode
+	return gz
+}
+
+// WithFlatten discards the directory structure of the archive when enabled,
+// extracting every file directly into the destination. Decompression fails if
+// two files in the archive share the same name.
+func (gz TarGzipArchive) WithFlatten(flatten bool) TarGzipArchive {
+	gz.flatten = flatten
+	return gz
+}
+
+// WithXattrs applies the extended attributes recorded in the PAX headers of
+// the archive to the extracted files when enabled. This is a no-op on
+// platforms that do not support extended attributes.
+func (gz TarGzipArchive) WithXattrs(xattrs bool) TarGzipArchive {
+	gz.xattrs = xattrs
+	return gz
+}
+
+// WithConcurrencyLimiter bounds the number of extractions that write to disk
+// at the same time. Each extraction holds a slot on the given channel while it
+// writes files, so the capacity of the channel is the maximum number of
+// concurrent extractions among all archives sharing it. A nil channel imposes
+// no limit.
+func (gz TarGzipArchive) WithConcurrencyLimiter(limiter chan struct{}) TarGzipArchive {
+	gz.limiter = limiter
+	return gz
+}
+
+// WithStrictEntryTypes causes decompression to fail when the archive contains
+// an entry whose type is not supported, such as a hard link or device node,
+// rather than silently skipping it.
+func (gz TarGzipArchive) WithStrictEntryTypes(strict bool) TarGzipArchive {
+	gz.strict = strict
+	return gz
+}
+
+// WithRootMode sets the permissions of the destination directory once
+// decompression has completed, regardless of the permissions recorded in the
+// archive. It is only applied when decompressing onto the host file system.
+func (gz TarGzipArchive) WithRootMode(mode os.FileMode) TarGzipArchive {
+	gz.rootMode = mode
+	return gz
+}
+
+// WithNormalizedModes sets the permissions of every extracted regular file to
+// fileMode and of every extracted directory to dirMode once decompression has
+// completed, regardless of the permissions recorded in the archive or the
+// umask, so that the extracted files are reproducible. A mode of zero leaves
+// the permissions of that kind of entry unchanged. It is only applied when
+// decompressing onto the host file system.
+func (gz TarGzipArchive) WithNormalizedModes(fileMode, dirMode os.FileMode) TarGzipArchive {
+	gz.modes.file = fileMode
+	gz.modes.dir = dirMode
+	return gz
+}
+
+// WithPreservedExecutables keeps files that are executable in the archive
+// executable when their modes are normalized with WithNormalizedModes, by
+// adding an execute bit for each read bit of the normalized file mode.
+func (gz TarGzipArchive) WithPreservedExecutables(preserve bool) TarGzipArchive {
+	gz.modes.executables = preserve
+	return gz
+}
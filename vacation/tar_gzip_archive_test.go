@@ -23,6 +23,7 @@ func testTarGzipArchive(t *testing.T, context spec.G, it spec.S) {
 	context("Decompress", func() {
 		var (
 			tempDir        string
+			content        []byte
 			tarGzipArchive vacation.TarGzipArchive
 		)
 
@@ -61,7 +62,8 @@ func testTarGzipArchive(t *testing.T, context spec.G, it spec.S) {
 			Expect(tw.Close()).To(Succeed())
 			Expect(gw.Close()).To(Succeed())
 
-			tarGzipArchive = vacation.NewTarGzipArchive(bytes.NewReader(buffer.Bytes()))
+			content = buffer.Bytes()
+			tarGzipArchive = vacation.NewTarGzipArchive(bytes.NewReader(content))
 
 		})
 
@@ -111,6 +113,56 @@ func testTarGzipArchive(t *testing.T, context spec.G, it spec.S) {
 			Expect(filepath.Join(tempDir, "some-other-dir", "some-file")).To(BeARegularFile())
 		})
 
+		it("produces identical output when gzip readers are reused", func() {
+			tree := func(dir string) map[string]string {
+				files := map[string]string{}
+				err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+					if err != nil {
+						return err
+					}
+
+					rel, err := filepath.Rel(dir, path)
+					if err != nil {
+						return err
+					}
+
+					switch {
+					case info.Mode()&os.ModeSymlink != 0:
+						link, err := os.Readlink(path)
+						if err != nil {
+							return err
+						}
+						files[rel] = "-> " + link
+					case info.Mode().IsRegular():
+						contents, err := os.ReadFile(path)
+						if err != nil {
+							return err
+						}
+						files[rel] = string(contents)
+					default:
+						files[rel] = info.Mode().String()
+					}
+
+					return nil
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				return files
+			}
+
+			first := filepath.Join(tempDir, "first-extraction")
+			Expect(vacation.NewTarGzipArchive(bytes.NewReader(content)).Decompress(first)).To(Succeed())
+
+			err := vacation.NewTarGzipArchive(bytes.NewBuffer([]byte(`something`))).Decompress(filepath.Join(tempDir, "failed-extraction"))
+			Expect(err).To(MatchError(ContainSubstring("failed to create gzip reader")))
+
+			for i := 0; i < 3; i++ {
+				next := filepath.Join(tempDir, fmt.Sprintf("extraction-%d", i))
+				Expect(vacation.NewTarGzipArchive(bytes.NewReader(content)).Decompress(next)).To(Succeed())
+				Expect(tree(next)).To(Equal(tree(first)))
+			}
+		})
+
 		context("failure cases", func() {
 			context("when it fails to create a grip reader", func() {
 				it("returns an error", func() {
@@ -122,4 +174,95 @@ func testTarGzipArchive(t *testing.T, context spec.G, it spec.S) {
 			})
 		})
 	})
+	context("List", func() {
+		var tarGzipArchive vacation.TarGzipArchive
+
+		it.Before(func() {
+			var err error
+
+			buffer := bytes.NewBuffer(nil)
+			gw := gzip.NewWriter(buffer)
+			tw := tar.NewWriter(gw)
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "some-dir/", Mode: 0755, Typeflag: tar.TypeDir})).To(Succeed())
+			_, err = tw.Write(nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "some-dir/some-file", Mode: 0644, Size: int64(len("some-file"))})).To(Succeed())
+			_, err = tw.Write([]byte("some-file"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "symlink", Mode: 0777, Typeflag: tar.TypeSymlink, Linkname: "some-dir/some-file"})).To(Succeed())
+			_, err = tw.Write(nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tw.Close()).To(Succeed())
+			Expect(gw.Close()).To(Succeed())
+
+			tarGzipArchive = vacation.NewTarGzipArchive(bytes.NewReader(buffer.Bytes()))
+		})
+
+		it("lists the entries in the archive", func() {
+			entries, err := tarGzipArchive.List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(Equal([]vacation.Entry{
+				{Name: "some-dir/", Mode: os.ModeDir | 0755, Typeflag: tar.TypeDir},
+				{Name: "some-dir/some-file", Size: 9, Mode: 0644, Typeflag: tar.TypeReg},
+				{Name: "symlink", Mode: os.ModeSymlink | 0777, Typeflag: tar.TypeSymlink, Linkname: "some-dir/some-file"},
+			}))
+		})
+
+		context("failure cases", func() {
+			context("when it fails to create a gzip reader", func() {
+				it("returns an error", func() {
+					_, err := vacation.NewTarGzipArchive(bytes.NewBuffer([]byte(`something`))).List()
+					Expect(err).To(MatchError(ContainSubstring("failed to create gzip reader")))
+				})
+			})
+		})
+	})
+}
+
+func BenchmarkTarGzipArchiveDecompress(b *testing.B) {
+	buffer := bytes.NewBuffer(nil)
+	gw := gzip.NewWriter(buffer)
+	tw := tar.NewWriter(gw)
+
+	for i := 0; i < 10; i++ {
+		name := fmt.Sprintf("file-%d", i)
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(name))})
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		_, err = tw.Write([]byte(name))
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	err := tw.Close()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	err = gw.Close()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	destinationDir, err := os.MkdirTemp("", "destination")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.RemoveAll(destinationDir)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err = vacation.NewTarGzipArchive(bytes.NewReader(buffer.Bytes())).Decompress(filepath.Join(destinationDir, fmt.Sprintf("destination-%d", i)))
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
 }
@@ -0,0 +1,163 @@
+package vacation
+
+import (
+	"io"
+	"os"
+
+	"github.com/pierrec/lz4/v4"
+)
+
+// A TarLZ4Archive decompresses lz4 tar files from an input stream.
+type TarLZ4Archive struct {
+	reader     io.Reader
+	path       string
+	components int
+	archiveOptions
+}
+
+// NewTarLZ4Archive returns a new TarLZ4Archive that reads from inputReader.
+func NewTarLZ4Archive(inputReader io.Reader) TarLZ4Archive {
+	return TarLZ4Archive{
+		reader:         inputReader,
+		archiveOptions: archiveOptions{mode: os.ModePerm},
+	}
+}
+
+// NewTarLZ4ArchiveFromFile returns a new TarLZ4Archive that reads from the file
+// at the given path. The file is opened each time the archive is read and is
+// closed once reading completes.
+func NewTarLZ4ArchiveFromFile(path string) TarLZ4Archive {
+	archive := NewTarLZ4Archive(nil)
+	archive.path = path
+	return archive
+}
+
+// Decompress reads from TarLZ4Archive and writes files into the destination
+// specified.
+func (tlz TarLZ4Archive) Decompress(destination string) error {
+	return tlz.DecompressTo(OSFileSystem{}, destination)
+}
+
+// DecompressTo reads from TarLZ4Archive and writes files into the destination
+// specified on the given file system.
+func (tlz TarLZ4Archive) DecompressTo(fsys WritableFS, destination string) error {
+	source, closeSource, err := openSource(tlz.reader, tlz.path)
+	if err != nil {
+		return err
+	}
+	defer closeSource()
+	tlz.reader = source
+
+	return NewTarArchive(lz4.NewReader(tlz.reader)).StripComponents(tlz.components).withOptions(tlz.archiveOptions).DecompressTo(fsys, destination)
+}
+
+// List reads from TarLZ4Archive and returns the entries it contains without
+// writing anything to disk.
+func (tlz TarLZ4Archive) List() ([]Entry, error) {
+	source, closeSource, err := openSource(tlz.reader, tlz.path)
+	if err != nil {
+		return nil, err
+	}
+	defer closeSource()
+	tlz.reader = source
+
+	return NewTarArchive(lz4.NewReader(tlz.reader)).List()
+}
+
+// Validate reads every entry of the TarLZ4Archive without writing anything to
+// disk. It returns an error if the stream is truncated or malformed, if an
+// entry would be extracted outside of the destination directory, or if a file
+// appears more than once.
+func (tlz TarLZ4Archive) Validate() error {
+	source, closeSource, err := openSource(tlz.reader, tlz.path)
+	if err != nil {
+		return err
+	}
+	defer closeSource()
+	tlz.reader = source
+
+	return NewTarArchive(lz4.NewReader(tlz.reader)).Validate()
+}
+
+// StripComponents behaves like the --strip-components flag on tar command
+// removing the first n levels from the final decompression destination.
+func (tlz TarLZ4Archive) StripComponents(components int) TarLZ4Archive {
+	tlz.components = components
+	return tlz
+}
+
+// withOptions replaces the shared extraction options with the given options.
+func (tlz TarLZ4Archive) withOptions(options archiveOptions) TarLZ4Archive {
+	tlz.archiveOptions = options
+	return tlz
+}
+
+// WithDestinationMode sets the permissions used for directories that are
+// created implicitly because a file in the archive is nested inside of them.
+// Defaults to os.ModePerm.
+func (tlz TarLZ4Archive) WithDestinationMode(mode os.FileMode) TarLZ4Archive {
+	tlz.mode = mode
+	return tlz
+}
+
+// WithFlatten discards the directory structure of the archive when enabled,
+// extracting every file directly into the destination. Decompression fails if
+// two files in the archive share the same name.
+func (tlz TarLZ4Archive) WithFlatten(flatten bool) TarLZ4Archive {
+	tlz.flatten = flatten
+	return tlz
+}
+
+// WithXattrs applies the extended attributes recorded in the PAX headers of
+// the archive to the extracted files when enabled. This is a no-op on
+// platforms that do not support extended attributes.
+func (tlz TarLZ4Archive) WithXattrs(xattrs bool) TarLZ4Archive {
+	tlz.xattrs = xattrs
+	return tlz
+}
+
+// WithConcurrencyLimiter bounds the number of extractions that write to disk
+// at the same time. Each extraction holds a slot on the given channel while it
+// writes files, so the capacity of the channel is the maximum number of
+// concurrent extractions among all archives sharing it. A nil channel imposes
+// no limit.
+func (tlz TarLZ4Archive) WithConcurrencyLimiter(limiter chan struct{}) TarLZ4Archive {
+	tlz.limiter = limiter
+	return tlz
+}
+
+// WithStrictEntryTypes causes decompression to fail when the archive contains
+// an entry whose type is not supported, such as a hard link or device node,
+// rather than silently skipping it.
+func (tlz TarLZ4Archive) WithStrictEntryTypes(strict bool) TarLZ4Archive {
+	tlz.strict = strict
+	return tlz
+}
+
+// WithRootMode sets the permissions of the destination directory once
+// decompression has completed, regardless of the permissions recorded in the
+// archive. It is only applied when decompressing onto the host file system.
+func (tlz TarLZ4Archive) WithRootMode(mode os.FileMode) TarLZ4Archive {
+	tlz.rootMode = mode
+	return tlz
+}
+
+// WithNormalizedModes sets the permissions of every extracted regular file to
+// fileMode and of every extracted directory to dirMode once decompression has
+// completed, regardless of the permissions recorded in the archive or the
+// umask, so that the extracted files are reproducible. A mode of zero leaves
+// the permissions of that kind of entry unchanged. It is only applied when
+// decompressing onto the host file system.
+func (tlz TarLZ4Archive) WithNormalizedModes(fileMode, dirMode os.FileMode) TarLZ4Archive {
+	tlz.modes.file = fileMode
+	tlz.modes.dir = dirMode
+	return tlz
+}
+
+// WithPreservedExecutables keeps files that are executable in the archive
+// executable when their modes are normalized with WithNormalizedModes, by
+// adding an execute bit for each read bit of the normalized file mode.
+func (tlz TarLZ4Archive) WithPreservedExecutables(preserve bool) TarLZ4Archive {
+	tlz.modes.executables = preserve
+	return tlz
+}
@@ -0,0 +1,173 @@
+package vacation_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/vacation"
+	"github.com/pierrec/lz4/v4"
+	"github.com/sclevine/spec"
+
+	. "github.com/onsi/gomega"
+)
+
+func testTarLZ4Archive(t *testing.T, context spec.G, it spec.S) {
+	var (
+		Expect = NewWithT(t).Expect
+	)
+
+	context("Decompress", func() {
+		var (
+			tempDir       string
+			tarLZ4Archive vacation.TarLZ4Archive
+		)
+
+		it.Before(func() {
+			var err error
+			tempDir, err = os.MkdirTemp("", "vacation")
+			Expect(err).NotTo(HaveOccurred())
+
+			buffer := bytes.NewBuffer(nil)
+			lzw := lz4.NewWriter(buffer)
+			tw := tar.NewWriter(lzw)
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "some-dir", Mode: 0755, Typeflag: tar.TypeDir})).To(Succeed())
+			_, err = tw.Write(nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tw.WriteHeader(&tar.Header{Name: filepath.Join("some-dir", "some-other-dir"), Mode: 0755, Typeflag: tar.TypeDir})).To(Succeed())
+			_, err = tw.Write(nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			nestedFile := filepath.Join("some-dir", "some-other-dir", "some-file")
+			Expect(tw.WriteHeader(&tar.Header{Name: nestedFile, Mode: 0755, Size: int64(len(nestedFile))})).To(Succeed())
+			_, err = tw.Write([]byte(nestedFile))
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, file := range []string{"first", "second", "third"} {
+				Expect(tw.WriteHeader(&tar.Header{Name: file, Mode: 0755, Size: int64(len(file))})).To(Succeed())
+				_, err = tw.Write([]byte(file))
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "symlink", Mode: 0777, Size: int64(0), Typeflag: tar.TypeSymlink, Linkname: "first"})).To(Succeed())
+			_, err = tw.Write([]byte{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tw.Close()).To(Succeed())
+			Expect(lzw.Close()).To(Succeed())
+
+			tarLZ4Archive = vacation.NewTarLZ4Archive(bytes.NewReader(buffer.Bytes()))
+
+		})
+
+		it.After(func() {
+			Expect(os.RemoveAll(tempDir)).To(Succeed())
+		})
+
+		it("unpackages the archive into the path", func() {
+			var err error
+			err = tarLZ4Archive.Decompress(tempDir)
+			Expect(err).ToNot(HaveOccurred())
+
+			files, err := filepath.Glob(fmt.Sprintf("%s/*", tempDir))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(ConsistOf([]string{
+				filepath.Join(tempDir, "first"),
+				filepath.Join(tempDir, "second"),
+				filepath.Join(tempDir, "third"),
+				filepath.Join(tempDir, "some-dir"),
+				filepath.Join(tempDir, "symlink"),
+			}))
+
+			info, err := os.Stat(filepath.Join(tempDir, "first"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode()).To(Equal(os.FileMode(0755)))
+
+			Expect(filepath.Join(tempDir, "some-dir", "some-other-dir")).To(BeADirectory())
+			Expect(filepath.Join(tempDir, "some-dir", "some-other-dir", "some-file")).To(BeARegularFile())
+
+			data, err := os.ReadFile(filepath.Join(tempDir, "symlink"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(Equal([]byte(`first`)))
+		})
+
+		it("unpackages the archive into the path but also strips the first component", func() {
+			var err error
+			err = tarLZ4Archive.StripComponents(1).Decompress(tempDir)
+			Expect(err).ToNot(HaveOccurred())
+
+			files, err := filepath.Glob(fmt.Sprintf("%s/*", tempDir))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(ConsistOf([]string{
+				filepath.Join(tempDir, "some-other-dir"),
+			}))
+
+			Expect(filepath.Join(tempDir, "some-other-dir")).To(BeADirectory())
+			Expect(filepath.Join(tempDir, "some-other-dir", "some-file")).To(BeARegularFile())
+
+		})
+
+		context("failure cases", func() {
+			context("when the input is not an lz4 stream", func() {
+				it("returns an error", func() {
+					readyArchive := vacation.NewTarLZ4Archive(bytes.NewBuffer([]byte(`something`)))
+
+					err := readyArchive.Decompress(tempDir)
+					Expect(err).To(MatchError(ContainSubstring("failed to read tar response")))
+				})
+			})
+		})
+	})
+	context("List", func() {
+		var tarLZ4Archive vacation.TarLZ4Archive
+
+		it.Before(func() {
+			var err error
+
+			buffer := bytes.NewBuffer(nil)
+			lzw := lz4.NewWriter(buffer)
+			tw := tar.NewWriter(lzw)
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "some-dir/", Mode: 0755, Typeflag: tar.TypeDir})).To(Succeed())
+			_, err = tw.Write(nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "some-dir/some-file", Mode: 0644, Size: int64(len("some-file"))})).To(Succeed())
+			_, err = tw.Write([]byte("some-file"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "symlink", Mode: 0777, Typeflag: tar.TypeSymlink, Linkname: "some-dir/some-file"})).To(Succeed())
+			_, err = tw.Write(nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tw.Close()).To(Succeed())
+			Expect(lzw.Close()).To(Succeed())
+
+			tarLZ4Archive = vacation.NewTarLZ4Archive(bytes.NewReader(buffer.Bytes()))
+		})
+
+		it("lists the entries in the archive", func() {
+			entries, err := tarLZ4Archive.List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(Equal([]vacation.Entry{
+				{Name: "some-dir/", Mode: os.ModeDir | 0755, Typeflag: tar.TypeDir},
+				{Name: "some-dir/some-file", Size: 9, Mode: 0644, Typeflag: tar.TypeReg},
+				{Name: "symlink", Mode: os.ModeSymlink | 0777, Typeflag: tar.TypeSymlink, Linkname: "some-dir/some-file"},
+			}))
+		})
+
+		context("failure cases", func() {
+			context("when the input is not an lz4 stream", func() {
+				it("returns an error", func() {
+					_, err := vacation.NewTarLZ4Archive(bytes.NewBuffer([]byte(`something`))).List()
+					Expect(err).To(MatchError(ContainSubstring("failed to read tar response")))
+				})
+			})
+		})
+	})
+}
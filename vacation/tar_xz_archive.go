@@ -3,6 +3,7 @@ package vacation
 import (
 	"fmt"
 	"io"
+	"os"
 
 	"github.com/ulikunitz/xz"
 )
@@ -10,23 +11,88 @@ import (
 // A TarXZArchive decompresses xz tar files from an input stream.
 type TarXZArchive struct {
 	reader     io.Reader
+	path       string
 	components int
+	archiveOptions
 }
 
 // NewTarXZArchive returns a new TarXZArchive that reads from inputReader.
 func NewTarXZArchive(inputReader io.Reader) TarXZArchive {
-	return TarXZArchive{reader: inputReader}
+	return TarXZArchive{
+		reader:         inputReader,
+		archiveOptions: archiveOptions{mode: os.ModePerm},
+	}
+}
+
+// NewTarXZArchiveFromFile returns a new TarXZArchive that reads from the file
+// at the given path. The file is opened each time the archive is read and is
+// closed once reading completes.
+func NewTarXZArchiveFromFile(path string) TarXZArchive {
+	archive := NewTarXZArchive(nil)
+	archive.path = path
+	return archive
 }
 
 // Decompress reads from TarXZArchive and writes files into the destination
 // specified.
 func (txz TarXZArchive) Decompress(destination string) error {
+	return txz.DecompressTo(OSFileSystem{}, destination)
+}
+
+// DecompressTo reads from TarXZArchive and writes files into the destination
+// specified on the given file system.
+func (txz TarXZArchive) DecompressTo(fsys WritableFS, destination string) error {
+	source, closeSource, err := openSource(txz.reader, txz.path)
+	if err != nil {
+		return err
+	}
+	defer closeSource()
+	txz.reader = source
+
 	xzr, err := xz.NewReader(txz.reader)
 	if err != nil {
 		return fmt.Errorf("failed to create xz reader: %w", err)
 	}
 
-	return NewTarArchive(xzr).StripComponents(txz.components).Decompress(destination)
+	return NewTarArchive(xzr).StripComponents(txz.components).withOptions(txz.archiveOptions).DecompressTo(fsys, destination)
+}
+
+// List reads from TarXZArchive and returns the entries it contains without
+// writing anything to disk.
+func (txz TarXZArchive) List() ([]Entry, error) {
+	source, closeSource, err := openSource(txz.reader, txz.path)
+	if err != nil {
+		return nil, err
+	}
+	defer closeSource()
+	txz.reader = source
+
+	xzr, err := xz.NewReader(txz.reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create xz reader: %w", err)
+	}
+
+	return NewTarArchive(xzr).List()
+}
+
+// Validate reads every entry of the TarXZArchive without writing anything to
+// disk. It returns an error if the stream is truncated or malformed, if an
+// entry would be extracted outside of the destination directory, or if a file
+// appears more than once.
+func (txz TarXZArchive) Validate() error {
+	source, closeSource, err := openSource(txz.reader, txz.path)
+	if err != nil {
+		return err
+	}
+	defer closeSource()
+	txz.reader = source
+
+	xzr, err := xz.NewReader(txz.reader)
+	if err != nil {
+		return fmt.Errorf("failed to create xz reader: %w", err)
+	}
+
+	return NewTarArchive(xzr).Validate()
 }
 
 // StripComponents behaves like the --strip-components flag on tar command
@@ -35,3 +101,73 @@ func (txz TarXZArchive) StripComponents(components int) TarXZArchive {
 	txz.components = components
 	return txz
 }
+
+// WithDestinationMode sets the permissions used for directories that are
+// created implicitly because a file in the archive is nested inside of them.
+// Defaults to os.ModePerm.
+func (txz TarXZArchive) WithDestinationMode(mode os.FileMode) TarXZArchive {
+	txz.mode = mode
+	return txz
+}
+
+// WithFlatten discards the directory structure of the archive when enabled,
+// extracting every file directly into the destination. Decompression fails if
+// two files in the archive share the same name.
+func (txz TarXZArchive) WithFlatten(flatten bool) TarXZArchive {
+	txz.flatten = flatten
+	return txz
+}
+
+// WithXattrs applies the extended attributes recorded in the PAX headers of
+// the archive to the extracted files when enabled. This is a no-op on
+// platforms that do not support extended attributes.
+func (txz TarXZArchive) WithXattrs(xattrs bool) TarXZArchive {
+	txz.xattrs = xattrs
+	return txz
+}
+
+// WithConcurrencyLimiter bounds the number of extractions that write to disk
+// at the same time. Each extraction holds a slot on the given channel while it
+// writes files, so the capacity of the channel is the maximum number of
+// concurrent extractions among all archives sharing it. A nil channel imposes
+// no limit.
+func (txz TarXZArchive) WithConcurrencyLimiter(limiter chan struct{}) TarXZArchive {
+	txz.limiter = limiter
+	return txz
+}
+
+// WithStrictEntryTypes causes decompression to fail when the archive contains
+// an entry whose type is not supported, such as a hard link or device node,
+// rather than silently skipping it.
+func (txz TarXZArchive) WithStrictEntryTypes(strict bool) TarXZArchive {
+	txz.strict = strict
+	return txz
+}
+
+// WithRootMode sets the permissions of the destination directory once
+// decompression has completed, regardless of the permissions recorded in the
+// archive. It is only applied when decompressing onto the host file system.
+func (txz TarXZArchive) WithRootMode(mode os.FileMode) TarXZArchive {
+	txz.rootMode = mode
+	return txz
+}
+
+// WithNormalizedModes sets the permissions of every extracted regular file to
+// fileMode and of every extracted directory to dirMode once decompression has
+// completed, regardless of the permissions recorded in the archive or the
+// umask, so that the extracted files are reproducible. A mode of zero leaves
+// the permissions of that kind of entry unchanged. It is only applied when
+// decompressing onto the host file system.
+func (txz TarXZArchive) WithNormalizedModes(fileMode, dirMode os.FileMode) TarXZArchive {
+	txz.modes.file = fileMode
+	txz.modes.dir = dirMode
+	return txz
+}
+
+// WithPreservedExecutables keeps files that are executable in the archive
+// executable when their modes are normalized with WithNormalizedModes, by
+// adding an execute bit for each read bit of the normalized file mode.
+func (txz TarXZArchive) WithPreservedExecutables(preserve bool) TarXZArchive {
+	txz.modes.executables = preserve
+	return txz
+}
@@ -125,4 +125,51 @@ func testTarXZArchive(t *testing.T, context spec.G, it spec.S) {
 			})
 		})
 	})
+	context("List", func() {
+		var tarXZArchive vacation.TarXZArchive
+
+		it.Before(func() {
+			buffer := bytes.NewBuffer(nil)
+			xzw, err := xz.NewWriter(buffer)
+			Expect(err).NotTo(HaveOccurred())
+
+			tw := tar.NewWriter(xzw)
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "some-dir/", Mode: 0755, Typeflag: tar.TypeDir})).To(Succeed())
+			_, err = tw.Write(nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "some-dir/some-file", Mode: 0644, Size: int64(len("some-file"))})).To(Succeed())
+			_, err = tw.Write([]byte("some-file"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tw.WriteHeader(&tar.Header{Name: "symlink", Mode: 0777, Typeflag: tar.TypeSymlink, Linkname: "some-dir/some-file"})).To(Succeed())
+			_, err = tw.Write(nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tw.Close()).To(Succeed())
+			Expect(xzw.Close()).To(Succeed())
+
+			tarXZArchive = vacation.NewTarXZArchive(bytes.NewReader(buffer.Bytes()))
+		})
+
+		it("lists the entries in the archive", func() {
+			entries, err := tarXZArchive.List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(Equal([]vacation.Entry{
+				{Name: "some-dir/", Mode: os.ModeDir | 0755, Typeflag: tar.TypeDir},
+				{Name: "some-dir/some-file", Size: 9, Mode: 0644, Typeflag: tar.TypeReg},
+				{Name: "symlink", Mode: os.ModeSymlink | 0777, Typeflag: tar.TypeSymlink, Linkname: "some-dir/some-file"},
+			}))
+		})
+
+		context("failure cases", func() {
+			context("when it fails to create a xz reader", func() {
+				it("returns an error", func() {
+					_, err := vacation.NewTarXZArchive(bytes.NewBuffer([]byte(`something`))).List()
+					Expect(err).To(MatchError(ContainSubstring("failed to create xz reader")))
+				})
+			})
+		})
+	})
 }
@@ -0,0 +1,36 @@
+package vacation
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// An entryValidator checks the names of the entries of an archive for paths
+// that would escape the destination directory and for files that appear more
+// than once.
+type entryValidator map[string]bool
+
+// check validates the name of a single entry. Directories may appear more than
+// once as doing so does not lose any data during decompression.
+func (v entryValidator) check(name string, dir bool) error {
+	cleaned := filepath.Clean(name)
+	if cleaned == "." {
+		return nil
+	}
+
+	err := checkExtractPath(cleaned, "destination")
+	if err != nil {
+		return err
+	}
+
+	if dir {
+		return nil
+	}
+
+	if v[cleaned] {
+		return fmt.Errorf("duplicate entry %q", name)
+	}
+	v[cleaned] = true
+
+	return nil
+}
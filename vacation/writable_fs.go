@@ -0,0 +1,54 @@
+package vacation
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// A WritableFS is a file system that an archive can be decompressed into.
+// Implementations can be used to redirect or inspect the files an archive
+// extracts without writing them to disk.
+type WritableFS interface {
+	// MkdirAll creates a directory along with any necessary parents.
+	MkdirAll(path string, perm os.FileMode) error
+
+	// Create creates or truncates the file at the given path.
+	Create(path string, perm os.FileMode) (io.WriteCloser, error)
+
+	// Symlink creates newname as a symbolic link to oldname.
+	Symlink(oldname, newname string) error
+}
+
+// OSFileSystem is a WritableFS that writes to the host file system. It is used
+// when an archive is decompressed with Decompress.
+type OSFileSystem struct{}
+
+// MkdirAll creates a directory along with any necessary parents using
+// os.MkdirAll.
+func (OSFileSystem) MkdirAll(path string, perm os.FileMode) error {
+	return os.MkdirAll(path, perm)
+}
+
+// Create creates or truncates the file at the given path with the given
+// permissions.
+func (OSFileSystem) Create(path string, perm os.FileMode) (io.WriteCloser, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// Symlink creates newname as a symbolic link to oldname after checking that
+// the file that will be linked to exists.
+func (OSFileSystem) Symlink(oldname, newname string) error {
+	_, err := filepath.EvalSymlinks(linknameFullPath(newname, oldname))
+	if err != nil {
+		return fmt.Errorf("failed to evaluate symlink %s: %w", newname, err)
+	}
+
+	return os.Symlink(oldname, newname)
+}
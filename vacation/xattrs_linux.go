@@ -0,0 +1,33 @@
+package vacation
+
+import (
+	"archive/tar"
+	"fmt"
+	"sort"
+	"strings"
+	"syscall"
+)
+
+const xattrPAXPrefix = "SCHILY.xattr."
+
+// setXattrs applies each SCHILY.xattr.* PAX record in the header to the file
+// at the given path.
+func setXattrs(path string, hdr *tar.Header) error {
+	var keys []string
+	for key := range hdr.PAXRecords {
+		if strings.HasPrefix(key, xattrPAXPrefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		attr := strings.TrimPrefix(key, xattrPAXPrefix)
+		err := syscall.Setxattr(path, attr, []byte(hdr.PAXRecords[key]), 0)
+		if err != nil {
+			return fmt.Errorf("failed to set extended attribute %s on %s: %w", attr, path, err)
+		}
+	}
+
+	return nil
+}
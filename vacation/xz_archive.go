@@ -0,0 +1,134 @@
+package vacation
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/ulikunitz/xz"
+)
+
+// An XZArchive decompresses a single xz compressed file (one that is not
+// wrapped in a tar archive) from an input stream.
+type XZArchive struct {
+	reader io.Reader
+	path   string
+	name   string
+}
+
+// NewXZArchive returns a new XZArchive that reads from inputReader.
+func NewXZArchive(inputReader io.Reader) XZArchive {
+	return XZArchive{
+		reader: inputReader,
+		name:   "artifact",
+	}
+}
+
+// NewXZArchiveFromFile returns a new XZArchive that reads from the file at the
+// given path. The file is opened each time the archive is read and is closed
+// once reading completes.
+func NewXZArchiveFromFile(path string) XZArchive {
+	archive := NewXZArchive(nil)
+	archive.path = path
+	return archive
+}
+
+// Decompress reads from XZArchive and writes the decompressed file into the
+// destination directory under the name specified by the `XZArchive.WithName()`
+// option (or defaults to "artifact").
+func (xza XZArchive) Decompress(destination string) error {
+	source, closeSource, err := openSource(xza.reader, xza.path)
+	if err != nil {
+		return err
+	}
+	defer closeSource()
+	xza.reader = source
+
+	xzr, err := xz.NewReader(xza.reader)
+	if err != nil {
+		return fmt.Errorf("failed to create xz reader: %w", err)
+	}
+
+	return NewNopArchive(xzr).Decompress(filepath.Join(destination, xza.name))
+}
+
+// DecompressReader returns a reader of the decompressed contents of the
+// XZArchive, so that they can be streamed to another consumer without first
+// being written to disk.
+func (xza XZArchive) DecompressReader() (io.ReadCloser, error) {
+	source, closeSource, err := openSource(xza.reader, xza.path)
+	if err != nil {
+		return nil, err
+	}
+
+	xzr, err := xz.NewReader(source)
+	if err != nil {
+		closeSource()
+		return nil, fmt.Errorf("failed to create xz reader: %w", err)
+	}
+
+	return sourceReadCloser{Reader: xzr, close: closeSource}, nil
+}
+
+// sourceReadCloser closes the source of an archive once the reader of its
+// decompressed contents is closed.
+type sourceReadCloser struct {
+	io.Reader
+	close func()
+}
+
+func (r sourceReadCloser) Close() error {
+	r.close()
+	return nil
+}
+
+// List reads from XZArchive and reports the decompressed file as a single
+// regular file entry using the name specified by the `XZArchive.WithName()`
+// option.
+func (xza XZArchive) List() ([]Entry, error) {
+	source, closeSource, err := openSource(xza.reader, xza.path)
+	if err != nil {
+		return nil, err
+	}
+	defer closeSource()
+	xza.reader = source
+
+	xzr, err := xz.NewReader(xza.reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create xz reader: %w", err)
+	}
+
+	entries, err := NewNopArchive(xzr).List()
+	if err != nil {
+		return nil, err
+	}
+
+	entries[0].Name = xza.name
+
+	return entries, nil
+}
+
+// Validate decompresses the XZArchive without writing anything to disk,
+// returning an error if the stream is truncated or malformed.
+func (xza XZArchive) Validate() error {
+	source, closeSource, err := openSource(xza.reader, xza.path)
+	if err != nil {
+		return err
+	}
+	defer closeSource()
+	xza.reader = source
+
+	xzr, err := xz.NewReader(xza.reader)
+	if err != nil {
+		return fmt.Errorf("failed to create xz reader: %w", err)
+	}
+
+	return NewNopArchive(xzr).Validate()
+}
+
+// WithName provides a way of overriding the name of the file
+// that the decompressed file will be copied into.
+func (xza XZArchive) WithName(name string) XZArchive {
+	xza.name = name
+	return xza
+}
@@ -0,0 +1,147 @@
+package vacation_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/paketo-buildpacks/packit/vacation"
+	"github.com/sclevine/spec"
+	"github.com/ulikunitz/xz"
+
+	. "github.com/onsi/gomega"
+)
+
+func testXZArchive(t *testing.T, context spec.G, it spec.S) {
+	var Expect = NewWithT(t).Expect
+
+	context("Decompress", func() {
+		var (
+			tempDir   string
+			xzArchive vacation.XZArchive
+		)
+
+		it.Before(func() {
+			var err error
+			tempDir, err = os.MkdirTemp("", "vacation")
+			Expect(err).NotTo(HaveOccurred())
+
+			buffer := bytes.NewBuffer(nil)
+			xzw, err := xz.NewWriter(buffer)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = xzw.Write([]byte("some-binary-contents"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(xzw.Close()).To(Succeed())
+
+			xzArchive = vacation.NewXZArchive(bytes.NewReader(buffer.Bytes()))
+		})
+
+		it.After(func() {
+			Expect(os.RemoveAll(tempDir)).To(Succeed())
+		})
+
+		it("writes the decompressed file into the destination", func() {
+			err := xzArchive.Decompress(tempDir)
+			Expect(err).NotTo(HaveOccurred())
+
+			content, err := os.ReadFile(filepath.Join(tempDir, "artifact"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content).To(Equal([]byte("some-binary-contents")))
+		})
+
+		context("when given a name", func() {
+			it("writes the decompressed file into the destination with that name", func() {
+				err := xzArchive.WithName("some-binary").Decompress(tempDir)
+				Expect(err).NotTo(HaveOccurred())
+
+				content, err := os.ReadFile(filepath.Join(tempDir, "some-binary"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(content).To(Equal([]byte("some-binary-contents")))
+			})
+		})
+
+		context("failure cases", func() {
+			context("when it fails to create an xz reader", func() {
+				it("returns an error", func() {
+					err := vacation.NewXZArchive(bytes.NewBuffer([]byte(`something`))).Decompress(tempDir)
+					Expect(err).To(MatchError(ContainSubstring("failed to create xz reader")))
+				})
+			})
+
+			context("when the destination file cannot be created", func() {
+				it("returns an error", func() {
+					err := xzArchive.Decompress("/no/such/path")
+					Expect(err).To(MatchError(ContainSubstring("no such file or directory")))
+				})
+			})
+		})
+	})
+	context("List", func() {
+		var xzArchive vacation.XZArchive
+
+		it.Before(func() {
+			buffer := bytes.NewBuffer(nil)
+			xzw, err := xz.NewWriter(buffer)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = xzw.Write([]byte("some-binary-contents"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(xzw.Close()).To(Succeed())
+
+			xzArchive = vacation.NewXZArchive(bytes.NewReader(buffer.Bytes())).WithName("some-binary")
+		})
+
+		it("reports the decompressed file as a single entry", func() {
+			entries, err := xzArchive.List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(Equal([]vacation.Entry{
+				{Name: "some-binary", Size: 20, Typeflag: tar.TypeReg},
+			}))
+		})
+
+		context("failure cases", func() {
+			context("when it fails to create an xz reader", func() {
+				it("returns an error", func() {
+					_, err := vacation.NewXZArchive(bytes.NewBuffer([]byte(`something`))).List()
+					Expect(err).To(MatchError(ContainSubstring("failed to create xz reader")))
+				})
+			})
+		})
+	})
+	context("DecompressReader", func() {
+		it("returns a reader of the decompressed contents", func() {
+			buffer := bytes.NewBuffer(nil)
+			xzw, err := xz.NewWriter(buffer)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = xzw.Write([]byte("some-binary-contents"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(xzw.Close()).To(Succeed())
+
+			reader, err := vacation.NewXZArchive(buffer).DecompressReader()
+			Expect(err).NotTo(HaveOccurred())
+
+			content, err := io.ReadAll(reader)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(content).To(Equal([]byte("some-binary-contents")))
+
+			Expect(reader.Close()).To(Succeed())
+		})
+
+		context("failure cases", func() {
+			context("when it fails to create an xz reader", func() {
+				it("returns an error", func() {
+					_, err := vacation.NewXZArchive(bytes.NewBuffer([]byte(`something`))).DecompressReader()
+					Expect(err).To(MatchError(ContainSubstring("failed to create xz reader")))
+				})
+			})
+		})
+	})
+}
@@ -1,22 +1,38 @@
 package vacation
 
 import (
+	"archive/tar"
 	"archive/zip"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 )
 
 // A ZipArchive decompresses zip files from an input stream.
 type ZipArchive struct {
 	reader io.Reader
+	path   string
+	archiveOptions
 }
 
 // NewZipArchive returns a new ZipArchive that reads from inputReader.
 func NewZipArchive(inputReader io.Reader) ZipArchive {
-	return ZipArchive{reader: inputReader}
+	return ZipArchive{
+		reader:         inputReader,
+		archiveOptions: archiveOptions{mode: os.ModePerm},
+	}
+}
+
+// NewZipArchiveFromFile returns a new ZipArchive that reads from the file at
+// the given path. The file is opened each time the archive is read and is
+// closed once reading completes.
+func NewZipArchiveFromFile(path string) ZipArchive {
+	archive := NewZipArchive(nil)
+	archive.path = path
+	return archive
 }
 
 // Decompress reads from ZipArchive and writes files into the destination
@@ -32,24 +48,34 @@ func (z ZipArchive) Decompress(destination string) error {
 
 	var symlinkHeaders []header
 
-	// Use an os.File to buffer the zip contents. This is needed because
-	// zip.NewReader requires an io.ReaderAt so that it can jump around within
-	// the file as it decompresses.
-	buffer, err := os.CreateTemp("", "")
-	if err != nil {
-		return err
+	// Struct and slice to collect the modes recorded for directory entries so
+	// that they can be applied after all files have been created. A directory
+	// may otherwise be created implicitly by a file inside of it, or lose write
+	// permission before the files inside of it are written.
+	type directory struct {
+		path string
+		mode os.FileMode
 	}
-	defer os.Remove(buffer.Name())
 
-	size, err := io.Copy(buffer, z.reader)
+	var directories []directory
+
+	// These collect the regular files, along with their archived modes, and the
+	// directories that were extracted so that their modes can be normalized.
+	extractedFiles := map[string]os.FileMode{}
+	var extractedDirs []string
+
+	// This map keeps track of the entry that was extracted to each path when
+	// flattening so that name collisions can be reported.
+	flattened := map[string]string{}
+
+	zr, closeReader, err := z.newReader()
 	if err != nil {
 		return err
 	}
+	defer closeReader()
 
-	zr, err := zip.NewReader(buffer, size)
-	if err != nil {
-		return fmt.Errorf("failed to create zip reader: %w", err)
-	}
+	release := acquire(z.limiter)
+	defer release()
 
 	for _, f := range zr.File {
 		// Clean the name in the header to prevent './filename' being stripped to
@@ -67,12 +93,34 @@ func (z ZipArchive) Decompress(destination string) error {
 
 		path := filepath.Join(destination, name)
 
+		if z.flatten {
+			if f.FileInfo().IsDir() {
+				continue
+			}
+
+			path = filepath.Join(destination, filepath.Base(name))
+			if previous, ok := flattened[path]; ok {
+				return fmt.Errorf("failed to flatten archive: %s and %s both extract to %s", previous, name, path)
+			}
+			flattened[path] = name
+		}
+
 		switch {
 		case f.FileInfo().IsDir():
 			err = os.MkdirAll(path, os.ModePerm)
 			if err != nil {
 				return fmt.Errorf("failed to unzip directory: %w", err)
 			}
+
+			extractedDirs = append(extractedDirs, path)
+
+			// Only entries written by a Unix or macOS creator record a real mode.
+			// Others, such as those written by a FAT creator, report a synthesized
+			// mode without execute bits, so they keep the default mode of 0755, as
+			// do entries whose mode would prevent the directory from being entered.
+			if mode := f.Mode().Perm(); hasUnixMode(f) && mode&0100 != 0 {
+				directories = append(directories, directory{path: path, mode: mode})
+			}
 		case f.FileInfo().Mode()&os.ModeSymlink != 0:
 			fd, err := f.Open()
 			if err != nil {
@@ -83,6 +131,7 @@ func (z ZipArchive) Decompress(destination string) error {
 			if err != nil {
 				return err
 			}
+			fd.Close()
 
 			// Collect all of the headers for symlinks so that they can be verified
 			// after all other files are written
@@ -93,27 +142,17 @@ func (z ZipArchive) Decompress(destination string) error {
 			})
 
 		default:
-			err = os.MkdirAll(filepath.Dir(path), os.ModePerm)
+			err = os.MkdirAll(filepath.Dir(path), z.mode)
 			if err != nil {
 				return fmt.Errorf("failed to unzip directory that was part of file path: %w", err)
 			}
 
-			dst, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
-			if err != nil {
-				return fmt.Errorf("failed to unzip file: %w", err)
-			}
-			defer dst.Close()
-
-			src, err := f.Open()
+			err = unzipFile(f, path)
 			if err != nil {
 				return err
 			}
-			defer src.Close()
 
-			_, err = io.Copy(dst, src)
-			if err != nil {
-				return err
-			}
+			extractedFiles[path] = f.Mode()
 		}
 	}
 
@@ -156,5 +195,254 @@ func (z ZipArchive) Decompress(destination string) error {
 		}
 	}
 
+	// Apply the directory modes from the deepest directory to the shallowest so
+	// that a restrictive mode on a parent does not prevent the modes of its
+	// children from being set.
+	sort.SliceStable(directories, func(i, j int) bool {
+		return strings.Count(directories[i].path, string(filepath.Separator)) > strings.Count(directories[j].path, string(filepath.Separator))
+	})
+
+	for _, d := range directories {
+		err = os.Chmod(d.path, d.mode)
+		if err != nil {
+			return fmt.Errorf("failed to set directory mode: %w", err)
+		}
+	}
+
+	err = z.modes.apply(destination, extractedFiles, extractedDirs)
+	if err != nil {
+		return err
+	}
+
+	return chmodRoot(destination, z.rootMode)
+}
+
+// hasUnixMode reports whether the zip entry was written by a Unix or macOS
+// creator, which record the mode of the entry in its external attributes.
+func hasUnixMode(f *zip.File) bool {
+	switch f.CreatorVersion >> 8 {
+	case 3, 19:
+		return true
+	default:
+		return false
+	}
+}
+
+// newReader returns a zip reader for the contents of the ZipArchive along with
+// a function that releases the resources it holds. A ZipArchive created from a
+// file reads the file directly. Otherwise, the input stream is buffered to a
+// temporary file because zip.NewReader requires an io.ReaderAt so that it can
+// jump around within the file as it decompresses.
+func (z ZipArchive) newReader() (*zip.Reader, func(), error) {
+	if z.path != "" {
+		file, err := os.Open(z.path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open archive: %w", err)
+		}
+
+		info, err := file.Stat()
+		if err != nil {
+			file.Close()
+			return nil, nil, fmt.Errorf("failed to open archive: %w", err)
+		}
+
+		zr, err := zip.NewReader(file, info.Size())
+		if err != nil {
+			file.Close()
+			return nil, nil, fmt.Errorf("failed to create zip reader: %w", err)
+		}
+
+		return zr, func() { file.Close() }, nil
+	}
+
+	buffer, err := os.CreateTemp("", "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	release := func() {
+		buffer.Close()
+		os.Remove(buffer.Name())
+	}
+
+	size, err := io.Copy(buffer, z.reader)
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+
+	zr, err := zip.NewReader(buffer, size)
+	if err != nil {
+		release()
+		return nil, nil, fmt.Errorf("failed to create zip reader: %w", err)
+	}
+
+	return zr, release, nil
+}
+
+// unzipFile streams the contents of the zip member into a file at the given
+// path. The member is closed before returning so that archives with many
+// members do not hold every file open until decompression completes.
+func unzipFile(f *zip.File, path string) error {
+	dst, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, f.Mode())
+	if err != nil {
+		return fmt.Errorf("failed to unzip file: %w", err)
+	}
+	defer dst.Close()
+
+	src, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	_, err = io.Copy(dst, src)
+	if err != nil {
+		return err
+	}
+
+	return dst.Close()
+}
+
+// Validate reads every entry of the ZipArchive without writing anything to
+// disk. It returns an error if the archive is truncated or malformed, if the
+// contents of an entry do not match its checksum, if an entry would be
+// extracted outside of the destination directory, or if a file appears more
+// than once.
+func (z ZipArchive) Validate() error {
+	zr, closeReader, err := z.newReader()
+	if err != nil {
+		return err
+	}
+	defer closeReader()
+
+	validator := entryValidator{}
+	for _, f := range zr.File {
+		err = validator.check(f.Name, f.FileInfo().IsDir())
+		if err != nil {
+			return err
+		}
+
+		err = validateZipFile(f)
+		if err != nil {
+			return fmt.Errorf("failed to read zip entry %q: %w", f.Name, err)
+		}
+	}
+
 	return nil
 }
+
+// validateZipFile reads the contents of the zip member so that its checksum
+// is verified.
+func validateZipFile(f *zip.File) error {
+	src, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	_, err = io.Copy(io.Discard, src)
+	return err
+}
+
+// withOptions replaces the shared extraction options with the given options.
+func (z ZipArchive) withOptions(options archiveOptions) ZipArchive {
+	z.archiveOptions = options
+	return z
+}
+
+// WithDestinationMode sets the permissions used for directories that are
+// created implicitly because a file in the archive is nested inside of them.
+// Defaults to os.ModePerm.
+func (z ZipArchive) WithDestinationMode(mode os.FileMode) ZipArchive {
+	z.mode = mode
+	return z
+}
+
+// List reads from ZipArchive and returns the entries it contains without
+// writing anything to disk.
+func (z ZipArchive) List() ([]Entry, error) {
+	zr, closeReader, err := z.newReader()
+	if err != nil {
+		return nil, err
+	}
+	defer closeReader()
+
+	var entries []Entry
+	for _, f := range zr.File {
+		entry := Entry{
+			Name:     f.Name,
+			Size:     int64(f.UncompressedSize64),
+			Mode:     f.Mode(),
+			Typeflag: tar.TypeReg,
+		}
+
+		switch {
+		case f.FileInfo().IsDir():
+			entry.Typeflag = tar.TypeDir
+		case f.Mode()&os.ModeSymlink != 0:
+			fd, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+
+			linkname, err := io.ReadAll(fd)
+			if err != nil {
+				return nil, err
+			}
+			fd.Close()
+
+			entry.Typeflag = tar.TypeSymlink
+			entry.Linkname = string(linkname)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// WithFlatten discards the directory structure of the archive when enabled,
+// extracting every file directly into the destination. Decompression fails if
+// two files in the archive share the same name.
+func (z ZipArchive) WithFlatten(flatten bool) ZipArchive {
+	z.flatten = flatten
+	return z
+}
+
+// WithConcurrencyLimiter bounds the number of extractions that write to disk
+// at the same time. Each extraction holds a slot on the given channel while it
+// writes files, so the capacity of the channel is the maximum number of
+// concurrent extractions among all archives sharing it. A nil channel imposes
+// no limit.
+func (z ZipArchive) WithConcurrencyLimiter(limiter chan struct{}) ZipArchive {
+	z.limiter = limiter
+	return z
+}
+
+// WithRootMode sets the permissions of the destination directory once
+// decompression has completed, regardless of the permissions recorded in the
+// archive.
+func (z ZipArchive) WithRootMode(mode os.FileMode) ZipArchive {
+	z.rootMode = mode
+	return z
+}
+
+// WithNormalizedModes sets the permissions of every extracted regular file to
+// fileMode and of every extracted directory to dirMode once decompression has
+// completed, regardless of the permissions recorded in the archive or the
+// umask, so that the extracted files are reproducible. A mode of zero leaves
+// the permissions of that kind of entry unchanged.
+func (z ZipArchive) WithNormalizedModes(fileMode, dirMode os.FileMode) ZipArchive {
+	z.modes.file = fileMode
+	z.modes.dir = dirMode
+	return z
+}
+
+// WithPreservedExecutables keeps files that are executable in the archive
+// executable when their modes are normalized with WithNormalizedModes, by
+// adding an execute bit for each read bit of the normalized file mode.
+func (z ZipArchive) WithPreservedExecutables(preserve bool) ZipArchive {
+	z.modes.executables = preserve
+	return z
+}
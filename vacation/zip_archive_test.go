@@ -1,9 +1,12 @@
 package vacation_test
 
 import (
+	"archive/tar"
 	"archive/zip"
 	"bytes"
+	"encoding/binary"
 	"fmt"
+	"hash/crc32"
 	"os"
 	"path/filepath"
 	"testing"
@@ -113,6 +116,228 @@ func testZipArchive(t *testing.T, context spec.G, it spec.S) {
 			Expect(data).To(Equal([]byte("nested file")))
 		})
 
+		context("when flattening the archive", func() {
+			it.Before(func() {
+				buffer := bytes.NewBuffer(nil)
+				zw := zip.NewWriter(buffer)
+
+				_, err := zw.Create("some-dir/")
+				Expect(err).NotTo(HaveOccurred())
+
+				for _, name := range []string{filepath.Join("some-dir", "some-other-dir", "some-file"), "first"} {
+					fileHeader := &zip.FileHeader{Name: name}
+					fileHeader.SetMode(0644)
+
+					f, err := zw.CreateHeader(fileHeader)
+					Expect(err).NotTo(HaveOccurred())
+
+					_, err = f.Write([]byte(name))
+					Expect(err).NotTo(HaveOccurred())
+				}
+
+				Expect(zw.Close()).To(Succeed())
+
+				zipArchive = vacation.NewZipArchive(bytes.NewReader(buffer.Bytes())).WithFlatten(true)
+			})
+
+			it("unpackages every file directly into the path", func() {
+				err := zipArchive.Decompress(tempDir)
+				Expect(err).ToNot(HaveOccurred())
+
+				files, err := filepath.Glob(fmt.Sprintf("%s/*", tempDir))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(files).To(ConsistOf([]string{
+					filepath.Join(tempDir, "first"),
+					filepath.Join(tempDir, "some-file"),
+				}))
+
+				data, err := os.ReadFile(filepath.Join(tempDir, "some-file"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(data)).To(Equal(filepath.Join("some-dir", "some-other-dir", "some-file")))
+			})
+		})
+
+		context("when the archive uses Zip64 records", func() {
+			var content []byte
+
+			it.Before(func() {
+				content = bytes.Repeat([]byte("some-content\n"), 1024*1024)
+
+				zipArchive = vacation.NewZipArchive(bytes.NewReader(zip64Archive("some-dir/some-file", content)))
+			})
+
+			it("unpackages the member using its Zip64 sizes", func() {
+				err := zipArchive.Decompress(tempDir)
+				Expect(err).ToNot(HaveOccurred())
+
+				info, err := os.Stat(filepath.Join(tempDir, "some-dir", "some-file"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Size()).To(Equal(int64(len(content))))
+				Expect(info.Mode()).To(Equal(os.FileMode(0644)))
+
+				data, err := os.ReadFile(filepath.Join(tempDir, "some-dir", "some-file"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(bytes.Equal(data, content)).To(BeTrue())
+			})
+		})
+
+		context("when given a destination mode", func() {
+			it.Before(func() {
+				buffer := bytes.NewBuffer(nil)
+				zw := zip.NewWriter(buffer)
+
+				fileHeader := &zip.FileHeader{Name: filepath.Join("some-dir", "some-other-dir", "some-file")}
+				fileHeader.SetMode(0644)
+
+				nestedFile, err := zw.CreateHeader(fileHeader)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = nestedFile.Write([]byte("nested file"))
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(zw.Close()).To(Succeed())
+
+				zipArchive = vacation.NewZipArchive(bytes.NewReader(buffer.Bytes())).WithDestinationMode(0750)
+			})
+
+			it("creates the implied directories with that mode", func() {
+				err := zipArchive.Decompress(tempDir)
+				Expect(err).ToNot(HaveOccurred())
+
+				info, err := os.Stat(filepath.Join(tempDir, "some-dir", "some-other-dir"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Mode().Perm()).To(Equal(os.FileMode(0750)))
+
+				info, err = os.Stat(filepath.Join(tempDir, "some-dir"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Mode().Perm()).To(Equal(os.FileMode(0750)))
+			})
+		})
+
+		context("when directory entries record a mode", func() {
+			it.Before(func() {
+				buffer := bytes.NewBuffer(nil)
+				zw := zip.NewWriter(buffer)
+
+				// The file is listed before its directory so that the directory is
+				// created implicitly when the file is written.
+				fileHeader := &zip.FileHeader{Name: "private-dir/some-file"}
+				fileHeader.SetMode(0644)
+
+				f, err := zw.CreateHeader(fileHeader)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = f.Write([]byte("some-file"))
+				Expect(err).NotTo(HaveOccurred())
+
+				dirHeader := &zip.FileHeader{Name: "private-dir/"}
+				dirHeader.SetMode(os.ModeDir | 0700)
+
+				_, err = zw.CreateHeader(dirHeader)
+				Expect(err).NotTo(HaveOccurred())
+
+				// The read-only directory is listed before the file inside of it, so
+				// its mode can only be applied once the file has been written.
+				dirHeader = &zip.FileHeader{Name: "private-dir/read-only-dir/"}
+				dirHeader.SetMode(os.ModeDir | 0500)
+
+				_, err = zw.CreateHeader(dirHeader)
+				Expect(err).NotTo(HaveOccurred())
+
+				fileHeader = &zip.FileHeader{Name: "private-dir/read-only-dir/some-file"}
+				fileHeader.SetMode(0644)
+
+				f, err = zw.CreateHeader(fileHeader)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = f.Write([]byte("some-other-file"))
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(zw.Close()).To(Succeed())
+
+				zipArchive = vacation.NewZipArchive(bytes.NewReader(buffer.Bytes()))
+			})
+
+			it.After(func() {
+				Expect(os.Chmod(filepath.Join(tempDir, "private-dir", "read-only-dir"), os.ModePerm)).To(Succeed())
+			})
+
+			it("applies the recorded modes once the files inside have been written", func() {
+				err := zipArchive.Decompress(tempDir)
+				Expect(err).NotTo(HaveOccurred())
+
+				info, err := os.Stat(filepath.Join(tempDir, "private-dir"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Mode().Perm()).To(Equal(os.FileMode(0700)))
+
+				info, err = os.Stat(filepath.Join(tempDir, "private-dir", "read-only-dir"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Mode().Perm()).To(Equal(os.FileMode(0500)))
+
+				content, err := os.ReadFile(filepath.Join(tempDir, "private-dir", "some-file"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(Equal("some-file"))
+
+				content, err = os.ReadFile(filepath.Join(tempDir, "private-dir", "read-only-dir", "some-file"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(Equal("some-other-file"))
+			})
+
+			context("when given normalized modes", func() {
+				it("applies the normalized modes in place of the recorded modes", func() {
+					err := zipArchive.WithNormalizedModes(0640, 0750).WithPreservedExecutables(true).Decompress(tempDir)
+					Expect(err).NotTo(HaveOccurred())
+
+					for _, path := range []string{"private-dir", filepath.Join("private-dir", "read-only-dir")} {
+						info, err := os.Stat(filepath.Join(tempDir, path))
+						Expect(err).NotTo(HaveOccurred())
+						Expect(info.Mode().Perm()).To(Equal(os.FileMode(0750)), path)
+					}
+
+					for _, path := range []string{filepath.Join("private-dir", "some-file"), filepath.Join("private-dir", "read-only-dir", "some-file")} {
+						info, err := os.Stat(filepath.Join(tempDir, path))
+						Expect(err).NotTo(HaveOccurred())
+						Expect(info.Mode().Perm()).To(Equal(os.FileMode(0640)), path)
+					}
+				})
+			})
+		})
+
+		context("when directory entries come from a FAT creator", func() {
+			it.Before(func() {
+				buffer := bytes.NewBuffer(nil)
+				zw := zip.NewWriter(buffer)
+
+				// Entries created without a mode are written by a FAT creator and
+				// report a mode of 0666 for directories.
+				_, err := zw.Create("fat-dir/")
+				Expect(err).NotTo(HaveOccurred())
+
+				f, err := zw.Create("fat-dir/some-file")
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = f.Write([]byte("some-file"))
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(zw.Close()).To(Succeed())
+
+				zipArchive = vacation.NewZipArchive(bytes.NewReader(buffer.Bytes()))
+			})
+
+			it("keeps the default directory mode", func() {
+				err := zipArchive.Decompress(tempDir)
+				Expect(err).NotTo(HaveOccurred())
+
+				info, err := os.Stat(filepath.Join(tempDir, "fat-dir"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Mode().Perm()).To(Equal(os.FileMode(0755)))
+
+				content, err := os.ReadFile(filepath.Join(tempDir, "fat-dir", "some-file"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(Equal("some-file"))
+			})
+		})
+
 		context("failure cases", func() {
 			context("when it fails to create a zip reader", func() {
 				it("returns an error", func() {
@@ -123,6 +348,30 @@ func testZipArchive(t *testing.T, context spec.G, it spec.S) {
 				})
 			})
 
+			context("when flattening results in a name collision", func() {
+				it.Before(func() {
+					buffer := bytes.NewBuffer(nil)
+					zw := zip.NewWriter(buffer)
+
+					for _, name := range []string{filepath.Join("some-dir", "some-file"), filepath.Join("other-dir", "some-file")} {
+						f, err := zw.Create(name)
+						Expect(err).NotTo(HaveOccurred())
+
+						_, err = f.Write([]byte(name))
+						Expect(err).NotTo(HaveOccurred())
+					}
+
+					Expect(zw.Close()).To(Succeed())
+
+					zipArchive = vacation.NewZipArchive(bytes.NewReader(buffer.Bytes())).WithFlatten(true)
+				})
+
+				it("returns an error", func() {
+					err := zipArchive.Decompress(tempDir)
+					Expect(err).To(MatchError(fmt.Sprintf("failed to flatten archive: some-dir/some-file and other-dir/some-file both extract to %s", filepath.Join(tempDir, "some-file"))))
+				})
+			})
+
 			context("when a file is not inside of the destination director (Zip Slip)", func() {
 				var buffer *bytes.Buffer
 				it.Before(func() {
@@ -288,4 +537,168 @@ func testZipArchive(t *testing.T, context spec.G, it spec.S) {
 			})
 		})
 	})
+	context("List", func() {
+		var zipArchive vacation.ZipArchive
+
+		it.Before(func() {
+			buffer := bytes.NewBuffer(nil)
+			zw := zip.NewWriter(buffer)
+
+			fileHeader := &zip.FileHeader{Name: "some-dir/"}
+			fileHeader.SetMode(os.ModeDir | 0755)
+
+			_, err := zw.CreateHeader(fileHeader)
+			Expect(err).NotTo(HaveOccurred())
+
+			fileHeader = &zip.FileHeader{Name: "some-dir/some-file"}
+			fileHeader.SetMode(0644)
+
+			file, err := zw.CreateHeader(fileHeader)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = file.Write([]byte("some-file"))
+			Expect(err).NotTo(HaveOccurred())
+
+			fileHeader = &zip.FileHeader{Name: "symlink"}
+			fileHeader.SetMode(0777 | os.ModeSymlink)
+
+			symlink, err := zw.CreateHeader(fileHeader)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = symlink.Write([]byte("some-dir/some-file"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(zw.Close()).To(Succeed())
+
+			zipArchive = vacation.NewZipArchive(bytes.NewReader(buffer.Bytes()))
+		})
+
+		it("lists the entries in the archive", func() {
+			entries, err := zipArchive.List()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(Equal([]vacation.Entry{
+				{Name: "some-dir/", Mode: os.ModeDir | 0755, Typeflag: tar.TypeDir},
+				{Name: "some-dir/some-file", Size: 9, Mode: 0644, Typeflag: tar.TypeReg},
+				{Name: "symlink", Size: 18, Mode: os.ModeSymlink | 0777, Typeflag: tar.TypeSymlink, Linkname: "some-dir/some-file"},
+			}))
+		})
+
+		context("failure cases", func() {
+			context("when it fails to create a zip reader", func() {
+				it("returns an error", func() {
+					_, err := vacation.NewZipArchive(bytes.NewBuffer([]byte(`something`))).List()
+					Expect(err).To(MatchError(ContainSubstring("failed to create zip reader")))
+				})
+			})
+		})
+	})
+
+	context("Validate", func() {
+		it("succeeds for a well-formed archive", func() {
+			buffer := bytes.NewBuffer(nil)
+			zw := zip.NewWriter(buffer)
+
+			_, err := zw.Create("some-dir/")
+			Expect(err).NotTo(HaveOccurred())
+
+			f, err := zw.Create("some-dir/some-file")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = f.Write([]byte("some-content"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(zw.Close()).To(Succeed())
+
+			Expect(vacation.NewZipArchive(buffer).Validate()).To(Succeed())
+		})
+
+		context("failure cases", func() {
+			context("when an entry would be extracted outside of the destination", func() {
+				it("returns an error", func() {
+					buffer := bytes.NewBuffer(nil)
+					zw := zip.NewWriter(buffer)
+
+					_, err := zw.Create(filepath.Join("..", "some-file"))
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(zw.Close()).To(Succeed())
+
+					err = vacation.NewZipArchive(buffer).Validate()
+					Expect(err).To(MatchError(ContainSubstring("illegal file path")))
+				})
+			})
+
+			context("when a file appears more than once", func() {
+				it("returns an error", func() {
+					buffer := bytes.NewBuffer(nil)
+					zw := zip.NewWriter(buffer)
+
+					for i := 0; i < 2; i++ {
+						_, err := zw.Create("some-file")
+						Expect(err).NotTo(HaveOccurred())
+					}
+
+					Expect(zw.Close()).To(Succeed())
+
+					err := vacation.NewZipArchive(buffer).Validate()
+					Expect(err).To(MatchError(`duplicate entry "some-file"`))
+				})
+			})
+
+			context("when the archive is truncated", func() {
+				it("returns an error", func() {
+					err := vacation.NewZipArchive(bytes.NewBufferString("PK\x03\x04 truncated")).Validate()
+					Expect(err).To(MatchError(ContainSubstring("failed to create zip reader")))
+				})
+			})
+		})
+	})
+}
+
+// zip64Archive builds a zip archive containing a single stored file whose
+// sizes are only recorded in Zip64 extra fields, and whose end of central
+// directory is only described by a Zip64 record, as happens for archives
+// with members larger than 4GB.
+func zip64Archive(name string, content []byte) []byte {
+	buffer := bytes.NewBuffer(nil)
+	write := func(values ...interface{}) {
+		for _, value := range values {
+			err := binary.Write(buffer, binary.LittleEndian, value)
+			if err != nil {
+				panic(err)
+			}
+		}
+	}
+
+	size := uint64(len(content))
+	checksum := crc32.ChecksumIEEE(content)
+	extra := func() {
+		write(uint16(0x0001), uint16(16), size, size)
+	}
+
+	// Local file header
+	write(uint32(0x04034b50), uint16(45), uint16(0), uint16(zip.Store), uint16(0), uint16(0))
+	write(checksum, uint32(0xffffffff), uint32(0xffffffff), uint16(len(name)), uint16(20))
+	buffer.WriteString(name)
+	extra()
+	buffer.Write(content)
+
+	// Central directory header
+	directoryOffset := uint64(buffer.Len())
+	write(uint32(0x02014b50), uint16(3<<8|45), uint16(45), uint16(0), uint16(zip.Store), uint16(0), uint16(0))
+	write(checksum, uint32(0xffffffff), uint32(0xffffffff), uint16(len(name)), uint16(20), uint16(0))
+	write(uint16(0), uint16(0), uint32(0100644<<16), uint32(0))
+	buffer.WriteString(name)
+	extra()
+	directorySize := uint64(buffer.Len()) - directoryOffset
+
+	// Zip64 end of central directory record and locator
+	recordOffset := uint64(buffer.Len())
+	write(uint32(0x06064b50), uint64(44), uint16(45), uint16(45), uint32(0), uint32(0))
+	write(uint64(1), uint64(1), directorySize, directoryOffset)
+	write(uint32(0x07064b50), uint32(0), recordOffset, uint32(1))
+
+	// End of central directory record
+	write(uint32(0x06054b50), uint16(0), uint16(0), uint16(0xffff), uint16(0xffff), uint32(0xffffffff), uint32(0xffffffff), uint16(0))
+
+	return buffer.Bytes()
 }